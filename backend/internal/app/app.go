@@ -12,6 +12,9 @@ import (
 	"github.com/ktruedat/healthisis/backend/internal/database"
 	"github.com/ktruedat/healthisis/backend/internal/pkg/common"
 	"github.com/ktruedat/healthisis/backend/internal/pkg/log"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+	"github.com/ktruedat/healthisis/backend/internal/repository/clickhouse"
+	"github.com/ktruedat/healthisis/backend/internal/repository/memory"
 	"github.com/ktruedat/healthisis/backend/internal/server"
 )
 
@@ -19,7 +22,7 @@ import (
 type App struct {
 	config *config.Config
 	server *server.Server
-	db     *database.DB
+	db     *database.DB // nil unless the ClickHouse driver is used
 	logger log.Logger
 }
 
@@ -33,14 +36,28 @@ func New(configPath string) (*App, error) {
 
 	logger := log.NewLogger(common.DevelopmentEnvironment)
 
-	// Initialize database
-	db, err := database.NewClickHouseDB(cfg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	// Initialize storage
+	var db *database.DB
+	var store *repository.Store
+	switch cfg.Database.Driver {
+	case config.DriverClickhouse:
+		db, err = database.NewClickHouseDB(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize database: %w", err)
+		}
+		store = clickhouse.NewStore(db)
+	case config.DriverMemory:
+		store, err = memory.LoadStore(cfg.Database.Memory.SeedFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize in-memory store: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
 	}
+	logger.Info("Storage initialized", "driver", cfg.Database.Driver)
 
 	// Initialize server
-	srv := server.New(cfg, db, logger)
+	srv := server.New(cfg, store, logger)
 
 	return &App{
 		config: cfg,
@@ -85,6 +102,9 @@ func (a *App) Run() error {
 
 // Close cleans up resources used by the application
 func (a *App) Close() error {
+	if a.db == nil {
+		return nil
+	}
 	if err := a.db.Close(); err != nil {
 		return fmt.Errorf("error closing database connection: %w", err)
 	}
@@ -13,6 +13,12 @@ type Config struct {
 	Server   ServerConfig   `yaml:"server"`
 	Database DatabaseConfig `yaml:"database"`
 	CORS     CORSConfig     `yaml:"cors"`
+	Demo     DemoConfig     `yaml:"demo"`
+}
+
+// DemoConfig controls demo mode, the only mode in which synthetic data is served
+type DemoConfig struct {
+	Enabled bool `yaml:"enabled"`
 }
 
 // ServerConfig holds all the server-related config
@@ -22,9 +28,17 @@ type ServerConfig struct {
 	Timeout int    `yaml:"timeout"`
 }
 
+// Database drivers selectable through DatabaseConfig.Driver
+const (
+	DriverClickhouse = "clickhouse"
+	DriverMemory     = "memory"
+)
+
 // DatabaseConfig holds all the database-related config
 type DatabaseConfig struct {
+	Driver     string           `yaml:"driver"`
 	Clickhouse ClickhouseConfig `yaml:"clickhouse"`
+	Memory     MemoryConfig     `yaml:"memory"`
 }
 
 // ClickhouseConfig holds the configuration specific to ClickHouse
@@ -39,6 +53,11 @@ type ClickhouseConfig struct {
 	ConnMaxLifetime  int    `yaml:"conn_max_lifetime"`
 }
 
+// MemoryConfig holds the configuration specific to the in-memory backend
+type MemoryConfig struct {
+	SeedFile string `yaml:"seed_file"`
+}
+
 // CORSConfig holds all the CORS-related config
 type CORSConfig struct {
 	AllowedOrigins   []string `yaml:"allowed_origins"`
@@ -75,6 +94,10 @@ func Load(path string) (*Config, error) {
 	// Apply environment variable overrides
 	applyEnvOverrides(&cfg)
 
+	if cfg.Database.Driver == "" {
+		cfg.Database.Driver = DriverClickhouse
+	}
+
 	return &cfg, nil
 }
 
@@ -90,7 +113,19 @@ func applyEnvOverrides(cfg *Config) {
 		cfg.Server.Env = env
 	}
 
+	if demo := os.Getenv("DEMO_MODE"); demo != "" {
+		if enabled, err := strconv.ParseBool(demo); err == nil {
+			cfg.Demo.Enabled = enabled
+		}
+	}
+
 	// Database settings
+	if driver := os.Getenv("DATABASE_DRIVER"); driver != "" {
+		cfg.Database.Driver = driver
+	}
+	if seed := os.Getenv("MEMORY_SEED_FILE"); seed != "" {
+		cfg.Database.Memory.SeedFile = seed
+	}
 	if host := os.Getenv("CLICKHOUSE_HOST"); host != "" {
 		cfg.Database.Clickhouse.Host = host
 	}
@@ -0,0 +1,230 @@
+// Package demo holds every synthetic answer the API can serve. It is only consulted when demo
+// mode is enabled in the configuration, and everything it returns is marked as synthetic.
+package demo
+
+import (
+	"strings"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+var synthetic = models.Provenance{Synthetic: true}
+
+// Payload is a free-form synthetic response body
+type Payload map[string]interface{}
+
+// IsSynthetic reports that the payload holds synthetic data
+func (Payload) IsSynthetic() bool {
+	return true
+}
+
+// newPayload returns a payload carrying the synthetic marker field
+func newPayload(fields map[string]interface{}) Payload {
+	p := Payload{"synthetic": true}
+	for k, v := range fields {
+		p[k] = v
+	}
+	return p
+}
+
+// TimeSeries returns a synthetic two-year influenza series
+func TimeSeries() *models.TimeSeries {
+	return &models.TimeSeries{
+		Points: []models.DiseaseTimePoint{
+			{Year: 2020, Quarter: 1, Name: "Influenza", Cases: 15000},
+			{Year: 2020, Quarter: 2, Name: "Influenza", Cases: 12000},
+			{Year: 2020, Quarter: 3, Name: "Influenza", Cases: 9000},
+			{Year: 2020, Quarter: 4, Name: "Influenza", Cases: 18000},
+			{Year: 2021, Quarter: 1, Name: "Influenza", Cases: 24000},
+			{Year: 2021, Quarter: 2, Name: "Influenza", Cases: 18000},
+			{Year: 2021, Quarter: 3, Name: "Influenza", Cases: 14000},
+			{Year: 2021, Quarter: 4, Name: "Influenza", Cases: 19000},
+		},
+		Provenance: synthetic,
+	}
+}
+
+// Comparison returns synthetic yearly totals for a disease
+func Comparison(disease string, years []int) *models.DiseaseComparison {
+	comparison := &models.DiseaseComparison{
+		Disease:    disease,
+		Data:       make([]models.DiseaseComparisonPoint, 0, len(years)),
+		Provenance: synthetic,
+	}
+
+	for i, year := range years {
+		baseValue := 10000 + (year%10)*1000
+		comparison.Data = append(comparison.Data, models.DiseaseComparisonPoint{
+			Year:  year,
+			Cases: baseValue + i*1500,
+		})
+	}
+
+	return comparison
+}
+
+// Prediction returns a synthetic quarterly prediction for a year
+func Prediction(year int) *models.DiseasePrediction {
+	baseValue := 5000 + (year%5)*1500
+	seasonality := []float64{1.2, 0.8, 0.7, 1.3} // Q1, Q2, Q3, Q4 factors
+
+	return &models.DiseasePrediction{
+		Year:             year,
+		Q1PredictedCases: int(float64(baseValue) * seasonality[0]),
+		Q2PredictedCases: int(float64(baseValue) * seasonality[1]),
+		Q3PredictedCases: int(float64(baseValue) * seasonality[2]),
+		Q4PredictedCases: int(float64(baseValue) * seasonality[3]),
+		Model:            "synthetic",
+		Provenance:       synthetic,
+	}
+}
+
+// Correlation returns a synthetic correlation result
+func Correlation() *models.CorrelationResult {
+	return &models.CorrelationResult{
+		CorrelationCoefficient: 0.75,
+		PValue:                 0.001,
+		ConfidenceInterval: models.ConfInterval{
+			Lower: 0.65,
+			Upper: 0.85,
+		},
+		Provenance: synthetic,
+	}
+}
+
+// LaggedCorrelation returns a synthetic cross-correlation function peaking at a one-quarter lag
+func LaggedCorrelation() *models.LaggedCorrelationResult {
+	ccf := models.CrossCorrelation{Lags: []models.LagCorrelation{
+		{Lag: 0, Coefficient: 0.41, SampleSize: 36, Band: 0.33, Significant: true},
+		{Lag: 1, Coefficient: 0.58, SampleSize: 35, Band: 0.33, Significant: true},
+		{Lag: 2, Coefficient: 0.22, SampleSize: 34, Band: 0.34},
+	}}
+	ccf.BestLag = &ccf.Lags[1]
+
+	return &models.LaggedCorrelationResult{
+		Factor1:     "temperature",
+		Factor2:     "respiratory_diseases",
+		LagUnit:     models.LagQuarter,
+		MaxLag:      2,
+		Level:       0.95,
+		Raw:         ccf,
+		Prewhitened: ccf,
+		Provenance:  synthetic,
+	}
+}
+
+// Forecast returns a synthetic forecast for the four quarters of a year
+func Forecast(diseaseID string, year int) *models.ForecastResponse {
+	return &models.ForecastResponse{
+		DiseaseID: diseaseID,
+		Year:      year,
+		Model:     "synthetic",
+		Level:     0.95,
+		Quarters:  []int{1, 2, 3, 4},
+		Forecast:  []int{120, 145, 210, 180},
+		ConfidenceIntervals: []models.ConfInterval{
+			{Lower: 90, Upper: 150}, {Lower: 110, Upper: 180}, {Lower: 160, Upper: 260}, {Lower: 140, Upper: 220},
+		},
+		Provenance: synthetic,
+	}
+}
+
+// Correlations returns a synthetic correlation matrix of two factors
+func Correlations() *models.CorrelationMatrix {
+	one, r, p, q := 1.0, 0.75, 0.001, 0.001
+
+	return &models.CorrelationMatrix{
+		Series:          []string{"environment:temperature", "category:Respiratory Infections"},
+		Method:          models.CorrelationPearson,
+		Coefficients:    [][]*float64{{&one, &r}, {&r, &one}},
+		PValues:         [][]*float64{{nil, &p}, {&p, nil}},
+		AdjustedPValues: [][]*float64{{nil, &q}, {&q, nil}},
+		SampleSizes:     [][]int{{36, 36}, {36, 36}},
+		Provenance:      synthetic,
+	}
+}
+
+// AnalyticsQuery returns a synthetic answer chosen by keywords in the query
+func AnalyticsQuery(query string) Payload {
+	query = strings.ToLower(query)
+
+	if strings.Contains(query, "correlation") || strings.Contains(query, "correlate") {
+		return newPayload(map[string]interface{}{
+			"type": "correlation",
+			"data": map[string]interface{}{
+				"factor1":                 "temperature",
+				"factor2":                 "respiratory_diseases",
+				"correlation_coefficient": 0.72,
+				"significance":            "high",
+				"explanation":             "There is a strong correlation between temperature drops and increase in respiratory diseases.",
+			},
+		})
+	}
+
+	if strings.Contains(query, "trend") || strings.Contains(query, "trending") {
+		return newPayload(map[string]interface{}{
+			"type": "trend",
+			"data": map[string]interface{}{
+				"disease":            "Influenza",
+				"trend":              "increasing",
+				"change_percent":     12.5,
+				"period":             "last_quarter",
+				"visualization_data": []int{145, 167, 189, 210, 245},
+			},
+		})
+	}
+
+	if strings.Contains(query, "forecast") || strings.Contains(query, "predict") {
+		return newPayload(map[string]interface{}{
+			"type": "forecast",
+			"data": map[string]interface{}{
+				"disease":                 "COVID-19",
+				"next_quarter_prediction": 320,
+				"confidence_interval": map[string]float64{
+					"lower": 280.5,
+					"upper": 359.5,
+				},
+				"forecast_values": []int{320, 280, 250, 210},
+			},
+		})
+	}
+
+	return newPayload(map[string]interface{}{
+		"type": "general_stats",
+		"data": map[string]interface{}{
+			"most_prevalent_disease": "Influenza",
+			"highest_incidence":      "COVID-19",
+			"recent_trend":           "Respiratory infections showing seasonal increase",
+			"suggestion":             "Try querying about specific diseases, trends, or correlations.",
+		},
+	})
+}
+
+// QueryResult returns a synthetic natural language answer
+func QueryResult() *models.QueryResult {
+	return &models.QueryResult{
+		Answer: "The highest flu cases in 2023 occurred in Q1, with approximately 12,000 cases.",
+		Data: map[string]interface{}{
+			"disease": "Influenza",
+			"year":    2023,
+			"quarter": 1,
+			"cases":   12000,
+		},
+		Explanation: "This answer is based on disease records from Q1 2023 where influenza cases peaked.",
+		Provenance:  synthetic,
+	}
+}
+
+// RegionalDistribution returns synthetic cases for three cities
+func RegionalDistribution() *models.RegionalDistribution {
+	coord := func(v float64) *float64 { return &v }
+
+	return &models.RegionalDistribution{
+		Regions: []models.RegionCases{
+			{Name: "Chisinau", Cases: 1245, Lat: coord(47.0105), Lon: coord(28.8638), Diseases: []string{"Influenza", "COVID-19", "Tuberculosis"}},
+			{Name: "Balti", Cases: 532, Lat: coord(47.7619), Lon: coord(27.9294), Diseases: []string{"Influenza", "Hepatitis A"}},
+			{Name: "Tiraspol", Cases: 348, Lat: coord(46.8403), Lon: coord(29.6433), Diseases: []string{"COVID-19", "Tuberculosis"}},
+		},
+		Provenance: synthetic,
+	}
+}
@@ -0,0 +1,125 @@
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Accuracy summarises the errors of a rolling-origin backtest. MAPE skips zero actuals and
+// MASE skips origins whose training series has no seasonal variation; either is nil when
+// nothing remains to average.
+type Accuracy struct {
+	FirstOrigin int // index of the first origin that could be fitted
+	LastOrigin  int
+	Origins     int // origins fitted
+	Skipped     int // origins whose training series was too short for the model
+	Forecasts   int // forecasts scored against an actual value
+	MAE         float64
+	MAPE        *float64
+	SMAPE       float64
+	MASE        *float64
+	Coverage    float64 // share of actuals inside the prediction interval
+}
+
+// Backtest refits the model on y truncated at every origin from firstOrigin onwards and scores
+// the forecasts up to horizon steps ahead against the observed values. Like served forecasts,
+// means and bounds are clamped at zero. Origins the model cannot fit are skipped; when none is
+// left, the error is ErrNonPositive if values the model cannot fit were the reason and
+// ErrSeriesTooShort otherwise.
+func Backtest(m Model, y []float64, period, firstOrigin, horizon int, level float64) (*Accuracy, error) {
+	if firstOrigin < 0 {
+		firstOrigin = 0
+	}
+
+	acc := &Accuracy{FirstOrigin: -1}
+	var absSum, apeSum, sapeSum, scaledSum float64
+	var apeN, scaledN, covered int
+	var nonPositive error
+
+	for origin := firstOrigin; origin < len(y)-1; origin++ {
+		steps := horizon
+		if origin+steps >= len(y) {
+			steps = len(y) - 1 - origin
+		}
+
+		train := y[:origin+1]
+		f, err := m.Forecast(train, period, steps, level)
+		if errors.Is(err, ErrNonPositive) {
+			nonPositive = err
+		}
+		if errors.Is(err, ErrSeriesTooShort) || errors.Is(err, ErrNonPositive) {
+			acc.Skipped++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if acc.FirstOrigin < 0 {
+			acc.FirstOrigin = origin
+		}
+		acc.LastOrigin = origin
+		acc.Origins++
+
+		scale := naiveScale(train, period)
+		for h := 0; h < steps; h++ {
+			actual := y[origin+1+h]
+			mean := math.Max(f.Mean[h], 0)
+			absErr := math.Abs(actual - mean)
+
+			acc.Forecasts++
+			absSum += absErr
+			if actual != 0 {
+				apeSum += absErr / math.Abs(actual) * 100
+				apeN++
+			}
+			if denom := math.Abs(actual) + math.Abs(mean); denom > 0 {
+				sapeSum += 200 * absErr / denom
+			}
+			if scale > 0 {
+				scaledSum += absErr / scale
+				scaledN++
+			}
+			if actual >= math.Max(f.Lower[h], 0) && actual <= math.Max(f.Upper[h], 0) {
+				covered++
+			}
+		}
+	}
+
+	// Values the model cannot fit, rather than the length of the series, are what left nothing
+	// to score when any long enough training series was rejected for them
+	if acc.Forecasts == 0 && nonPositive != nil {
+		return nil, nonPositive
+	}
+	if acc.Forecasts == 0 {
+		return nil, fmt.Errorf("%w: no origin from index %d could be fitted and scored", ErrSeriesTooShort, firstOrigin)
+	}
+
+	n := float64(acc.Forecasts)
+	acc.MAE = absSum / n
+	acc.SMAPE = sapeSum / n
+	acc.Coverage = float64(covered) / n
+	if apeN > 0 {
+		mape := apeSum / float64(apeN)
+		acc.MAPE = &mape
+	}
+	if scaledN > 0 {
+		mase := scaledSum / float64(scaledN)
+		acc.MASE = &mase
+	}
+
+	return acc, nil
+}
+
+// naiveScale is the in-sample mean absolute error of the seasonal naive forecast, the MASE denominator
+func naiveScale(y []float64, period int) float64 {
+	if len(y) <= period {
+		return 0
+	}
+	var sum float64
+	for t := period; t < len(y); t++ {
+		sum += math.Abs(y[t] - y[t-period])
+	}
+	return sum / float64(len(y)-period)
+}
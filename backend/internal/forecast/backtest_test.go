@@ -0,0 +1,68 @@
+package forecast
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBacktestScoresEveryOrigin(t *testing.T) {
+	// Every value exceeds that of a season before by 40, the in-sample error of the seasonal
+	// naive forecast, so its forecasts within a season are all 40 too low: MASE is 1
+	y := make([]float64, 12)
+	for i := range y {
+		y[i] = 100 + 10*float64(i) + []float64{-30, 10, 40, -20}[i%4]
+	}
+
+	acc, err := Backtest(seasonalNaive{}, y, 4, 2, 2, 0.95)
+	if err != nil {
+		t.Fatalf("Backtest: %v", err)
+	}
+
+	// Origins 2 and 3 leave less than the period plus one observation to fit; origins 4 to 10
+	// are scored two steps ahead, the last one only the one step left
+	if acc.Skipped != 2 || acc.Origins != 7 || acc.FirstOrigin != 4 || acc.LastOrigin != 10 || acc.Forecasts != 13 {
+		t.Errorf("skipped %d, fitted %d origins from %d to %d, scored %d forecasts; want 2, 7 from 4 to 10, 13",
+			acc.Skipped, acc.Origins, acc.FirstOrigin, acc.LastOrigin, acc.Forecasts)
+	}
+	if acc.MAE != 40 || acc.MASE == nil || !near(*acc.MASE, 1, 1e-12) {
+		t.Errorf("MAE %g, MASE %v; want 40 and 1", acc.MAE, acc.MASE)
+	}
+
+	// The residual spread is 40 too: a 95% interval reaches the actuals, a 50% one does not
+	if acc.Coverage != 1 {
+		t.Errorf("coverage at 95%% = %g, want 1", acc.Coverage)
+	}
+	narrow, err := Backtest(seasonalNaive{}, y, 4, 2, 2, 0.5)
+	if err != nil {
+		t.Fatalf("Backtest: %v", err)
+	}
+	if narrow.Coverage != 0 {
+		t.Errorf("coverage at 50%% = %g, want 0", narrow.Coverage)
+	}
+
+	// MASE is scale free, MAE is not
+	scaled := make([]float64, len(y))
+	for i, v := range y {
+		scaled[i] = 3 * v
+	}
+	tripled, err := Backtest(seasonalNaive{}, scaled, 4, 2, 2, 0.95)
+	if err != nil {
+		t.Fatalf("Backtest: %v", err)
+	}
+	if tripled.MAE != 120 || !near(*tripled.MASE, *acc.MASE, 1e-12) {
+		t.Errorf("tripled series: MAE %g, MASE %g; want 120 and %g", tripled.MAE, *tripled.MASE, *acc.MASE)
+	}
+}
+
+func TestBacktestReportsWhyNothingWasScored(t *testing.T) {
+	if _, err := Backtest(seasonalNaive{}, noisy(5), 4, 0, 4, 0.95); !errors.Is(err, ErrSeriesTooShort) {
+		t.Errorf("short series: error = %v, want ErrSeriesTooShort", err)
+	}
+
+	// A zero in the first quarter is part of every training series
+	y := noisy(20)
+	y[0] = 0
+	if _, err := Backtest(holtWinters{multiplicative: true}, y, 4, 0, 4, 0.95); !errors.Is(err, ErrNonPositive) {
+		t.Errorf("zero count: error = %v, want ErrNonPositive", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package forecast implements the seasonal time-series models used to predict quarterly case counts.
+//
+// Every model is fitted on an evenly spaced series without gaps and returns point forecasts with
+// prediction intervals. Intervals come from the model's forecast variance where it has a closed
+// form and from simulated sample paths otherwise.
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrUnknownModel is returned for a model name that is not registered
+	ErrUnknownModel = errors.New("unknown forecast model")
+	// ErrSeriesTooShort is returned when a series has too few observations to fit a model
+	ErrSeriesTooShort = errors.New("series too short")
+	// ErrNonPositive is returned when a multiplicative model meets zero or negative values
+	ErrNonPositive = errors.New("series has non-positive values")
+)
+
+// Model names accepted by New
+const (
+	SeasonalNaive             = "seasonal_naive"
+	HoltWintersAdditive       = "holt_winters_additive"
+	HoltWintersMultiplicative = "holt_winters_multiplicative"
+	SARIMA                    = "sarima"
+)
+
+// Defaults for requests that leave the model or interval level unset
+const (
+	DefaultModel = HoltWintersAdditive
+	DefaultLevel = 0.95
+)
+
+// Simulated intervals use a fixed seed so that repeated requests return the same bounds
+const (
+	simulatedPaths       = 2000
+	simulationSeed int64 = 20240101
+)
+
+// Forecast holds point forecasts with prediction intervals at the requested level
+type Forecast struct {
+	Model  string
+	Level  float64
+	Mean   []float64
+	Lower  []float64
+	Upper  []float64
+	Params map[string]float64 // fitted parameters
+	Sigma  float64            // residual standard deviation of the fit
+}
+
+// Model fits a series with the given seasonal period and forecasts horizon steps ahead
+type Model interface {
+	Name() string
+	Forecast(y []float64, period, horizon int, level float64) (*Forecast, error)
+}
+
+var registry = map[string]Model{
+	SeasonalNaive:             seasonalNaive{},
+	HoltWintersAdditive:       holtWinters{multiplicative: false},
+	HoltWintersMultiplicative: holtWinters{multiplicative: true},
+	SARIMA:                    airline{},
+}
+
+// New returns the model registered under name
+func New(name string) (Model, error) {
+	m, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q; available: %v", ErrUnknownModel, name, Names())
+	}
+	return m, nil
+}
+
+// Names lists the registered models in alphabetical order
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkArgs validates the arguments shared by all models
+func checkArgs(y []float64, period, horizon, minLength int, level float64) error {
+	if period < 1 {
+		return fmt.Errorf("seasonal period must be positive, got %d", period)
+	}
+	if horizon < 1 {
+		return fmt.Errorf("horizon must be positive, got %d", horizon)
+	}
+	if level <= 0 || level >= 1 {
+		return fmt.Errorf("interval level must be between 0 and 1, got %g", level)
+	}
+	if len(y) < minLength {
+		return fmt.Errorf("%w: %d observations, at least %d needed", ErrSeriesTooShort, len(y), minLength)
+	}
+	return nil
+}
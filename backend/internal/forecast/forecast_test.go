@@ -0,0 +1,167 @@
+package forecast
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// trendSeason returns n quarters of 500 + 5t plus a seasonal pattern summing to zero
+func trendSeason(n int) []float64 {
+	season := []float64{-30, 10, 40, -20}
+	y := make([]float64, n)
+	for t := range y {
+		y[t] = 500 + 5*float64(t) + season[t%4]
+	}
+	return y
+}
+
+// noisy returns a seasonal series with deterministic noise from a linear congruential generator
+func noisy(n int) []float64 {
+	seed := uint32(2024)
+	season := []float64{-30, 10, 40, -20}
+	y := make([]float64, n)
+	for t := range y {
+		seed = seed*1664525 + 1013904223
+		y[t] = 500 + 3*float64(t) + season[t%4] + 40*(float64(seed)/math.MaxUint32-0.5)
+	}
+	return y
+}
+
+func TestSeasonalNaiveRepeatsLastSeason(t *testing.T) {
+	y := noisy(10)
+	f, err := seasonalNaive{}.Forecast(y, 4, 6, 0.9)
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+
+	want := []float64{y[6], y[7], y[8], y[9], y[6], y[7]}
+	for h := range want {
+		if f.Mean[h] != want[h] {
+			t.Errorf("h=%d: mean %g, want %g", h+1, f.Mean[h], want[h])
+		}
+	}
+
+	// The variance grows with the seasons ahead: constant within the first season, then twice
+	width := func(h int) float64 { return f.Upper[h] - f.Lower[h] }
+	if !near(width(3), width(0), 1e-9) || !near(width(4), math.Sqrt2*width(0), 1e-9) {
+		t.Errorf("interval widths %g, %g, %g; want the fifth √2 times the first and fourth", width(0), width(3), width(4))
+	}
+}
+
+func TestModelsContinueTrendAndSeason(t *testing.T) {
+	y := trendSeason(24)
+	future := trendSeason(32)[24:]
+
+	for _, name := range []string{HoltWintersAdditive, SARIMA} {
+		t.Run(name, func(t *testing.T) {
+			m, err := New(name)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			f, err := m.Forecast(y, 4, len(future), 0.95)
+			if err != nil {
+				t.Fatalf("Forecast: %v", err)
+			}
+			if f.Sigma > 1e-6 {
+				t.Errorf("sigma = %g, want an exact fit", f.Sigma)
+			}
+			for h, want := range future {
+				if !near(f.Mean[h], want, 1e-6) {
+					t.Errorf("h=%d: mean %g, want %g", h+1, f.Mean[h], want)
+				}
+				if f.Lower[h] > f.Mean[h]+1e-6 || f.Upper[h] < f.Mean[h]-1e-6 {
+					t.Errorf("h=%d: interval [%g, %g] excludes the mean %g", h+1, f.Lower[h], f.Upper[h], f.Mean[h])
+				}
+			}
+		})
+	}
+}
+
+func TestMultiplicativeHoltWintersFollowsProportionalSeason(t *testing.T) {
+	factors := []float64{0.8, 1.1, 1.3, 0.8}
+	y := make([]float64, 32)
+	for t := range y {
+		y[t] = (200 + 4*float64(t)) * factors[t%4]
+	}
+
+	f, err := holtWinters{multiplicative: true}.Forecast(y[:24], 4, 8, 0.95)
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+	for h, want := range y[24:] {
+		if math.Abs(f.Mean[h]-want) > 0.01*want {
+			t.Errorf("h=%d: mean %g, want %g within 1%%", h+1, f.Mean[h], want)
+		}
+	}
+}
+
+func TestMultiplicativeHoltWintersRejectsNonPositive(t *testing.T) {
+	for _, v := range []float64{0, -3} {
+		y := noisy(12)
+		y[5] = v
+		if _, err := (holtWinters{multiplicative: true}).Forecast(y, 4, 4, 0.95); !errors.Is(err, ErrNonPositive) {
+			t.Errorf("value %g: error = %v, want ErrNonPositive", v, err)
+		}
+		if _, err := (holtWinters{}).Forecast(y, 4, 4, 0.95); err != nil {
+			t.Errorf("additive model with value %g: %v", v, err)
+		}
+	}
+}
+
+func TestAirlinePsi(t *testing.T) {
+	tests := []struct {
+		name                 string
+		theta, seasonalTheta float64
+		want                 []float64
+	}{
+		// (1+θB)(1+ΘB⁴) / ((1-B)(1-B⁴)): 1 + θ per step, and 1 + Θ times that again every season
+		{"θ = Θ = -0.5", -0.5, -0.5, []float64{1, 0.5, 0.5, 0.5, 1, 0.75, 0.75, 0.75, 1.25}},
+		// Without moving-average terms every season adds one more unit
+		{"θ = Θ = 0", 0, 0, []float64{1, 1, 1, 1, 2, 2, 2, 2, 3}},
+	}
+	for _, tt := range tests {
+		got := airlinePsi(4, tt.theta, tt.seasonalTheta, len(tt.want))
+		for j := range tt.want {
+			if !near(got[j], tt.want[j], 1e-12) {
+				t.Errorf("%s: psi = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSARIMAIntervalsWidenWithHorizon(t *testing.T) {
+	f, err := airline{}.Forecast(noisy(40), 4, 12, 0.8)
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+	if f.Sigma <= 0 {
+		t.Fatalf("sigma = %g, want a positive residual spread", f.Sigma)
+	}
+	for h := 1; h < len(f.Mean); h++ {
+		if f.Upper[h]-f.Lower[h] <= f.Upper[h-1]-f.Lower[h-1] {
+			t.Errorf("h=%d: interval width %g does not exceed %g at h=%d",
+				h+1, f.Upper[h]-f.Lower[h], f.Upper[h-1]-f.Lower[h-1], h)
+		}
+	}
+}
+
+func TestForecastRejectsInvalidArguments(t *testing.T) {
+	if _, err := New("arima"); !errors.Is(err, ErrUnknownModel) {
+		t.Errorf("New(arima) error = %v, want ErrUnknownModel", err)
+	}
+	for _, name := range Names() {
+		m, _ := New(name)
+		if _, err := m.Forecast(noisy(4), 4, 4, 0.95); !errors.Is(err, ErrSeriesTooShort) {
+			t.Errorf("%s on one season: error = %v, want ErrSeriesTooShort", name, err)
+		}
+		if _, err := m.Forecast(noisy(20), 4, 4, 1); err == nil {
+			t.Errorf("%s at level 1: no error", name)
+		}
+	}
+}
+
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
@@ -0,0 +1,167 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/ktruedat/healthisis/backend/internal/pkg/stats"
+)
+
+// holtWinters is triple exponential smoothing with an additive trend and an additive or
+// multiplicative season. Smoothing parameters minimise the one-step squared errors, which
+// are relative errors for the multiplicative season.
+type holtWinters struct {
+	multiplicative bool
+}
+
+// hwState is the level, trend and the seasonal indices of the next period positions
+type hwState struct {
+	level, trend float64
+	season       []float64
+}
+
+func (m holtWinters) Name() string {
+	if m.multiplicative {
+		return HoltWintersMultiplicative
+	}
+	return HoltWintersAdditive
+}
+
+// Forecast fits the smoothing parameters and simulates sample paths for the intervals
+func (m holtWinters) Forecast(y []float64, period, horizon int, level float64) (*Forecast, error) {
+	if err := checkArgs(y, period, horizon, 2*period+1, level); err != nil {
+		return nil, err
+	}
+	if m.multiplicative {
+		for _, v := range y {
+			if v <= 0 {
+				return nil, fmt.Errorf("%w: multiplicative seasonality needs positive values", ErrNonPositive)
+			}
+		}
+	}
+
+	objective := func(x []float64) float64 {
+		sse, _ := m.run(y, period, logistic(x[0]), logistic(x[1]), logistic(x[2]))
+		return sse
+	}
+	x := minimize(objective, []float64{logit(0.3), logit(0.1), logit(0.1)}, 500)
+	alpha, beta, gamma := logistic(x[0]), logistic(x[1]), logistic(x[2])
+
+	sse, state := m.run(y, period, alpha, beta, gamma)
+	dof := len(y) - period - 3
+	if dof < 1 {
+		dof = 1
+	}
+	sigma := math.Sqrt(sse / float64(dof))
+
+	f := &Forecast{
+		Model:  m.Name(),
+		Level:  level,
+		Sigma:  sigma,
+		Params: map[string]float64{"alpha": alpha, "beta": beta, "gamma": gamma},
+	}
+
+	for h := 1; h <= horizon; h++ {
+		f.Mean = append(f.Mean, m.point(state, h))
+	}
+
+	// Sample paths feed simulated one-step errors through the smoothing equations
+	rng := rand.New(rand.NewSource(simulationSeed))
+	paths := make([][]float64, horizon)
+	for i := range paths {
+		paths[i] = make([]float64, simulatedPaths)
+	}
+	for p := 0; p < simulatedPaths; p++ {
+		s := hwState{level: state.level, trend: state.trend, season: append([]float64(nil), state.season...)}
+		for h := 0; h < horizon; h++ {
+			fitted := m.point(s, 1)
+			obs := fitted + rng.NormFloat64()*sigma
+			if m.multiplicative {
+				obs = fitted * (1 + rng.NormFloat64()*sigma)
+			}
+			paths[h][p] = obs
+			s = m.update(s, obs, alpha, beta, gamma)
+		}
+	}
+
+	for h := 0; h < horizon; h++ {
+		f.Lower = append(f.Lower, stats.Quantile(paths[h], (1-level)/2))
+		f.Upper = append(f.Upper, stats.Quantile(paths[h], (1+level)/2))
+	}
+
+	return f, nil
+}
+
+// run filters the series after its first season with the given parameters, returning the sum of
+// squared one-step errors and the final state
+func (m holtWinters) run(y []float64, period int, alpha, beta, gamma float64) (float64, hwState) {
+	s := m.initial(y, period)
+
+	var sse float64
+	for _, obs := range y[period:] {
+		fitted := m.point(s, 1)
+		e := obs - fitted
+		if m.multiplicative {
+			e /= fitted
+		}
+		sse += e * e
+		s = m.update(s, obs, alpha, beta, gamma)
+	}
+
+	if math.IsNaN(sse) || math.IsInf(sse, 0) {
+		return math.MaxFloat64, s
+	}
+	return sse, s
+}
+
+// initial derives the state at the end of the first season from the first two seasons. The
+// trend is the change between their means; the seasonal indices compare the first season with
+// that trend line, so that they do not absorb the growth within the season.
+func (m holtWinters) initial(y []float64, period int) hwState {
+	first := stats.Mean(y[:period])
+	second := stats.Mean(y[period : 2*period])
+	trend := (second - first) / float64(period)
+	middle := float64(period-1) / 2
+
+	s := hwState{level: first + trend*middle, trend: trend, season: make([]float64, period)}
+	for i := 0; i < period; i++ {
+		base := first + trend*(float64(i)-middle)
+		if m.multiplicative {
+			if base <= 0 {
+				base = first
+			}
+			s.season[i] = y[i] / base
+		} else {
+			s.season[i] = y[i] - base
+		}
+	}
+	return s
+}
+
+// point is the h-step point forecast from a state
+func (m holtWinters) point(s hwState, h int) float64 {
+	seasonal := s.season[(h-1)%len(s.season)]
+	if m.multiplicative {
+		return (s.level + float64(h)*s.trend) * seasonal
+	}
+	return s.level + float64(h)*s.trend + seasonal
+}
+
+// update applies one observation to a state; the season slice rotates so that index 0 always
+// belongs to the next period
+func (m holtWinters) update(s hwState, obs, alpha, beta, gamma float64) hwState {
+	seasonal := s.season[0]
+	var level, newSeasonal float64
+	if m.multiplicative {
+		level = alpha*obs/seasonal + (1-alpha)*(s.level+s.trend)
+		newSeasonal = gamma*obs/(s.level+s.trend) + (1-gamma)*seasonal
+	} else {
+		level = alpha*(obs-seasonal) + (1-alpha)*(s.level+s.trend)
+		newSeasonal = gamma*(obs-s.level-s.trend) + (1-gamma)*seasonal
+	}
+	trend := beta*(level-s.level) + (1-beta)*s.trend
+
+	season := append(s.season[1:len(s.season):len(s.season)], newSeasonal)
+	return hwState{level: level, trend: trend, season: season}
+}
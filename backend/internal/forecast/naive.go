@@ -0,0 +1,39 @@
+package forecast
+
+import (
+	"math"
+
+	"github.com/ktruedat/healthisis/backend/internal/pkg/stats"
+)
+
+// seasonalNaive repeats the last observed season
+type seasonalNaive struct{}
+
+func (seasonalNaive) Name() string { return SeasonalNaive }
+
+// Forecast repeats the last season; the variance grows with the number of seasons ahead
+func (seasonalNaive) Forecast(y []float64, period, horizon int, level float64) (*Forecast, error) {
+	if err := checkArgs(y, period, horizon, period+1, level); err != nil {
+		return nil, err
+	}
+
+	n := len(y)
+	var sse float64
+	for t := period; t < n; t++ {
+		e := y[t] - y[t-period]
+		sse += e * e
+	}
+	sigma := math.Sqrt(sse / float64(n-period))
+	z := stats.NormalQuantile(0.5 + level/2)
+
+	f := &Forecast{Model: SeasonalNaive, Level: level, Sigma: sigma, Params: map[string]float64{}}
+	for h := 1; h <= horizon; h++ {
+		mean := y[n-period+(h-1)%period]
+		sd := sigma * math.Sqrt(float64((h-1)/period+1))
+		f.Mean = append(f.Mean, mean)
+		f.Lower = append(f.Lower, mean-z*sd)
+		f.Upper = append(f.Upper, mean+z*sd)
+	}
+
+	return f, nil
+}
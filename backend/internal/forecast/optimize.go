@@ -0,0 +1,97 @@
+package forecast
+
+import (
+	"math"
+	"sort"
+)
+
+// minimize finds a local minimum of f with the Nelder-Mead simplex method starting from x0
+func minimize(f func([]float64) float64, x0 []float64, maxIter int) []float64 {
+	const (
+		reflection  = 1.0
+		expansion   = 2.0
+		contraction = 0.5
+		shrink      = 0.5
+		tolerance   = 1e-10
+	)
+
+	n := len(x0)
+	type vertex struct {
+		x []float64
+		f float64
+	}
+
+	simplex := make([]vertex, n+1)
+	simplex[0] = vertex{append([]float64(nil), x0...), f(x0)}
+	for i := 0; i < n; i++ {
+		x := append([]float64(nil), x0...)
+		if x[i] != 0 {
+			x[i] *= 1.5
+		} else {
+			x[i] = 0.5
+		}
+		simplex[i+1] = vertex{x, f(x)}
+	}
+
+	point := func(c []float64, d []float64, t float64) []float64 {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = c[i] + t*(d[i]-c[i])
+		}
+		return x
+	}
+
+	for iter := 0; iter < maxIter; iter++ {
+		sort.Slice(simplex, func(i, j int) bool { return simplex[i].f < simplex[j].f })
+		if math.Abs(simplex[n].f-simplex[0].f) <= tolerance*(math.Abs(simplex[0].f)+tolerance) {
+			break
+		}
+
+		centroid := make([]float64, n)
+		for _, v := range simplex[:n] {
+			for i := range centroid {
+				centroid[i] += v.x[i] / float64(n)
+			}
+		}
+
+		worst := simplex[n]
+		xr := point(centroid, worst.x, -reflection)
+		fr := f(xr)
+
+		switch {
+		case fr < simplex[0].f:
+			xe := point(centroid, worst.x, -expansion)
+			if fe := f(xe); fe < fr {
+				simplex[n] = vertex{xe, fe}
+			} else {
+				simplex[n] = vertex{xr, fr}
+			}
+		case fr < simplex[n-1].f:
+			simplex[n] = vertex{xr, fr}
+		default:
+			xc := point(centroid, worst.x, contraction)
+			if fc := f(xc); fc < worst.f {
+				simplex[n] = vertex{xc, fc}
+				continue
+			}
+			best := simplex[0].x
+			for i := 1; i <= n; i++ {
+				x := point(best, simplex[i].x, shrink)
+				simplex[i] = vertex{x, f(x)}
+			}
+		}
+	}
+
+	sort.Slice(simplex, func(i, j int) bool { return simplex[i].f < simplex[j].f })
+	return simplex[0].x
+}
+
+// logistic maps the real line onto (0, 1)
+func logistic(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+// logit is the inverse of logistic
+func logit(p float64) float64 {
+	return math.Log(p / (1 - p))
+}
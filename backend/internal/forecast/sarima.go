@@ -0,0 +1,100 @@
+package forecast
+
+import (
+	"math"
+
+	"github.com/ktruedat/healthisis/backend/internal/pkg/stats"
+)
+
+// airline is the seasonal ARIMA(0,1,1)(0,1,1) model, fitted by conditional sum of squares
+type airline struct{}
+
+func (airline) Name() string { return SARIMA }
+
+// Forecast fits the two moving-average terms and derives intervals from the psi weights
+func (airline) Forecast(y []float64, period, horizon int, level float64) (*Forecast, error) {
+	if err := checkArgs(y, period, horizon, 3*period, level); err != nil {
+		return nil, err
+	}
+
+	// Coefficients are kept inside (-1, 1) so the fitted model stays invertible
+	bound := func(x float64) float64 { return 0.99 * math.Tanh(x) }
+	objective := func(x []float64) float64 {
+		css, _ := airlineResiduals(y, period, bound(x[0]), bound(x[1]))
+		return css
+	}
+	x := minimize(objective, []float64{-0.3, -0.3}, 400)
+	theta, seasonalTheta := bound(x[0]), bound(x[1])
+
+	css, e := airlineResiduals(y, period, theta, seasonalTheta)
+	dof := len(y) - period - 1 - 2
+	if dof < 1 {
+		dof = 1
+	}
+	sigma := math.Sqrt(css / float64(dof))
+
+	// Extend the series, with future errors at their expectation of zero
+	n := len(y)
+	ext := append(append([]float64(nil), y...), make([]float64, horizon)...)
+	errs := append(append([]float64(nil), e...), make([]float64, horizon)...)
+	for t := n; t < n+horizon; t++ {
+		ext[t] = ext[t-1] + ext[t-period] - ext[t-period-1] +
+			theta*errs[t-1] + seasonalTheta*errs[t-period] + theta*seasonalTheta*errs[t-period-1]
+	}
+
+	psi := airlinePsi(period, theta, seasonalTheta, horizon)
+	z := stats.NormalQuantile(0.5 + level/2)
+	f := &Forecast{
+		Model:  SARIMA,
+		Level:  level,
+		Sigma:  sigma,
+		Params: map[string]float64{"ma1": theta, "sma1": seasonalTheta},
+	}
+
+	var variance float64
+	for h := 0; h < horizon; h++ {
+		variance += psi[h] * psi[h]
+		mean := ext[n+h]
+		sd := sigma * math.Sqrt(variance)
+		f.Mean = append(f.Mean, mean)
+		f.Lower = append(f.Lower, mean-z*sd)
+		f.Upper = append(f.Upper, mean+z*sd)
+	}
+
+	return f, nil
+}
+
+// airlinePsi returns the first n psi weights of the model written in terms of the undifferenced
+// series, the weights of past errors in the h-step forecast error
+func airlinePsi(period int, theta, seasonalTheta float64, n int) []float64 {
+	ar := map[int]float64{1: 1, period: 1, period + 1: -1}
+	ma := map[int]float64{1: theta, period: seasonalTheta, period + 1: theta * seasonalTheta}
+	psi := make([]float64, n)
+	psi[0] = 1
+	for j := 1; j < n; j++ {
+		psi[j] = ma[j]
+		for i, phi := range ar {
+			if i <= j {
+				psi[j] += phi * psi[j-i]
+			}
+		}
+	}
+	return psi
+}
+
+// airlineResiduals returns the conditional sum of squares and the residuals aligned with y;
+// residuals before the first fully differenced observation are zero
+func airlineResiduals(y []float64, period int, theta, seasonalTheta float64) (float64, []float64) {
+	e := make([]float64, len(y))
+	var css float64
+	for t := period + 1; t < len(y); t++ {
+		w := y[t] - y[t-1] - y[t-period] + y[t-period-1]
+		e[t] = w - theta*e[t-1] - seasonalTheta*e[t-period] - theta*seasonalTheta*e[t-period-1]
+		css += e[t] * e[t]
+	}
+
+	if math.IsNaN(css) || math.IsInf(css, 0) {
+		return math.MaxFloat64, e
+	}
+	return css, e
+}
@@ -7,20 +7,112 @@ type CorrelationRequest struct {
 	Factor1   string          `json:"factor1"`
 	Factor2   string          `json:"factor2"`
 	Timeframe TimeframeFilter `json:"timeframe"`
+	MaxLag    int             `json:"max_lag,omitempty"`  // lagged analysis: factor1 leads factor2 by 0..MaxLag
+	LagUnit   string          `json:"lag_unit,omitempty"` // lagged analysis: LagQuarter (default) or LagMonth
 }
 
+// Lag units of a lagged correlation analysis
+const (
+	LagQuarter = "quarter"
+	LagMonth   = "month"
+)
+
 // TimeframeFilter defines a time period for analysis
 type TimeframeFilter struct {
 	StartDate time.Time `json:"start_date"`
 	EndDate   time.Time `json:"end_date"`
 }
 
-// CorrelationResult represents the results of a correlation analysis
+// CorrelationResult represents the results of a correlation analysis. The top-level coefficient,
+// p-value and interval repeat the Pearson statistics.
 type CorrelationResult struct {
-	CorrelationCoefficient float64      `json:"correlation_coefficient"`
-	PValue                 float64      `json:"p_value"`
-	ConfidenceInterval     ConfInterval `json:"confidence_interval"`
-	VisualizationData      interface{}  `json:"visualization_data"`
+	Factor1                string           `json:"factor1,omitempty"`
+	Factor2                string           `json:"factor2,omitempty"`
+	CorrelationCoefficient float64          `json:"correlation_coefficient"`
+	PValue                 float64          `json:"p_value"`
+	ConfidenceInterval     ConfInterval     `json:"confidence_interval"`
+	Pearson                *CorrelationStat `json:"pearson,omitempty"`
+	Spearman               *CorrelationStat `json:"spearman,omitempty"`
+	SampleSize             int              `json:"sample_size"`
+	Level                  float64          `json:"level,omitempty"`
+	Granularity            string           `json:"granularity,omitempty"` // GranularityQuarter or GranularityYear
+	VisualizationData      interface{}      `json:"visualization_data"`
+	Provenance
+}
+
+// CorrelationStat is one correlation coefficient with its two-sided p-value and Fisher-z interval
+type CorrelationStat struct {
+	Coefficient        float64      `json:"coefficient"`
+	PValue             float64      `json:"p_value"`
+	ConfidenceInterval ConfInterval `json:"confidence_interval"`
+}
+
+// LaggedCorrelationResult holds the cross-correlation of two series with factor1 leading, on the
+// original series and after prewhitening both with an autoregression fitted to factor1
+type LaggedCorrelationResult struct {
+	Factor1        string           `json:"factor1"`
+	Factor2        string           `json:"factor2"`
+	LagUnit        string           `json:"lag_unit"`
+	MaxLag         int              `json:"max_lag"`
+	Level          float64          `json:"level"`
+	Raw            CrossCorrelation `json:"raw"`
+	Prewhitened    CrossCorrelation `json:"prewhitened"`
+	PrewhitenOrder int              `json:"prewhiten_order"`
+	Provenance
+}
+
+// CrossCorrelation is a cross-correlation function over a range of lags
+type CrossCorrelation struct {
+	Lags    []LagCorrelation `json:"lags"`
+	BestLag *LagCorrelation  `json:"best_lag"` // lag with the largest absolute coefficient
+}
+
+// LagCorrelation is the correlation at one lag with its significance band ±z/sqrt(n)
+type LagCorrelation struct {
+	Lag         int     `json:"lag"`
+	Coefficient float64 `json:"coefficient"`
+	SampleSize  int     `json:"sample_size"`
+	Band        float64 `json:"band"`
+	Significant bool    `json:"significant"`
+}
+
+// CorrelationMatrixRequest selects the series of a correlation matrix
+type CorrelationMatrixRequest struct {
+	Diseases   []string        `json:"diseases"`   // disease IDs or names
+	Categories []string        `json:"categories"` // cases summed over the diseases of each category
+	Factors    []string        `json:"factors"`    // environmental factors and "emission:" pollutants
+	Timeframe  TimeframeFilter `json:"timeframe"`  // optional; the whole record by default
+	Method     string          `json:"method,omitempty"`
+	Cluster    bool            `json:"cluster,omitempty"` // reorder series by hierarchical clustering
+}
+
+// Correlation methods of a correlation matrix
+const (
+	CorrelationPearson  = "pearson"
+	CorrelationSpearman = "spearman"
+)
+
+// CorrelationMatrix holds the pairwise correlations of a set of series. Cells are null where two
+// series share too few quarters, or years; adjusted p-values apply the Benjamini-Hochberg
+// correction to all pairs at once.
+type CorrelationMatrix struct {
+	Series          []string     `json:"series"`
+	Method          string       `json:"method"`
+	Granularity     string       `json:"granularity"` // GranularityYear with an emission series among them
+	Coefficients    [][]*float64 `json:"coefficients"`
+	PValues         [][]*float64 `json:"p_values"`
+	AdjustedPValues [][]*float64 `json:"adjusted_p_values"`
+	SampleSizes     [][]int      `json:"sample_sizes"`
+	Clustered       bool         `json:"clustered"`
+	Provenance
+}
+
+// ScatterPoint is one period at which both correlated series were observed
+type ScatterPoint struct {
+	Year    int     `json:"year"`
+	Quarter int     `json:"quarter,omitempty"` // absent from yearly correlations
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
 }
 
 // ConfInterval represents a statistical confidence interval
@@ -43,23 +135,124 @@ type DiseaseData struct {
 
 // DiseaseComparison represents data for comparing disease across years
 type DiseaseComparison struct {
-	Disease string                   `json:"disease"`
-	Data    []DiseaseComparisonPoint `json:"data"`
+	Disease      string                   `json:"disease"`
+	BaselineYear int                      `json:"baselineYear"`
+	Data         []DiseaseComparisonPoint `json:"data"`
+	MissingYears []int                    `json:"missingYears"` // requested years without any record
+	Provenance
 }
 
 // DiseaseComparisonPoint represents data for a single year in comparison
 type DiseaseComparisonPoint struct {
-	Year  int `json:"year"`
-	Cases int `json:"cases"`
+	Year             int              `json:"year"`
+	Cases            int              `json:"cases"`
+	IncidencePer100k *float64         `json:"incidencePer100k"` // null without a stored population
+	DeltaCases       *int             `json:"deltaCases"`       // against the baseline year
+	DeltaPercent     *float64         `json:"deltaPercent"`     // null when the baseline has no cases
+	Quarters         []QuarterlyValue `json:"quarters"`
+	MissingQuarters  []int            `json:"missingQuarters"`
+}
+
+// QuarterlyValue holds the cases of one quarter of a compared year
+type QuarterlyValue struct {
+	Quarter          int      `json:"quarter"`
+	Cases            int      `json:"cases"`
+	IncidencePer100k *float64 `json:"incidencePer100k"`
 }
 
 // DiseasePrediction represents disease prediction results
 type DiseasePrediction struct {
-	Year             int `json:"year"`
-	Q1PredictedCases int `json:"q1_predicted_cases"`
-	Q2PredictedCases int `json:"q2_predicted_cases"`
-	Q3PredictedCases int `json:"q3_predicted_cases"`
-	Q4PredictedCases int `json:"q4_predicted_cases"`
+	Disease          string              `json:"disease"`
+	Year             int                 `json:"year"`
+	Q1PredictedCases int                 `json:"q1_predicted_cases"`
+	Q2PredictedCases int                 `json:"q2_predicted_cases"`
+	Q3PredictedCases int                 `json:"q3_predicted_cases"`
+	Q4PredictedCases int                 `json:"q4_predicted_cases"`
+	Model            string              `json:"model"`
+	Level            float64             `json:"level"` // coverage of the prediction intervals, e.g. 0.95
+	Quarters         []QuarterPrediction `json:"quarters"`
+	Params           map[string]float64  `json:"params,omitempty"` // fitted model parameters
+	TrainingStart    YearQuarter         `json:"training_start"`
+	TrainingEnd      YearQuarter         `json:"training_end"`
+	Accuracy         *BacktestResult     `json:"accuracy"` // latest stored backtest of the model at this horizon and level, if any
+	Provenance
+}
+
+// QuarterPrediction is the point forecast and prediction interval of one quarter
+type QuarterPrediction struct {
+	Quarter        int     `json:"quarter"`
+	PredictedCases float64 `json:"predicted_cases"`
+	Lower          float64 `json:"lower"`
+	Upper          float64 `json:"upper"`
+}
+
+// YearQuarter identifies a quarter
+type YearQuarter struct {
+	Year    int `json:"year"`
+	Quarter int `json:"quarter"`
+}
+
+// ForecastRequest represents parameters for a disease forecast
+type ForecastRequest struct {
+	DiseaseID string  `json:"disease_id"`
+	Year      int     `json:"year"`
+	Quarters  []int   `json:"quarters,omitempty"` // defaults to all four
+	Model     string  `json:"model,omitempty"`
+	Level     float64 `json:"level,omitempty"`
+}
+
+// ForecastResponse represents the forecast of the requested quarters
+type ForecastResponse struct {
+	DiseaseID           string         `json:"disease_id"`
+	Year                int            `json:"year"`
+	Model               string         `json:"model"`
+	Level               float64        `json:"level"`
+	Quarters            []int          `json:"quarters"`
+	Forecast            []int          `json:"forecast"`
+	ConfidenceIntervals []ConfInterval `json:"confidence_intervals"`
+	Provenance
+}
+
+// BacktestRequest represents parameters for a rolling-origin forecast backtest
+type BacktestRequest struct {
+	DiseaseIDs   []string `json:"disease_ids,omitempty"` // defaults to every disease
+	Models       []string `json:"models,omitempty"`      // defaults to every model
+	Horizon      int      `json:"horizon,omitempty"`     // quarters ahead, defaults to 4
+	Level        float64  `json:"level,omitempty"`
+	StartYear    int      `json:"start_year,omitempty"` // first origin, defaults to 2015 Q1
+	StartQuarter int      `json:"start_quarter,omitempty"`
+}
+
+// BacktestResult holds the accuracy of one model on one disease series
+type BacktestResult struct {
+	Disease        string      `json:"disease"`
+	Model          string      `json:"model"`
+	Horizon        int         `json:"horizon"`
+	Level          float64     `json:"level"`
+	FirstOrigin    YearQuarter `json:"first_origin"`
+	LastOrigin     YearQuarter `json:"last_origin"`
+	Origins        int         `json:"origins"`
+	SkippedOrigins int         `json:"skipped_origins"`
+	Forecasts      int         `json:"forecasts"`
+	MAE            float64     `json:"mae"`
+	MAPE           *float64    `json:"mape"` // null when every actual is zero
+	SMAPE          float64     `json:"smape"`
+	MASE           *float64    `json:"mase"` // null without seasonal variation in training
+	Coverage       float64     `json:"coverage"`
+	RunAt          time.Time   `json:"run_at"`
+}
+
+// BacktestReport is the outcome of a backtest run
+type BacktestReport struct {
+	Results []BacktestResult `json:"results"`
+	Skipped []BacktestSkip   `json:"skipped"`
+}
+
+// BacktestSkip names a disease and model pair that could not be backtested
+type BacktestSkip struct {
+	Disease string `json:"disease"`
+	Model   string `json:"model"`
+	Reason  string `json:"reason"`
 }
 
 // QueryResult represents an AI query response
@@ -67,4 +260,125 @@ type QueryResult struct {
 	Answer      string      `json:"answer"`
 	Data        interface{} `json:"data,omitempty"`
 	Explanation string      `json:"explanation,omitempty"`
+	Provenance
+}
+
+// Standard populations of an age standardization
+const (
+	StandardESP2013 = "esp2013" // European Standard Population 2013
+	StandardWHO     = "who"     // WHO world standard population 2000-2025
+	StandardMoldova = "moldova" // Moldova's population in a reference year
+)
+
+// StandardizedRatesRequest selects a stratified indicator to standardize by age. Rates are computed
+// per year and per stratum of the dimensions in group_by, sex and medium; the others are summed over.
+type StandardizedRatesRequest struct {
+	Indicator     string   `json:"indicator"`
+	Standard      string   `json:"standard,omitempty"`       // StandardESP2013 by default
+	ReferenceYear int      `json:"reference_year,omitempty"` // StandardMoldova: the latest population year by default
+	StartYear     *int     `json:"start_year,omitempty"`
+	EndYear       *int     `json:"end_year,omitempty"`
+	Sexes         []string `json:"sexes,omitempty"`
+	Media         []string `json:"media,omitempty"`
+	GroupBy       []string `json:"group_by,omitempty"`
+	Level         float64  `json:"level,omitempty"` // confidence level, 0.95 by default
+}
+
+// StandardizedRate is the crude and directly age-standardized rate per 100,000 of one year and
+// stratum, with the gamma interval of Fay and Feuer for the standardized rate
+type StandardizedRate struct {
+	Year               int             `json:"year"`
+	Sex                string          `json:"sex,omitempty"`
+	Medium             string          `json:"medium,omitempty"`
+	Events             float64         `json:"events"`
+	Population         float64         `json:"population"` // mid-year population of the age groups with events data
+	CrudeRate          float64         `json:"crude_rate"`
+	StandardizedRate   float64         `json:"standardized_rate"`
+	ConfidenceInterval ConfInterval    `json:"confidence_interval"`
+	UnknownAgeEvents   float64         `json:"unknown_age_events"` // events of undeclared age, left out of both rates
+	Suppressed         SuppressedCells `json:"suppressed"`
+}
+
+// StandardizedRates holds the age-standardized rates of an indicator against a standard population
+// whose weights, spread evenly over the single years of each standard band, are renormalised over
+// the indicator's age groups
+type StandardizedRates struct {
+	Indicator     string             `json:"indicator"`
+	Standard      string             `json:"standard"`
+	ReferenceYear int                `json:"reference_year,omitempty"`
+	AgeGroups     []string           `json:"age_groups"`
+	Weights       []float64          `json:"weights"` // standard weights of the age groups, summing to 1
+	Level         float64            `json:"level"`
+	Rates         []StandardizedRate `json:"rates"`
+	Provenance
+}
+
+// VaccinationRequest selects the antigens and years of a vaccination coverage analysis
+type VaccinationRequest struct {
+	Antigens  []string `json:"antigens"` // all when empty
+	StartYear *int     `json:"start_year"`
+	EndYear   *int     `json:"end_year"`
+	Target    float64  `json:"target,omitempty"`  // percent; defaults to WHOCoverageTarget
+	MaxLag    int      `json:"max_lag,omitempty"` // years coverage leads incidence by, 0..MaxLag
+}
+
+// VaccinationAnalysis reports the coverage trend of each antigen against the target and relates
+// coverage to the later incidence of the diseases the vaccine protects against
+type VaccinationAnalysis struct {
+	Target      float64           `json:"target"`
+	MaxLag      int               `json:"max_lag"`
+	Level       float64           `json:"level"`
+	Antigens    []AntigenCoverage `json:"antigens"`
+	BelowTarget []string          `json:"below_target"` // antigens whose latest coverage misses the target
+	Provenance
+}
+
+// AntigenCoverage is the coverage of one antigen at the age it is reported for
+type AntigenCoverage struct {
+	Antigen          string              `json:"antigen"`
+	Name             string              `json:"name"`
+	Age              string              `json:"age"`
+	Diseases         []string            `json:"diseases"`
+	Points           []CoveragePoint     `json:"points"`
+	Latest           *CoveragePoint      `json:"latest"` // latest observed figure
+	BelowTarget      bool                `json:"below_target"`
+	YearsBelowTarget int                 `json:"years_below_target"`
+	Trend            *float64            `json:"trend"` // least-squares slope, percentage points per year
+	Incidence        []CoverageIncidence `json:"incidence"`
+}
+
+// CoveragePoint is the coverage of an antigen in one year
+type CoveragePoint struct {
+	Year        int         `json:"year"`
+	Coverage    float64     `json:"coverage"`
+	Status      ValueStatus `json:"status"`
+	BelowTarget bool        `json:"below_target"`
+}
+
+// CoverageIncidence relates the coverage of an antigen to the yearly incidence of a disease it
+// protects against, coverage leading by 0..MaxLag years. Negative coefficients mean higher
+// coverage went with fewer cases.
+type CoverageIncidence struct {
+	Disease string           `json:"disease"`
+	Points  []IncidencePoint `json:"points"`
+	Lags    []LagCorrelation `json:"lags"`
+	BestLag *LagCorrelation  `json:"best_lag"`
+}
+
+// IncidencePoint is the yearly incidence of a disease; years with a suppressed or missing quarter
+// are left out
+type IncidencePoint struct {
+	Year          int      `json:"year"`
+	Cases         uint64   `json:"cases"`
+	IncidenceRate *float64 `json:"incidence_rate"` // per 100,000; null without a population
+}
+
+// Provenance marks responses built from synthetic demo data instead of stored records
+type Provenance struct {
+	Synthetic bool `json:"synthetic,omitempty"`
+}
+
+// IsSynthetic reports whether the response was built from synthetic demo data
+func (p Provenance) IsSynthetic() bool {
+	return p.Synthetic
 }
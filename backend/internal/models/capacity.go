@@ -0,0 +1,87 @@
+package models
+
+// BedProfileTotal is the profile of the series summing the beds of the selected profiles
+const BedProfileTotal = "total"
+
+// BedProfile is a hospital bed profile with the disease categories whose cases its beds treat
+type BedProfile struct {
+	Profile    string   `json:"profile" ch:"profile"` // as named by Statbank, e.g. "Infectioase"
+	Name       string   `json:"name" ch:"name"`
+	Categories []string `json:"categories" ch:"categories"`
+}
+
+// HospitalBeds is the number of hospital beds of a profile in a year
+type HospitalBeds struct {
+	Profile string      `json:"profile" ch:"profile"`
+	Year    uint16      `json:"year" ch:"year"`
+	Beds    uint32      `json:"beds" ch:"beds"`
+	Status  ValueStatus `json:"status" ch:"status"`
+}
+
+// CapacityFilter selects bed counts
+type CapacityFilter struct {
+	Profiles  []string `json:"profiles" form:"profiles"`
+	StartYear *int     `json:"startYear" form:"startYear"`
+	EndYear   *int     `json:"endYear" form:"endYear"`
+}
+
+// CapacityPoint is the beds of a profile in one year
+type CapacityPoint struct {
+	Year       int             `json:"year"`
+	Beds       uint32          `json:"beds"`       // over the reported profiles only
+	BedsPer10k *float64        `json:"bedsPer10k"` // nil without a mid-year population
+	Status     ValueStatus     `json:"status"`
+	Suppressed SuppressedCells `json:"suppressed"`
+}
+
+// CapacitySeries is the yearly bed series of a profile, or of BedProfileTotal
+type CapacitySeries struct {
+	Profile string          `json:"profile"`
+	Name    string          `json:"name"`
+	Points  []CapacityPoint `json:"points"`
+}
+
+// BurdenPoint relates the beds of a profile to the national cases of its categories in a year
+type BurdenPoint struct {
+	Year        int             `json:"year"`
+	Beds        uint32          `json:"beds"`
+	BedsPer10k  *float64        `json:"bedsPer10k"`
+	Cases       uint64          `json:"cases"`       // over the reported cells only
+	CasesPerBed *float64        `json:"casesPerBed"` // nil without beds
+	Suppressed  SuppressedCells `json:"suppressed"`  // case cells left out
+}
+
+// BedCaseCorrelation is the Pearson correlation of the yearly beds and cases of a profile
+type BedCaseCorrelation struct {
+	Coefficient float64 `json:"coefficient"`
+	PValue      float64 `json:"pValue"`
+	Lower       float64 `json:"lower"` // 95% confidence interval
+	Upper       float64 `json:"upper"`
+	SampleSize  int     `json:"sampleSize"`
+}
+
+// CapacityBurden is the yearly burden on the beds of a profile over the years both its beds and
+// the cases of all four quarters were reported
+type CapacityBurden struct {
+	Profile     string              `json:"profile"`
+	Name        string              `json:"name"`
+	Categories  []string            `json:"categories"`
+	Points      []BurdenPoint       `json:"points"`
+	Correlation *BedCaseCorrelation `json:"correlation"` // nil over too few years
+}
+
+// ProfileLoad is the latest burden on the beds of a profile
+type ProfileLoad struct {
+	Profile string `json:"profile"`
+	Name    string `json:"name"`
+	BurdenPoint
+}
+
+// CapacitySummary is the hospital capacity of the latest year with bed counts, as shown on the
+// dashboard
+type CapacitySummary struct {
+	Year       int           `json:"year"`
+	Beds       uint32        `json:"beds"`
+	BedsPer10k *float64      `json:"bedsPer10k"`
+	Burden     []ProfileLoad `json:"burden"`
+}
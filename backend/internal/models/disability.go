@@ -0,0 +1,59 @@
+package models
+
+// DisabilityFilter selects the disability rates of adults and how they are grouped, listed and
+// ordered. The years, strata and grouping are those of a StratifiedFilter, the ages those of the
+// disability facts ("18-29" to "50+"); sorting and offset pagination are those of a DiseaseFilter.
+type DisabilityFilter struct {
+	StartYear *int     `json:"startYear" form:"startYear"`
+	EndYear   *int     `json:"endYear" form:"endYear"`
+	Ages      []string `json:"ages" form:"ages"`
+	Sexes     []string `json:"sexes" form:"sexes"`
+	Media     []string `json:"media" form:"media"`
+	GroupBy   []string `json:"groupBy" form:"groupBy"`
+	Sort      string   `json:"sort" form:"sort"` // e.g. "-ratePer10k,year"; takes precedence over SortBy
+	SortBy    string   `json:"sortBy" form:"sortBy"`
+	SortOrder string   `json:"sortOrder" form:"sortOrder"`
+	Limit     int      `json:"limit" form:"limit"`
+	Offset    int      `json:"offset" form:"offset"`
+}
+
+// DisabilityPoint is the disability rate of a stratum in one year against its mid-year adult
+// population, and its change from the year before
+type DisabilityPoint struct {
+	Year          int             `json:"year"`
+	Persons       float64         `json:"persons"`       // over the reported cells only
+	Adults        float64         `json:"adults"`        // mid-year population of the reported cells
+	RatePer10k    *float64        `json:"ratePer10k"`    // nil without a population
+	Change        *float64        `json:"change"`        // rate difference, nil without last year's rate
+	ChangePercent *float64        `json:"changePercent"` // nil when last year's rate was zero
+	Status        ValueStatus     `json:"status"`
+	Suppressed    SuppressedCells `json:"suppressed"`
+}
+
+// DisabilityRate is a row of the disability table: one stratum in one year. Dimensions summed over
+// are left empty.
+type DisabilityRate struct {
+	Age    string `json:"age,omitempty"`
+	Sex    string `json:"sex,omitempty"`
+	Medium string `json:"medium,omitempty"`
+	DisabilityPoint
+}
+
+// DisabilityPage is one page of the disability table
+type DisabilityPage struct {
+	Rates      []DisabilityRate `json:"rates"`
+	TotalCount int              `json:"totalCount"`
+	Page       int              `json:"page"`
+	TotalPages int              `json:"totalPages"`
+	Limit      int              `json:"limit"`
+	HasMore    bool             `json:"hasMore"`
+}
+
+// DisabilitySeries is the yearly disability rate of one stratum; dimensions summed over are left
+// empty
+type DisabilitySeries struct {
+	Age    string            `json:"age,omitempty"`
+	Sex    string            `json:"sex,omitempty"`
+	Medium string            `json:"medium,omitempty"`
+	Points []DisabilityPoint `json:"points"`
+}
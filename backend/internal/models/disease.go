@@ -8,6 +8,7 @@ type Disease struct {
 	Year            uint16         `json:"year" ch:"year"`       // Changed from int to uint16 for ClickHouse compatibility
 	Quarter         uint8          `json:"quarter" ch:"quarter"` // Changed from int to uint8
 	Region          string         `json:"region" ch:"region"`
+	AgeGroup        string         `json:"ageGroup" ch:"age_group"`    // AgeGroupAll when empty
 	Cases           uint32         `json:"cases" ch:"cases"`           // Changed from int to uint32
 	Deaths          uint32         `json:"deaths" ch:"deaths"`         // Changed from int to uint32
 	Recoveries      uint32         `json:"recoveries" ch:"recoveries"` // Changed from int to uint32
@@ -15,7 +16,90 @@ type Disease struct {
 	IncidenceRate   float64        `json:"incidenceRate" ch:"incidence_rate"`
 	PrevalenceRate  float64        `json:"prevalenceRate" ch:"prevalence_rate"` // Added prevalence rate
 	MortalityRate   float64        `json:"mortalityRate" ch:"mortality_rate"`
-	EnvironmentData map[string]any `json:"environmentData,omitempty" ch:"-"`
+	Status          ValueStatus    `json:"status" ch:"status"`               // status of the source cell behind Cases
+	EnvironmentData map[string]any `json:"environmentData,omitempty" ch:"-"` // filled on request with IncludeEnvironment
+}
+
+// Age groups of disease records. The records of all ages count every case; those of children
+// count the cases aged 0-17 among them, so the two are never summed together.
+const (
+	AgeGroupAll      = "all"
+	AgeGroupChildren = "children"
+)
+
+// ChildAgeLimit is the first age not counted among children
+const ChildAgeLimit = 18
+
+// ValueStatus says whether a source cell reported a value
+type ValueStatus string
+
+const (
+	// StatusObserved marks a reported value
+	StatusObserved ValueStatus = "observed"
+	// StatusZero marks a cell reported as nil ("-"), a true zero
+	StatusZero ValueStatus = "zero"
+	// StatusNotAvailable marks a cell whose value was not available ("..")
+	StatusNotAvailable ValueStatus = "not_available"
+	// StatusConfidential marks a cell withheld for confidentiality ("C")
+	StatusConfidential ValueStatus = "confidential"
+)
+
+// Suppressed reports whether the cell withholds its value; its counts are then 0 and must not be
+// read as zeros. An empty status counts as observed.
+func (s ValueStatus) Suppressed() bool {
+	return s == StatusNotAvailable || s == StatusConfidential
+}
+
+// Valid reports whether s is a known status or empty
+func (s ValueStatus) Valid() bool {
+	switch s {
+	case "", StatusObserved, StatusZero, StatusNotAvailable, StatusConfidential:
+		return true
+	}
+	return false
+}
+
+// SuppressedCells counts the source cells an aggregate leaves out because they withhold their value
+type SuppressedCells struct {
+	NotAvailable int `json:"notAvailable"`
+	Confidential int `json:"confidential"`
+}
+
+// Add counts a cell of the given status if it is suppressed
+func (c *SuppressedCells) Add(status ValueStatus) {
+	switch status {
+	case StatusNotAvailable:
+		c.NotAvailable++
+	case StatusConfidential:
+		c.Confidential++
+	}
+}
+
+// Merge adds the counts of other
+func (c *SuppressedCells) Merge(other SuppressedCells) {
+	c.NotAvailable += other.NotAvailable
+	c.Confidential += other.Confidential
+}
+
+// Total returns the number of suppressed cells
+func (c SuppressedCells) Total() int {
+	return c.NotAvailable + c.Confidential
+}
+
+// AggregateStatus returns the status of an aggregate over reported cells summing to cases and the
+// suppressed cells: observed or zero when any cell reported, otherwise confidential when any cell
+// was confidential and not available when none was
+func AggregateStatus(reported int, cases uint64, suppressed SuppressedCells) ValueStatus {
+	switch {
+	case reported > 0 && cases > 0:
+		return StatusObserved
+	case reported > 0:
+		return StatusZero
+	case suppressed.Confidential > 0:
+		return StatusConfidential
+	default:
+		return StatusNotAvailable
+	}
 }
 
 // DiseaseFilter contains filter parameters for disease data queries
@@ -25,39 +109,144 @@ type DiseaseFilter struct {
 	Quarters   []int    `json:"quarters" form:"quarters"`
 	Regions    []string `json:"regions" form:"regions"`
 	Categories []string `json:"categories" form:"categories"`
+	Names      []string `json:"names" form:"names"`
+	AgeGroup   string   `json:"ageGroup" form:"ageGroup"` // defaults to AgeGroupAll
 	DiseaseIDs []string `json:"diseaseIds" form:"diseaseIds"`
 	MinCases   *int     `json:"minCases" form:"minCases"`
 	MaxCases   *int     `json:"maxCases" form:"maxCases"`
+	Sort       string   `json:"sort" form:"sort"` // e.g. "-cases,name,year"; takes precedence over SortBy
 	SortBy     string   `json:"sortBy" form:"sortBy"`
 	SortOrder  string   `json:"sortOrder" form:"sortOrder"`
 	Limit      int      `json:"limit" form:"limit"`
 	Offset     int      `json:"offset" form:"offset"`
+	Cursor     string   `json:"cursor" form:"cursor"`   // opaque keyset position; excludes Offset
+	Include    []string `json:"include" form:"include"` // related data to join in, e.g. IncludeEnvironment
+}
+
+// IncludeEnvironment joins the quarterly environment rollups of each record's quarter into
+// Disease.EnvironmentData
+const IncludeEnvironment = "environment"
+
+// DiseasePage is one page of a disease listing
+type DiseasePage struct {
+	Diseases   []Disease `json:"diseases"`
+	TotalCount int       `json:"totalCount"`
+	Page       int       `json:"page,omitempty"` // only known for offset pagination
+	TotalPages int       `json:"totalPages"`
+	Limit      int       `json:"limit"`
+	HasMore    bool      `json:"hasMore"`
+	NextCursor string    `json:"nextCursor,omitempty"`
 }
 
 // DiseaseStats represents aggregated disease statistics
 type DiseaseStats struct {
-	TotalCases      int     `json:"totalCases"`
-	TotalDeaths     int     `json:"totalDeaths"`
-	TotalRecoveries int     `json:"totalRecoveries"`
-	AverageRate     float64 `json:"averageRate"`
-	TrendDirection  string  `json:"trendDirection"`
-	ChangePercent   float64 `json:"changePercent"`
+	TotalCases      int               `json:"totalCases"`
+	TotalDeaths     int               `json:"totalDeaths"`
+	TotalRecoveries int               `json:"totalRecoveries"`
+	AverageRate     float64           `json:"averageRate"`
+	TrendDirection  string            `json:"trendDirection"` // increasing, decreasing, stable or insufficient_data
+	ChangePercent   float64           `json:"changePercent"`  // 0 when the previous period has no cases
+	Comparison      *PeriodComparison `json:"comparison"`
+	Suppressed      SuppressedCells   `json:"suppressed"` // cells left out of the totals
+	Capacity        *CapacitySummary  `json:"capacity,omitempty"`
+}
+
+// ComparisonWindow selects the period a stats trend is measured against
+type ComparisonWindow string
+
+const (
+	// ComparePriorYear compares the latest year with the year before over the same quarters
+	ComparePriorYear ComparisonWindow = "prior_year"
+	// ComparePriorQuarter compares the latest quarter with the quarter before it
+	ComparePriorQuarter ComparisonWindow = "prior_quarter"
+	// CompareSameQuarterLastYear compares the latest quarter with the same quarter a year earlier
+	CompareSameQuarterLastYear ComparisonWindow = "same_quarter_last_year"
+)
+
+// PeriodComparison describes the two periods behind a trend and the counts compared
+type PeriodComparison struct {
+	Window        ComparisonWindow `json:"window"`
+	Current       *Period          `json:"current,omitempty"`
+	Previous      *Period          `json:"previous,omitempty"`
+	CurrentCases  int              `json:"currentCases"`
+	PreviousCases int              `json:"previousCases"`
+	ZScore        float64          `json:"zScore"`
+	Significant   bool             `json:"significant"`
+}
+
+// Period is a year and the quarters of it covered by a comparison
+type Period struct {
+	Year     int   `json:"year"`
+	Quarters []int `json:"quarters"`
 }
 
 // DiseaseTimePoint represents a single data point in a time series
 type DiseaseTimePoint struct {
-	Year          int     `json:"year"`
-	Quarter       int     `json:"quarter"`
-	Name          string  `json:"name"`
-	Cases         uint64  `json:"cases"`
-	IncidenceRate float64 `json:"incidenceRate"`
-	MortalityRate float64 `json:"mortalityRate"`
-	RecoveryRate  float64 `json:"recoveryRate"`
+	Year          int             `json:"year"`
+	Quarter       int             `json:"quarter"`
+	Name          string          `json:"name"`
+	Cases         uint64          `json:"cases"` // over the reported cells only
+	IncidenceRate float64         `json:"incidenceRate"`
+	MortalityRate float64         `json:"mortalityRate"`
+	RecoveryRate  float64         `json:"recoveryRate"`
+	Status        ValueStatus     `json:"status"` // see AggregateStatus
+	Suppressed    SuppressedCells `json:"suppressed"`
+}
+
+// Reported reports whether any cell behind the point reported a value; points that are not
+// reported are gaps rather than zeros
+func (p DiseaseTimePoint) Reported() bool {
+	return !p.Status.Suppressed()
 }
 
 // TimeSeries represents a collection of disease data points over time
 type TimeSeries struct {
-	Points []DiseaseTimePoint `json:"points"`
+	Points     []DiseaseTimePoint `json:"points"`
+	Suppressed SuppressedCells    `json:"suppressed"` // over all points
+	Provenance
+}
+
+// AgeSegmentPoint splits the cases of one quarter between children and adults, the cases of all
+// ages less those of children
+type AgeSegmentPoint struct {
+	Year            int             `json:"year"`
+	Quarter         int             `json:"quarter"`
+	AllAges         uint64          `json:"allAges"`
+	Children        uint64          `json:"children"`
+	Adults          uint64          `json:"adults"`
+	PaediatricShare *float64        `json:"paediatricShare"` // children in percent of all ages; nil without cases
+	Status          ValueStatus     `json:"status"`          // see AggregateStatus
+	Suppressed      SuppressedCells `json:"suppressed"`      // cells of either age group left out
+}
+
+// AgeSegmentSeries is the quarterly split of the cases of a disease, or of every disease matched
+// when Name is empty
+type AgeSegmentSeries struct {
+	Name   string            `json:"name,omitempty"`
+	Points []AgeSegmentPoint `json:"points"`
+}
+
+// PaediatricBreakdown splits the cases matching a filter between children and adults per disease
+// and in total, over the diseases and quarters reported in both age groups
+type PaediatricBreakdown struct {
+	Diseases   []AgeSegmentSeries `json:"diseases"`
+	Total      AgeSegmentSeries   `json:"total"`
+	Suppressed SuppressedCells    `json:"suppressed"` // over all points
+}
+
+// RegionalDistribution represents the geographic distribution of disease cases
+type RegionalDistribution struct {
+	Regions []RegionCases `json:"regions"`
+	Provenance
+}
+
+// RegionCases holds the cases recorded in one region; coordinates are omitted for unknown regions
+type RegionCases struct {
+	Name     string   `json:"name"`
+	Cases    uint64   `json:"cases"`
+	Lat      *float64 `json:"lat,omitempty"`
+	Lon      *float64 `json:"lon,omitempty"`
+	Diseases []string `json:"diseases"`
 }
 
 // DiseaseQuery represents a natural language query for the AI system
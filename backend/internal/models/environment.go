@@ -0,0 +1,136 @@
+package models
+
+// Environmental variables measured at the meteorological stations
+const (
+	VariableAirTemperature = "air_temperature"
+	VariablePrecipitation  = "precipitation"
+	VariableWindSpeed      = "wind_speed"
+)
+
+// Ways monthly observations are rolled up into quarters
+const (
+	AggregateMean = "mean"
+	AggregateSum  = "sum"
+)
+
+// EnvironmentVariable describes an environmental variable and how it is rolled up into quarters
+type EnvironmentVariable struct {
+	Name        string `json:"name"`
+	Unit        string `json:"unit"`
+	Aggregation string `json:"aggregation"`
+}
+
+// EnvironmentVariables lists the known variables; amounts such as precipitation are summed over a
+// quarter while levels such as temperature are averaged
+var EnvironmentVariables = map[string]EnvironmentVariable{
+	VariableAirTemperature: {Name: VariableAirTemperature, Unit: "°C", Aggregation: AggregateMean},
+	VariablePrecipitation:  {Name: VariablePrecipitation, Unit: "mm", Aggregation: AggregateSum},
+	VariableWindSpeed:      {Name: VariableWindSpeed, Unit: "m/s", Aggregation: AggregateMean},
+}
+
+// VariableAggregation returns the quarterly aggregation of a variable, the mean for unknown ones
+func VariableAggregation(variable string) string {
+	if v, ok := EnvironmentVariables[variable]; ok {
+		return v.Aggregation
+	}
+	return AggregateMean
+}
+
+// EnvironmentObservation is the monthly value of a variable at a station
+type EnvironmentObservation struct {
+	Station  string  `json:"station" ch:"station"`
+	Variable string  `json:"variable" ch:"variable"`
+	Year     uint16  `json:"year" ch:"year"`
+	Month    uint8   `json:"month" ch:"month"`
+	Value    float64 `json:"value" ch:"value"`
+}
+
+// EnvironmentPoint is the quarterly or monthly value of an environmental factor
+type EnvironmentPoint struct {
+	Year    int     `json:"year"`
+	Quarter int     `json:"quarter"`
+	Month   int     `json:"month,omitempty"` // set on monthly values only
+	Factor  string  `json:"factor"`
+	Value   float64 `json:"value"`
+}
+
+// EnvironmentFilter selects environment observations; the year and quarter conditions mirror
+// those of DiseaseFilter
+type EnvironmentFilter struct {
+	Variables   []string `json:"variables" form:"variables"`
+	Stations    []string `json:"stations" form:"stations"`
+	StartYear   *int     `json:"startYear" form:"startYear"`
+	EndYear     *int     `json:"endYear" form:"endYear"`
+	Quarters    []int    `json:"quarters" form:"quarters"`
+	Granularity string   `json:"granularity" form:"granularity"` // GranularityQuarter (default) or GranularityMonth
+}
+
+// Granularities of an environment series
+const (
+	GranularityQuarter = "quarter"
+	GranularityMonth   = "month"
+	GranularityYear    = "year" // of correlations involving a yearly exposure
+)
+
+// EnvironmentSeries is the series of one variable at one station
+type EnvironmentSeries struct {
+	Station     string             `json:"station"`
+	Variable    string             `json:"variable"`
+	Unit        string             `json:"unit,omitempty"`
+	Aggregation string             `json:"aggregation"`
+	Granularity string             `json:"granularity"`
+	Points      []EnvironmentPoint `json:"points"`
+}
+
+// Station is a measuring station with the variables and years it has observations for
+type Station struct {
+	Name      string   `json:"name"`
+	Variables []string `json:"variables"`
+	FirstYear int      `json:"firstYear"`
+	LastYear  int      `json:"lastYear"`
+}
+
+// Pollutants emitted into the air by road transport, yearly environmental exposures
+const (
+	PollutantCarbonMonoxide  = "carbon_monoxide"
+	PollutantNitrogenDioxide = "nitrogen_dioxide"
+	PollutantSulphurDioxide  = "sulphur_dioxide"
+	PollutantHydrocarbons    = "hydrocarbons"
+)
+
+// EmissionUnit is the unit emissions are reported in
+const EmissionUnit = "thousand tonnes"
+
+// Pollutants lists the known pollutants
+var Pollutants = []string{
+	PollutantCarbonMonoxide, PollutantHydrocarbons, PollutantNitrogenDioxide, PollutantSulphurDioxide,
+}
+
+// Emission is the yearly emission of a pollutant into the air by road transport
+type Emission struct {
+	Pollutant string      `json:"pollutant" ch:"pollutant"`
+	Year      uint16      `json:"year" ch:"year"`
+	Value     float64     `json:"value" ch:"value"` // in EmissionUnit
+	Status    ValueStatus `json:"status" ch:"status"`
+}
+
+// EmissionFilter selects emissions
+type EmissionFilter struct {
+	Pollutants []string `json:"pollutants" form:"pollutants"`
+	StartYear  *int     `json:"startYear" form:"startYear"`
+	EndYear    *int     `json:"endYear" form:"endYear"`
+}
+
+// EmissionPoint is the emission of a pollutant in one year
+type EmissionPoint struct {
+	Year   int         `json:"year"`
+	Value  float64     `json:"value"`
+	Status ValueStatus `json:"status"`
+}
+
+// EmissionSeries is the yearly series of one pollutant
+type EmissionSeries struct {
+	Pollutant string          `json:"pollutant"`
+	Unit      string          `json:"unit"`
+	Points    []EmissionPoint `json:"points"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when a requested record does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidFilter is returned when filter, sort or pagination parameters are invalid
+	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrInvalidRecord is returned when a record to store has inconsistent fields
+	ErrInvalidRecord = errors.New("invalid record")
+	// ErrNotImplemented is returned by computations that have no real implementation yet;
+	// synthetic answers for them are only available in demo mode
+	ErrNotImplemented = errors.New("not implemented")
+	// ErrInsufficientData is returned when the stored data cannot support the requested computation
+	ErrInsufficientData = errors.New("insufficient data")
+)
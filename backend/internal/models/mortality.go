@@ -0,0 +1,83 @@
+package models
+
+// LifeTableRow is one age group of an abridged life table
+type LifeTableRow struct {
+	Age                    string   `json:"age"` // e.g. "0", "1-4", "5-9" or "85+"
+	Deaths                 float64  `json:"deaths"`
+	Population             float64  `json:"population"` // mid-year population
+	Mx                     float64  `json:"mx"`         // death rate
+	Ax                     float64  `json:"ax"`         // average years lived in the group by those dying in it
+	Qx                     float64  `json:"qx"`         // probability of dying in the group
+	Lx                     float64  `json:"lx"`         // survivors at the start of the group out of 100,000 births
+	Dx                     float64  `json:"dx"`         // deaths in the group out of 100,000 births
+	PersonYears            float64  `json:"personYears"`
+	Tx                     float64  `json:"tx"` // person-years lived from the start of the group
+	Ex                     float64  `json:"ex"` // life expectancy at the start of the group
+	OfficialLifeExpectancy *float64 `json:"officialLifeExpectancy,omitempty"`
+}
+
+// LifeTable is the abridged life table of a year and stratum computed from deaths and population;
+// sex and medium are empty when summed over. Official figures are published per sex and medium
+// only and are attached where they exist.
+type LifeTable struct {
+	Year             int            `json:"year"`
+	Sex              string         `json:"sex,omitempty"`
+	Medium           string         `json:"medium,omitempty"`
+	LifeExpectancy   float64        `json:"lifeExpectancy"`
+	UnknownAgeDeaths float64        `json:"unknownAgeDeaths"` // deaths of undeclared age, left out of the table
+	Rows             []LifeTableRow `json:"rows"`
+}
+
+// LifeExpectancyPoint compares the official and computed life expectancy of one year
+type LifeExpectancyPoint struct {
+	Year       int      `json:"year"`
+	Official   *float64 `json:"official"`
+	Computed   *float64 `json:"computed"`
+	Difference *float64 `json:"difference"` // computed minus official
+}
+
+// LifeExpectancySeries is the life expectancy at one age in one stratum over time
+type LifeExpectancySeries struct {
+	Age    string                `json:"age"`
+	Sex    string                `json:"sex"`
+	Medium string                `json:"medium"`
+	Points []LifeExpectancyPoint `json:"points"`
+}
+
+// LifeExpectancyContribution is the part of a change in life expectancy at birth due to the change
+// in mortality of one age group
+type LifeExpectancyContribution struct {
+	Age      string  `json:"age"`
+	Direct   float64 `json:"direct"`   // from the years lived within the group
+	Indirect float64 `json:"indirect"` // from the survivors the group passes on to older ages
+	Total    float64 `json:"total"`
+}
+
+// LifeExpectancyDecomposition is the Arriaga decomposition of the change in life expectancy at
+// birth between two years by age group; the contributions sum to the change
+type LifeExpectancyDecomposition struct {
+	FromYear           int                          `json:"fromYear"`
+	ToYear             int                          `json:"toYear"`
+	Sex                string                       `json:"sex,omitempty"`
+	Medium             string                       `json:"medium,omitempty"`
+	FromLifeExpectancy float64                      `json:"fromLifeExpectancy"`
+	ToLifeExpectancy   float64                      `json:"toLifeExpectancy"`
+	Change             float64                      `json:"change"`
+	Contributions      []LifeExpectancyContribution `json:"contributions"`
+}
+
+// LifeTableQuery selects the life table of one year and stratum; empty sex and medium sum over them
+type LifeTableQuery struct {
+	Year   *int   `json:"year" form:"year"` // the latest year with deaths when nil
+	Sex    string `json:"sex" form:"sex"`
+	Medium string `json:"medium" form:"medium"`
+}
+
+// DecompositionQuery selects the years and stratum of a life expectancy decomposition; the years
+// default to the first and last with deaths
+type DecompositionQuery struct {
+	FromYear *int   `json:"fromYear" form:"fromYear"`
+	ToYear   *int   `json:"toYear" form:"toYear"`
+	Sex      string `json:"sex" form:"sex"`
+	Medium   string `json:"medium" form:"medium"`
+}
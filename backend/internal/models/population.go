@@ -0,0 +1,47 @@
+package models
+
+// NationalRegion is the region of the records covering the whole country, the only region the
+// population table has denominators for
+const NationalRegion = "Republic of Moldova"
+
+// Values of the age, medium and sex strata
+const (
+	AgeTotal    = "total"
+	MediumUrban = "urban"
+	MediumRural = "rural"
+	SexMale     = "male"
+	SexFemale   = "female"
+)
+
+// PopulationEntry is the usual-residence population of one age, medium and sex on January 1
+type PopulationEntry struct {
+	Year       uint16 `json:"year" ch:"year"`
+	Age        string `json:"age" ch:"age"` // single years "0" to "84", "85+" or AgeTotal
+	Medium     string `json:"medium" ch:"medium"`
+	Sex        string `json:"sex" ch:"sex"`
+	Population uint32 `json:"population" ch:"population"`
+}
+
+// PopulationFilter selects population entries
+type PopulationFilter struct {
+	StartYear *int     `json:"startYear" form:"startYear"`
+	EndYear   *int     `json:"endYear" form:"endYear"`
+	Ages      []string `json:"ages" form:"ages"`
+	Media     []string `json:"media" form:"media"`
+	Sexes     []string `json:"sexes" form:"sexes"`
+}
+
+// PopulationDenominator is the population the rates of a year are computed against
+type PopulationDenominator struct {
+	Year         int     `json:"year"`
+	January1     uint64  `json:"january1"`
+	MidYear      float64 `json:"midYear"`
+	Interpolated bool    `json:"interpolated"` // false when a January 1 figure stands in for the mid-year one
+}
+
+// PopulationRevision reports what a population revision changed
+type PopulationRevision struct {
+	Entries        int   `json:"entries"`
+	Years          []int `json:"years"` // years whose denominators changed
+	RebasedRecords int   `json:"rebasedRecords"`
+}
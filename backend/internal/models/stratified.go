@@ -0,0 +1,95 @@
+package models
+
+// Indicators of the stratified fact table. The population indicator is served from the
+// population table, the others from the stratified facts.
+const (
+	IndicatorPopulation     = "population"
+	IndicatorDeaths         = "deaths"
+	IndicatorLifeExpectancy = "life_expectancy" // official life expectancy at the age of the fact
+	IndicatorDisability     = "disability"      // adults recognised with a primary disability in the year
+)
+
+// Additive reports whether the values of an indicator can be summed over strata; counts can,
+// life expectancies cannot
+func Additive(indicator string) bool {
+	return indicator != IndicatorLifeExpectancy
+}
+
+// Dimensions a stratified indicator is broken down by
+const (
+	DimensionAge    = "age"
+	DimensionSex    = "sex"
+	DimensionMedium = "medium"
+)
+
+// AgeUnknown is the age of the facts whose age was not declared
+const AgeUnknown = "unknown"
+
+// StratifiedFact is the yearly value of an indicator for one age, sex and medium. Ages are those
+// of the source: single years such as "7", groups such as "18-29", open groups such as "85+",
+// AgeUnknown or AgeTotal for the sum over all ages.
+type StratifiedFact struct {
+	Indicator string      `json:"indicator" ch:"indicator"`
+	Year      uint16      `json:"year" ch:"year"`
+	Age       string      `json:"age" ch:"age"`
+	Sex       string      `json:"sex" ch:"sex"`
+	Medium    string      `json:"medium" ch:"medium"`
+	Value     float64     `json:"value" ch:"value"`
+	Status    ValueStatus `json:"status" ch:"status"`
+}
+
+// StratifiedFilter selects the facts of an indicator and how they are grouped. Ages select source
+// ages; GroupBy names the dimensions kept apart, the others are summed over. With BandWidth set,
+// single ages are grouped into bands of that many years up to an open band from OpenAge.
+type StratifiedFilter struct {
+	Indicator string   `json:"indicator" form:"indicator"`
+	StartYear *int     `json:"startYear" form:"startYear"`
+	EndYear   *int     `json:"endYear" form:"endYear"`
+	Ages      []string `json:"ages" form:"ages"`
+	Sexes     []string `json:"sexes" form:"sexes"`
+	Media     []string `json:"media" form:"media"`
+	GroupBy   []string `json:"groupBy" form:"groupBy"`
+	BandWidth int      `json:"bandWidth" form:"bandWidth"`
+	OpenAge   int      `json:"openAge" form:"openAge"` // defaults to DefaultOpenAge
+}
+
+// DefaultOpenAge is the age from which banded ages fall into one open band, the highest single
+// age of the population table
+const DefaultOpenAge = 85
+
+// StratifiedPoint is the value of a stratified series in one year
+type StratifiedPoint struct {
+	Year       int             `json:"year"`
+	Value      float64         `json:"value"`
+	Status     ValueStatus     `json:"status"`
+	Suppressed SuppressedCells `json:"suppressed"`
+}
+
+// StratifiedSeries is the yearly series of an indicator in one stratum; dimensions summed over
+// are left empty
+type StratifiedSeries struct {
+	Indicator string            `json:"indicator"`
+	Age       string            `json:"age,omitempty"`
+	Sex       string            `json:"sex,omitempty"`
+	Medium    string            `json:"medium,omitempty"`
+	Points    []StratifiedPoint `json:"points"`
+}
+
+// PyramidBar is one age band of a pyramid
+type PyramidBar struct {
+	Age           string  `json:"age"`
+	Male          float64 `json:"male"`
+	Female        float64 `json:"female"`
+	MalePercent   float64 `json:"malePercent"`   // share of the pyramid total
+	FemalePercent float64 `json:"femalePercent"` // share of the pyramid total
+}
+
+// Pyramid is an indicator broken down by age band and sex in one year
+type Pyramid struct {
+	Indicator  string          `json:"indicator"`
+	Year       int             `json:"year"`
+	Media      []string        `json:"media"` // media summed over, both when empty in the request
+	Total      float64         `json:"total"`
+	Bars       []PyramidBar    `json:"bars"` // youngest first
+	Suppressed SuppressedCells `json:"suppressed"`
+}
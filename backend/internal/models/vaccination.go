@@ -0,0 +1,33 @@
+package models
+
+// VaccinationAgeNewborn is the age of the coverage of vaccines given within 30 days of birth;
+// the other ages are completed years such as "1"
+const VaccinationAgeNewborn = "newborn"
+
+// WHOCoverageTarget is the share of children the WHO targets for each antigen, in percent
+const WHOCoverageTarget = 95.0
+
+// Vaccine is an antigen of the coverage figures with the diseases it protects against, named as
+// in the disease records
+type Vaccine struct {
+	Antigen  string   `json:"antigen" ch:"antigen"` // as named by Statbank, e.g. "Rujeola"
+	Name     string   `json:"name" ch:"name"`
+	Diseases []string `json:"diseases" ch:"diseases"`
+}
+
+// VaccinationCoverage is the share of the children of an age vaccinated against an antigen in a
+// year, in percent
+type VaccinationCoverage struct {
+	Antigen  string      `json:"antigen" ch:"antigen"`
+	Age      string      `json:"age" ch:"age"`
+	Year     uint16      `json:"year" ch:"year"`
+	Coverage float64     `json:"coverage" ch:"coverage"`
+	Status   ValueStatus `json:"status" ch:"status"`
+}
+
+// VaccinationFilter selects coverage figures
+type VaccinationFilter struct {
+	Antigens  []string `json:"antigens" form:"antigens"`
+	StartYear *int     `json:"startYear" form:"startYear"`
+	EndYear   *int     `json:"endYear" form:"endYear"`
+}
@@ -0,0 +1,207 @@
+// Package lifetable builds abridged period life tables from deaths and mid-year population by age
+// group and decomposes the difference between the life expectancies of two tables by age.
+//
+// Tables follow Chiang's method as presented by Preston, Heuveline and Guillot (2001): the death
+// rates of the age groups are converted into probabilities of dying with the average person-years
+// lived by those dying in each group, which are taken from the Coale-Demeny model for the first
+// two groups and as half the group width above them. The last group is open-ended.
+package lifetable
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Radix is the number of births a table follows
+const Radix = 100000
+
+// Sexes selecting the Coale-Demeny separation factors; Both averages those of males and females
+const (
+	Male   = "male"
+	Female = "female"
+	Both   = ""
+)
+
+var (
+	// ErrNoPopulation is returned when an age group has no population to rate its deaths against
+	ErrNoPopulation = errors.New("age group without population")
+	// ErrNoDeaths is returned when the open age group has no deaths, leaving its life expectancy undefined
+	ErrNoDeaths = errors.New("open age group without deaths")
+	// ErrGroupsMismatch is returned when two tables do not share their age groups
+	ErrGroupsMismatch = errors.New("tables have different age groups")
+)
+
+// Group holds the deaths and mid-year population of an age group; a zero width marks the open group
+type Group struct {
+	Start      int
+	Width      int
+	Deaths     float64
+	Population float64
+}
+
+// StandardStarts are the first ages of the groups of an abridged table: 0, 1-4, five-year groups
+// and 85+
+var StandardStarts = []int{0, 1, 5, 10, 15, 20, 25, 30, 35, 40, 45, 50, 55, 60, 65, 70, 75, 80, 85}
+
+// Row is one age group of a life table
+type Row struct {
+	Start       int
+	Width       int     // 0 for the open group
+	Mx          float64 // death rate
+	Ax          float64 // average person-years lived in the group by those dying in it
+	Qx          float64 // probability of dying in the group
+	Lx          float64 // survivors at the start of the group
+	Dx          float64 // deaths in the group
+	PersonYears float64 // person-years lived in the group (nLx)
+	Tx          float64 // person-years lived from the start of the group
+	Ex          float64 // life expectancy at the start of the group
+	Deaths      float64
+	Exposure    float64 // mid-year population
+}
+
+// Table is an abridged life table, youngest group first
+type Table []Row
+
+// Abridged builds the life table of consecutive age groups starting at 0, the last of them open
+func Abridged(groups []Group, sex string) (Table, error) {
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("%w: no age groups", ErrNoPopulation)
+	}
+
+	table := make(Table, len(groups))
+	for i, g := range groups {
+		if g.Population <= 0 {
+			return nil, fmt.Errorf("%w: %d", ErrNoPopulation, g.Start)
+		}
+		table[i] = Row{Start: g.Start, Width: g.Width, Deaths: g.Deaths, Exposure: g.Population, Mx: g.Deaths / g.Population}
+	}
+
+	last := len(table) - 1
+	if table[last].Mx <= 0 {
+		return nil, ErrNoDeaths
+	}
+
+	l := float64(Radix)
+	for i := range table {
+		r := &table[i]
+		r.Lx = l
+		if i == last {
+			r.Ax = 1 / r.Mx
+			r.Qx = 1
+			r.Dx = l
+			r.PersonYears = l / r.Mx
+			break
+		}
+
+		n := float64(r.Width)
+		r.Ax = separationFactor(r.Start, r.Width, table[0].Mx, sex)
+		r.Qx = n * r.Mx / (1 + (n-r.Ax)*r.Mx)
+		if r.Qx > 1 {
+			r.Qx = 1
+		}
+		r.Dx = l * r.Qx
+		l -= r.Dx
+		r.PersonYears = n*l + r.Ax*r.Dx
+	}
+
+	t := 0.0
+	for i := last; i >= 0; i-- {
+		t += table[i].PersonYears
+		table[i].Tx = t
+		if table[i].Lx > 0 {
+			table[i].Ex = t / table[i].Lx
+		}
+	}
+	return table, nil
+}
+
+// separationFactor returns the average person-years lived in a closed group by those dying in it.
+// The first two groups use the Coale-Demeny West formulas in the infant death rate m0.
+func separationFactor(start, width int, m0 float64, sex string) float64 {
+	switch {
+	case start == 0 && width == 1:
+		male, female := 0.045+2.684*m0, 0.053+2.800*m0
+		if m0 >= 0.107 {
+			male, female = 0.330, 0.350
+		}
+		return bySex(male, female, sex)
+	case start == 1 && width == 4:
+		male, female := 1.651-2.816*m0, 1.522-1.518*m0
+		if m0 >= 0.107 {
+			male, female = 1.352, 1.361
+		}
+		return bySex(male, female, sex)
+	default:
+		return float64(width) / 2
+	}
+}
+
+func bySex(male, female float64, sex string) float64 {
+	switch sex {
+	case Male:
+		return male
+	case Female:
+		return female
+	default:
+		return (male + female) / 2
+	}
+}
+
+// Contribution is the part of a change in life expectancy at birth due to the change in mortality
+// of one age group. The direct effect comes from the years lived within the group, the indirect
+// effect from the additional survivors it passes on to older groups.
+type Contribution struct {
+	Start    int
+	Width    int
+	Direct   float64
+	Indirect float64
+}
+
+// Total returns the direct and indirect effects together
+func (c Contribution) Total() float64 {
+	return c.Direct + c.Indirect
+}
+
+// Arriaga decomposes the change in life expectancy at birth from table from to table to by age
+// group (Arriaga 1984). The contributions sum to the change. Groups no one survives to in one of
+// the tables, which a probability of dying of 1 leaves behind, contribute nothing on its side.
+func Arriaga(from, to Table) ([]Contribution, error) {
+	if len(from) != len(to) || len(from) == 0 {
+		return nil, ErrGroupsMismatch
+	}
+	for i := range from {
+		if from[i].Start != to[i].Start || from[i].Width != to[i].Width {
+			return nil, ErrGroupsMismatch
+		}
+	}
+
+	l0 := from[0].Lx
+	if l0 <= 0 {
+		return nil, fmt.Errorf("%w: no births", ErrNoPopulation)
+	}
+
+	last := len(from) - 1
+	contributions := make([]Contribution, len(from))
+	for i := range from {
+		f, t := from[i], to[i]
+		c := Contribution{Start: f.Start, Width: f.Width}
+		if i == last {
+			c.Direct = f.Lx / l0 * (perSurvivor(t.Tx, t.Lx) - perSurvivor(f.Tx, f.Lx))
+		} else {
+			c.Direct = f.Lx / l0 * (perSurvivor(t.PersonYears, t.Lx) - perSurvivor(f.PersonYears, f.Lx))
+			c.Indirect = to[i+1].Tx / l0 * (perSurvivor(f.Lx, t.Lx) - perSurvivor(from[i+1].Lx, to[i+1].Lx))
+		}
+		contributions[i] = c
+	}
+	return contributions, nil
+}
+
+// perSurvivor divides a quantity of a group by its survivors. A group without survivors has no
+// person-years either, and the ratio is taken as zero, which keeps the contributions summing to the
+// change.
+func perSurvivor(v, survivors float64) float64 {
+	if survivors <= 0 {
+		return 0
+	}
+	return v / survivors
+}
@@ -0,0 +1,140 @@
+package statbank
+
+import (
+	"strconv"
+	"strings"
+)
+
+// rowDimensions names the dimensions of the row headers Statbank uses, by lower-cased header text
+var rowDimensions = map[string]string{
+	"ani":                  DimYear,
+	"trimestre":            DimQuarter,
+	"luni":                 DimMonth,
+	"sexe":                 DimSex,
+	"medii":                DimMedium,
+	"virste":               DimAge,
+	"virsta":               DimAge,
+	"grupe de virsta":      DimAge,
+	"statia meteorologica": DimStation,
+}
+
+// months maps the Romanian month names to month numbers
+var months = map[string]int{
+	"ianuarie": 1, "februarie": 2, "martie": 3, "aprilie": 4, "mai": 5, "iunie": 6,
+	"iulie": 7, "august": 8, "septembrie": 9, "octombrie": 10, "noiembrie": 11, "decembrie": 12,
+}
+
+// quarters maps the Roman quarter numerals to quarter numbers
+var quarters = map[string]int{"I": 1, "II": 2, "III": 3, "IV": 4}
+
+// sexes and media map the header words of the sex and medium dimensions to their normalised values
+var (
+	sexes = map[string]string{"barbati": SexMale, "femei": SexFemale}
+	media = map[string]string{"urban": MediumUrban, "rural": MediumRural}
+)
+
+// rowDimension names the dimension of a row-dimension column
+func rowDimension(header string, columns map[string]string) string {
+	if dim, ok := columns[header]; ok {
+		return dim
+	}
+	if dim, ok := rowDimensions[strings.ToLower(header)]; ok {
+		return dim
+	}
+	return header
+}
+
+// decodeHeader decodes a value column header into its dimensions. Words that are a year, a Roman
+// quarter following a year, a month, a sex or a medium are taken as such and the remaining text,
+// if any, becomes the value of the label dimension. It reports false when no word decodes, which
+// marks a row-dimension column.
+func decodeHeader(header, label string) (map[string]string, bool) {
+	dims := make(map[string]string)
+	var rest []string
+
+	words := strings.Fields(header)
+	for i, w := range words {
+		lower := strings.ToLower(w)
+		switch {
+		case isYear(w):
+			dims[DimYear] = w
+		case quarters[w] > 0 && i > 0 && isYear(words[i-1]):
+			dims[DimQuarter] = strconv.Itoa(quarters[w])
+		case months[lower] > 0:
+			dims[DimMonth] = strconv.Itoa(months[lower])
+		case sexes[lower] != "":
+			dims[DimSex] = sexes[lower]
+		case media[lower] != "":
+			dims[DimMedium] = media[lower]
+		default:
+			rest = append(rest, w)
+		}
+	}
+
+	if len(dims) == 0 {
+		return nil, false
+	}
+	if len(rest) > 0 {
+		dims[label] = strings.Join(rest, " ")
+	}
+	return dims, true
+}
+
+// isYear reports whether a word is a four-digit year
+func isYear(w string) bool {
+	if len(w) != 4 {
+		return false
+	}
+	year, err := strconv.Atoi(w)
+	return err == nil && year >= 1900 && year < 2100
+}
+
+// normalizeValue normalises a row-dimension value: months, quarters, sexes and media take the
+// values of decoded headers, ages drop their unit and open age groups end in "+"; other values
+// only lose the leading dots PX-Web indents sub-items with
+func normalizeValue(dim, value string) string {
+	value = strings.TrimSpace(value)
+	lower := strings.ToLower(value)
+	if lower == "total" {
+		return ValueTotal
+	}
+
+	switch dim {
+	case DimMonth:
+		if m, ok := months[lower]; ok {
+			return strconv.Itoa(m)
+		}
+	case DimQuarter:
+		if q, ok := quarters[strings.TrimPrefix(value, "Trimestrul ")]; ok {
+			return strconv.Itoa(q)
+		}
+	case DimSex:
+		if s, ok := sexes[lower]; ok {
+			return s
+		}
+	case DimMedium:
+		if m, ok := media[lower]; ok {
+			return m
+		}
+	case DimAge:
+		return normalizeAge(lower)
+	}
+
+	return strings.TrimSpace(strings.TrimLeft(value, "."))
+}
+
+// normalizeAge turns Statbank ages such as "18-29 ani", "1 an", "85 si peste" or "100+" into
+// "18-29", "1", "85+" and "100+"; undeclared ages become ValueUnknown
+func normalizeAge(age string) string {
+	if age == "nedeclarata" || age == "nedeclarat" {
+		return ValueUnknown
+	}
+
+	open := strings.HasSuffix(age, " si peste")
+	age = strings.TrimSuffix(age, " si peste")
+	age = strings.TrimSuffix(strings.TrimSuffix(age, " ani"), " an")
+	if open {
+		age += "+"
+	}
+	return age
+}
@@ -0,0 +1,63 @@
+package statbank
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeHeader(t *testing.T) {
+	tests := []struct {
+		header string
+		label  string
+		want   map[string]string
+	}{
+		{"2014", DimIndicator, map[string]string{DimYear: "2014"}},
+		{"2015 III", DimIndicator, map[string]string{DimYear: "2015", DimQuarter: "3"}},
+		{"2014 Urban Barbati", DimIndicator, map[string]string{DimYear: "2014", DimMedium: MediumUrban, DimSex: SexMale}},
+		{"Femei 2023", DimIndicator, map[string]string{DimYear: "2023", DimSex: SexFemale}},
+		{"Chisinau 2014", DimStation, map[string]string{DimYear: "2014", DimStation: "Chisinau"}},
+		{"Numarul de paturi 2016", DimIndicator, map[string]string{DimYear: "2016", DimIndicator: "Numarul de paturi"}},
+		{"Decembrie 2020", DimIndicator, map[string]string{DimYear: "2020", DimMonth: "12"}},
+		// A Roman numeral only reads as a quarter right after a year
+		{"Grupa I 2019", DimIndicator, map[string]string{DimYear: "2019", DimIndicator: "Grupa I"}},
+	}
+	for _, tt := range tests {
+		got, ok := decodeHeader(tt.header, tt.label)
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("decodeHeader(%q) = %v, %v; want %v", tt.header, got, ok, tt.want)
+		}
+	}
+
+	// Row-dimension headers decode to nothing
+	for _, header := range []string{"Boli infectioase", "Virste", "Luni", "Persoane, mii", "12345"} {
+		if got, ok := decodeHeader(header, DimIndicator); ok {
+			t.Errorf("decodeHeader(%q) = %v, want a row dimension", header, got)
+		}
+	}
+}
+
+func TestNormalizeValue(t *testing.T) {
+	tests := []struct {
+		dim, value, want string
+	}{
+		{DimAge, "18-29 ani", "18-29"},
+		{DimAge, "1 an", "1"},
+		{DimAge, "85 si peste", "85+"},
+		{DimAge, "50 ani si peste", "50+"},
+		{DimAge, "100+", "100+"},
+		{DimAge, "Nedeclarata", ValueUnknown},
+		{DimAge, "Total", ValueTotal},
+		{DimMonth, "Februarie", "2"},
+		{DimQuarter, "Trimestrul IV", "4"},
+		{DimSex, "Femei", SexFemale},
+		{DimMedium, "Rural", MediumRural},
+		{"pollutant", "..oxid de carbon", "oxid de carbon"},
+		{"disease", " ...Hepatita virala B ", "Hepatita virala B"},
+		{"disease", "Gripa", "Gripa"},
+	}
+	for _, tt := range tests {
+		if got := normalizeValue(tt.dim, tt.value); got != tt.want {
+			t.Errorf("normalizeValue(%q, %q) = %q, want %q", tt.dim, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+// Package statbank reads the CSV exports of the Moldovan statistical databank (Statbank, PX-Web)
+// into tidy long-format tables. An export has an optional title line and a blank line, then a
+// header whose leading columns name the row dimensions and whose remaining columns each encode one
+// combination of the column dimensions, such as "2015 I" or "2014 Urban Barbati".
+package statbank
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dimension names produced by the parser
+const (
+	DimYear      = "year"
+	DimQuarter   = "quarter"
+	DimMonth     = "month"
+	DimSex       = "sex"
+	DimMedium    = "medium"
+	DimStation   = "station"
+	DimAge       = "age"
+	DimIndicator = "indicator"
+)
+
+// Normalised values of the sex and medium dimensions
+const (
+	SexMale      = "male"
+	SexFemale    = "female"
+	MediumUrban  = "urban"
+	MediumRural  = "rural"
+	ValueTotal   = "total"
+	ValueUnknown = "unknown"
+)
+
+// Statuses of a value cell; Statbank writes "-" for a nil value, "..", "...", ":" or "x" for one
+// that is not available and "C" for a confidential one
+const (
+	StatusObserved     = "observed"
+	StatusZero         = "zero"
+	StatusNotAvailable = "not_available"
+	StatusConfidential = "confidential"
+)
+
+// byteOrderMark is the UTF-8 byte order mark some exports start with
+const byteOrderMark = "\ufeff"
+
+// ErrNoHeader is returned for input without a header row
+var ErrNoHeader = errors.New("no header row found")
+
+// Mapping declares how an export's columns map to dimensions. The zero value suits most exports.
+type Mapping struct {
+	// Columns names the dimension of row-dimension columns by header text. Common Statbank headers
+	// such as "Ani", "Luni", "Medii" or "Virste" are recognised without it; other headers become
+	// dimensions named as written.
+	Columns map[string]string
+	// Label names the dimension taking the text left in a value column header once the year,
+	// quarter, month, sex and medium are decoded, e.g. DimStation for "Chisinau 2014". Defaults to
+	// DimIndicator.
+	Label string
+	// RowColumns is the number of leading row-dimension columns; detected from the header when 0
+	RowColumns int
+}
+
+// Observation is one cell of an export with the dimensions locating it
+type Observation struct {
+	Dimensions map[string]string
+	Value      float64 // 0 unless the status is StatusObserved
+	Raw        string  // the cell as written
+	Status     string
+}
+
+// Missing reports whether the cell withholds its value, being not available or confidential
+func (o Observation) Missing() bool {
+	return o.Status == StatusNotAvailable || o.Status == StatusConfidential
+}
+
+// Get returns the value of a dimension, empty when the observation does not have it
+func (o Observation) Get(dim string) string {
+	return o.Dimensions[dim]
+}
+
+// Int returns the value of a numeric dimension such as the year, quarter or month
+func (o Observation) Int(dim string) (int, bool) {
+	v, err := strconv.Atoi(o.Dimensions[dim])
+	return v, err == nil
+}
+
+// Table is an export in long format, one observation per value cell
+type Table struct {
+	Title        string
+	Dimensions   []string // row dimensions first, then the decoded column dimensions
+	Observations []Observation
+}
+
+// ReadFile reads the export at path
+func ReadFile(path string, m Mapping) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t, err := Read(f, m)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Read parses an export. Cells that are neither numbers nor missing-value markers are an error,
+// since they mean the mapping took a value column for a row dimension or the other way round.
+func Read(r io.Reader, m Mapping) (*Table, error) {
+	// Drop a byte order mark before the CSV reader sees it, as it would take a quoted title
+	// following one for a bare quote
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(len(byteOrderMark)); err == nil && string(bom) == byteOrderMark {
+		_, _ = buffered.Discard(len(byteOrderMark))
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1 // The title line has a single field
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV: %w", err)
+	}
+
+	t := &Table{}
+	headerRow := -1
+	for i, record := range records {
+		if len(record) > 1 {
+			headerRow = i
+			break
+		}
+		if t.Title == "" {
+			t.Title = strings.TrimSpace(record[0])
+		}
+	}
+	if headerRow < 0 {
+		return nil, ErrNoHeader
+	}
+
+	label := m.Label
+	if label == "" {
+		label = DimIndicator
+	}
+
+	header := records[headerRow]
+	columns := make([]map[string]string, len(header))
+	for i, h := range header {
+		if dims, ok := decodeHeader(h, label); ok {
+			columns[i] = dims
+		}
+	}
+
+	rowColumns := m.RowColumns
+	if rowColumns <= 0 {
+		for rowColumns < len(header) && columns[rowColumns] == nil {
+			rowColumns++
+		}
+		// Without any decodable header, e.g. "Ani","Persoane, mii", the last column holds the values
+		if rowColumns == len(header) {
+			rowColumns--
+		}
+	}
+	if rowColumns < 1 || rowColumns >= len(header) {
+		return nil, fmt.Errorf("header %q has no value columns", header)
+	}
+
+	rowDims := make([]string, rowColumns)
+	for i := range rowDims {
+		rowDims[i] = rowDimension(strings.TrimSpace(header[i]), m.Columns)
+	}
+	for i := rowColumns; i < len(header); i++ {
+		if columns[i] == nil {
+			// An undecodable value column, e.g. "Persoane, mii", is a value of the label dimension
+			columns[i] = map[string]string{label: strings.TrimSpace(header[i])}
+		}
+	}
+	t.Dimensions = tableDimensions(rowDims, columns[rowColumns:])
+
+	for n, record := range records[headerRow+1:] {
+		line := headerRow + n + 2
+		if len(record) <= rowColumns {
+			// Footnotes and source notes trail the data as single-field lines
+			continue
+		}
+
+		rowValues := make(map[string]string, rowColumns)
+		for i, dim := range rowDims {
+			rowValues[dim] = normalizeValue(dim, record[i])
+		}
+
+		for i := rowColumns; i < len(record) && i < len(header); i++ {
+			o := Observation{Dimensions: make(map[string]string, len(rowValues)+len(columns[i]))}
+			for dim, v := range rowValues {
+				o.Dimensions[dim] = v
+			}
+			for dim, v := range columns[i] {
+				o.Dimensions[dim] = v
+			}
+
+			o.Raw = strings.TrimSpace(record[i])
+			if o.Value, o.Status, err = parseCell(o.Raw); err != nil {
+				return nil, fmt.Errorf("line %d, column %q: %w", line, header[i], err)
+			}
+			t.Observations = append(t.Observations, o)
+		}
+	}
+
+	return t, nil
+}
+
+// Write writes the table as CSV with one column per dimension followed by the value; cells that
+// withhold their value keep their marker
+func (t *Table) Write(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append(append([]string{}, t.Dimensions...), "value")); err != nil {
+		return err
+	}
+
+	row := make([]string, len(t.Dimensions)+1)
+	for _, o := range t.Observations {
+		for i, dim := range t.Dimensions {
+			row[i] = o.Dimensions[dim]
+		}
+		row[len(t.Dimensions)] = o.Raw
+		if !o.Missing() {
+			row[len(t.Dimensions)] = strconv.FormatFloat(o.Value, 'f', -1, 64)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// tableDimensions lists the row dimensions followed by the column dimensions in a stable order
+func tableDimensions(rowDims []string, columns []map[string]string) []string {
+	seen := make(map[string]bool)
+	dims := make([]string, 0, len(rowDims))
+	for _, dim := range rowDims {
+		if !seen[dim] {
+			seen[dim] = true
+			dims = append(dims, dim)
+		}
+	}
+
+	var columnDims []string
+	for _, c := range columns {
+		for dim := range c {
+			if !seen[dim] {
+				seen[dim] = true
+				columnDims = append(columnDims, dim)
+			}
+		}
+	}
+	sort.Slice(columnDims, func(i, j int) bool {
+		ri, rj := dimensionRank(columnDims[i]), dimensionRank(columnDims[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return columnDims[i] < columnDims[j]
+	})
+
+	return append(dims, columnDims...)
+}
+
+// dimensionRank orders the column dimensions from the label down to the finest period
+func dimensionRank(dim string) int {
+	switch dim {
+	case DimMedium:
+		return 1
+	case DimSex:
+		return 2
+	case DimYear:
+		return 3
+	case DimQuarter:
+		return 4
+	case DimMonth:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// parseCell parses a value cell into its value and status
+func parseCell(cell string) (float64, string, error) {
+	switch cell {
+	case "-":
+		return 0, StatusZero, nil
+	case "", "..", "...", ":", "x":
+		return 0, StatusNotAvailable, nil
+	case "C":
+		return 0, StatusConfidential, nil
+	}
+
+	v, err := strconv.ParseFloat(strings.Replace(cell, ",", ".", 1), 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid value %q", cell)
+	}
+	return v, StatusObserved, nil
+}
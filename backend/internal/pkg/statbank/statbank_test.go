@@ -0,0 +1,169 @@
+package statbank
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseCell(t *testing.T) {
+	tests := []struct {
+		cell   string
+		value  float64
+		status string
+	}{
+		{"137.4", 137.4, StatusObserved},
+		{"-1,9", -1.9, StatusObserved},
+		{"0", 0, StatusObserved},
+		{"-", 0, StatusZero},
+		{"..", 0, StatusNotAvailable},
+		{"...", 0, StatusNotAvailable},
+		{":", 0, StatusNotAvailable},
+		{"x", 0, StatusNotAvailable},
+		{"", 0, StatusNotAvailable},
+		{"C", 0, StatusConfidential},
+	}
+	for _, tt := range tests {
+		value, status, err := parseCell(tt.cell)
+		if err != nil || value != tt.value || status != tt.status {
+			t.Errorf("parseCell(%q) = %v, %q, %v; want %v, %q", tt.cell, value, status, err, tt.value, tt.status)
+		}
+	}
+
+	if _, _, err := parseCell("Urban"); err == nil {
+		t.Error("parseCell(\"Urban\") accepted a dimension value")
+	}
+}
+
+// observations indexes the values and statuses of a table by its dimensions, joined in order
+func observations(t *testing.T, table *Table) map[string]string {
+	t.Helper()
+	got := make(map[string]string)
+	for _, o := range table.Observations {
+		key := make([]string, len(table.Dimensions))
+		for i, dim := range table.Dimensions {
+			key[i] = o.Get(dim)
+		}
+		got[strings.Join(key, "|")] = o.Raw + " " + o.Status
+	}
+	return got
+}
+
+func TestReadMultiDimensionHeader(t *testing.T) {
+	const export = "\ufeff\"Speranta de viata dupa virste, medii si sexe, 1958-2023\"\n" +
+		"\n" +
+		"\"Virste\",\"2014 Urban Barbati\",\"2014 Urban Femei\",\"2014 Rural Barbati\",\"2015 Urban Barbati\"\n" +
+		"\"0\",66.8,75.3,64.4,66.6\n" +
+		"\"85 si peste\",4.1,:,x,-\n" +
+		"\"Sursa: Biroul National de Statistica\"\n"
+
+	table, err := Read(strings.NewReader(export), Mapping{})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if table.Title != "Speranta de viata dupa virste, medii si sexe, 1958-2023" {
+		t.Errorf("title = %q", table.Title)
+	}
+	wantDims := []string{DimAge, DimMedium, DimSex, DimYear}
+	if strings.Join(table.Dimensions, ",") != strings.Join(wantDims, ",") {
+		t.Fatalf("dimensions = %v, want %v", table.Dimensions, wantDims)
+	}
+
+	got := observations(t, table)
+	want := map[string]string{
+		"0|urban|male|2014":   "66.8 observed",
+		"0|urban|female|2014": "75.3 observed",
+		"0|rural|male|2014":   "64.4 observed",
+		"0|urban|male|2015":   "66.6 observed",
+		"85+|urban|male|2014": "4.1 observed",
+		// ":" and "x" are not available, "-" is a true zero
+		"85+|urban|female|2014": ": not_available",
+		"85+|rural|male|2014":   "x not_available",
+		"85+|urban|male|2015":   "- zero",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d observations, want %d: %v", len(got), len(want), got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestReadIndentedRowsAndQuarters(t *testing.T) {
+	const export = "\"Emisiile substantelor poluante, 2001-2023\"\n" +
+		"\n" +
+		"\"Substante\",\"2022 III\",\"2022 IV\"\n" +
+		"\"..oxid de carbon\",105.6,C\n" +
+		"\"..dioxid de azot\",..,\"26,4\"\n"
+
+	table, err := Read(strings.NewReader(export), Mapping{Columns: map[string]string{"Substante": "pollutant"}})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	got := observations(t, table)
+	want := map[string]string{
+		"oxid de carbon|2022|3": "105.6 observed",
+		"oxid de carbon|2022|4": "C confidential",
+		"dioxid de azot|2022|3": ".. not_available",
+		"dioxid de azot|2022|4": "26,4 observed",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q (all: %v)", k, got[k], v, got)
+		}
+	}
+	for _, o := range table.Observations {
+		if o.Get("pollutant") == "dioxid de azot" && o.Get(DimQuarter) == "4" && o.Value != 26.4 {
+			t.Errorf("decimal comma read as %v", o.Value)
+		}
+	}
+}
+
+func TestReadLabelledColumns(t *testing.T) {
+	const export = "\"Temperatura medie lunara a aerului, 2002-2023\"\n" +
+		"\n" +
+		"\"Luni\",\"Chisinau 2014\",\"Balti 2014\"\n" +
+		"\"Ianuarie\",-1.9,-3.1\n"
+
+	table, err := Read(strings.NewReader(export), Mapping{Label: DimStation})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	got := observations(t, table)
+	for k, v := range map[string]string{"1|Balti|2014": "-3.1 observed", "1|Chisinau|2014": "-1.9 observed"} {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q (all: %v)", k, got[k], v, got)
+		}
+	}
+}
+
+func TestReadRejectsMisreadColumns(t *testing.T) {
+	// With two row dimensions declared as one, the medium lands in a value column
+	const export = "\"Medii\",\"Virste\",\"2014 Barbati\"\n\"Urban\",\"Total\",6888\n"
+	if _, err := Read(strings.NewReader(export), Mapping{RowColumns: 1}); err == nil {
+		t.Error("Read accepted a dimension value as a cell")
+	}
+	if _, err := Read(strings.NewReader("\"Just a title\"\n"), Mapping{}); err != ErrNoHeader {
+		t.Errorf("Read without a header: error = %v, want ErrNoHeader", err)
+	}
+}
+
+func TestWriteKeepsMarkers(t *testing.T) {
+	const export = "\"Ani\",\"Urban Barbati\",\"Urban Femei\"\n\"2014\",531732,C\n"
+	table, err := Read(strings.NewReader(export), Mapping{})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := table.Write(&out); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	want := "year,medium,sex,value\n2014,urban,male,531732\n2014,urban,female,C\n"
+	if out.String() != want {
+		t.Errorf("Write =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,65 @@
+package stats
+
+import "math"
+
+// AR is the autoregressive model x_t - μ = Σ φ_i (x_{t-i} - μ) + e_t
+type AR struct {
+	Mean   float64
+	Coeffs []float64 // φ_1..φ_p
+}
+
+// FitAR fits autoregressions of order 0..maxOrder to xs by Yule-Walker (Levinson-Durbin) and
+// returns the one with the lowest AIC
+func FitAR(xs []float64, maxOrder int) AR {
+	n := len(xs)
+	mean := Mean(xs)
+	if maxOrder > n-1 {
+		maxOrder = n - 1
+	}
+	if n < 2 || maxOrder < 1 {
+		return AR{Mean: mean}
+	}
+
+	acov := make([]float64, maxOrder+1)
+	for k := range acov {
+		for t := k; t < n; t++ {
+			acov[k] += (xs[t] - mean) * (xs[t-k] - mean)
+		}
+		acov[k] /= float64(n)
+	}
+	if acov[0] == 0 {
+		return AR{Mean: mean}
+	}
+
+	best := AR{Mean: mean}
+	variance := acov[0]
+	bestAIC := float64(n) * math.Log(variance)
+
+	phi := []float64{}
+	for p := 1; p <= maxOrder; p++ {
+		// Levinson-Durbin step from order p-1 to p
+		num := acov[p]
+		for i, f := range phi {
+			num -= f * acov[p-1-i]
+		}
+		reflection := num / variance
+
+		next := make([]float64, p)
+		for i := range phi {
+			next[i] = phi[i] - reflection*phi[p-2-i]
+		}
+		next[p-1] = reflection
+		phi = next
+
+		variance *= 1 - reflection*reflection
+		if variance <= 0 {
+			break
+		}
+		if aic := float64(n)*math.Log(variance) + 2*float64(p); aic < bestAIC {
+			bestAIC = aic
+			best = AR{Mean: mean, Coeffs: append([]float64(nil), phi...)}
+		}
+	}
+
+	return best
+}
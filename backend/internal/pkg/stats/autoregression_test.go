@@ -0,0 +1,24 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitARRecoversCoefficient(t *testing.T) {
+	// A deterministic AR(1) series with φ = 0.6 driven by a linear congruential generator
+	seed := uint32(12345)
+	noise := func() float64 {
+		seed = seed*1664525 + 1013904223
+		return float64(seed)/math.MaxUint32 - 0.5
+	}
+	xs := make([]float64, 5000)
+	for i := 1; i < len(xs); i++ {
+		xs[i] = 0.6*xs[i-1] + noise()
+	}
+
+	ar := FitAR(xs, 4)
+	if len(ar.Coeffs) == 0 || !near(ar.Coeffs[0], 0.6, 0.05) {
+		t.Errorf("FitAR coefficients = %v, want φ1 near 0.6", ar.Coeffs)
+	}
+}
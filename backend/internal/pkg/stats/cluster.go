@@ -0,0 +1,50 @@
+package stats
+
+// ClusterOrder clusters items by average-linkage agglomeration of a symmetric distance matrix and
+// returns the leaf order of the resulting dendrogram, which places similar items next to each other
+func ClusterOrder(dist [][]float64) []int {
+	type cluster struct {
+		members []int
+		order   []int
+	}
+
+	clusters := make([]*cluster, len(dist))
+	for i := range clusters {
+		clusters[i] = &cluster{members: []int{i}, order: []int{i}}
+	}
+
+	linkage := func(a, b *cluster) float64 {
+		var sum float64
+		for _, i := range a.members {
+			for _, j := range b.members {
+				sum += dist[i][j]
+			}
+		}
+		return sum / float64(len(a.members)*len(b.members))
+	}
+
+	for len(clusters) > 1 {
+		bestI, bestJ := 0, 1
+		best := linkage(clusters[0], clusters[1])
+		for i := range clusters {
+			for j := i + 1; j < len(clusters); j++ {
+				if d := linkage(clusters[i], clusters[j]); d < best {
+					best, bestI, bestJ = d, i, j
+				}
+			}
+		}
+
+		a, b := clusters[bestI], clusters[bestJ]
+		merged := &cluster{
+			members: append(append([]int(nil), a.members...), b.members...),
+			order:   append(append([]int(nil), a.order...), b.order...),
+		}
+		clusters[bestI] = merged
+		clusters = append(clusters[:bestJ], clusters[bestJ+1:]...)
+	}
+
+	if len(clusters) == 0 {
+		return nil
+	}
+	return clusters[0].order
+}
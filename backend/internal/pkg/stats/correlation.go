@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Correlation is a correlation coefficient with its two-sided significance and confidence interval
+type Correlation struct {
+	R     float64
+	P     float64 // two-sided p-value of H0: no correlation
+	Lower float64
+	Upper float64
+	N     int
+}
+
+// Pearson returns the Pearson product-moment correlation of x and y with a t-test p-value and a
+// Fisher-z confidence interval at the given level. It needs at least four pairs; fewer, or a
+// constant series, give NaN.
+func Pearson(x, y []float64, level float64) Correlation {
+	return newCorrelation(pearsonR(x, y), len(x), level, 1)
+}
+
+// Spearman returns the Spearman rank correlation of x and y (ties get their average rank) with a
+// t-approximation p-value and a Fisher-z confidence interval using the Fieller, Hartley and
+// Pearson variance 1.06/(n-3)
+func Spearman(x, y []float64, level float64) Correlation {
+	return newCorrelation(pearsonR(Ranks(x), Ranks(y)), len(x), level, 1.06)
+}
+
+// Ranks returns the 1-based ranks of xs, giving tied values their average rank
+func Ranks(xs []float64) []float64 {
+	order := make([]int, len(xs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return xs[order[a]] < xs[order[b]] })
+
+	ranks := make([]float64, len(xs))
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && xs[order[j+1]] == xs[order[i]] {
+			j++
+		}
+		avg := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			ranks[order[k]] = avg
+		}
+		i = j + 1
+	}
+	return ranks
+}
+
+// newCorrelation completes a coefficient over n pairs with its p-value and the Fisher-z interval
+// tanh(atanh(r) ± z·sqrt(variance/(n-3)))
+func newCorrelation(r float64, n int, level, variance float64) Correlation {
+	c := Correlation{R: r, P: correlationP(r, n), Lower: math.NaN(), Upper: math.NaN(), N: n}
+	switch {
+	case math.IsNaN(r) || n < 4:
+	case math.Abs(r) == 1:
+		c.Lower, c.Upper = r, r
+	default:
+		half := NormalQuantile(0.5+level/2) * math.Sqrt(variance/float64(n-3))
+		c.Lower = math.Tanh(math.Atanh(r) - half)
+		c.Upper = math.Tanh(math.Atanh(r) + half)
+	}
+	return c
+}
+
+func pearsonR(x, y []float64) float64 {
+	if len(x) != len(y) || len(x) < 2 {
+		return math.NaN()
+	}
+	mx, my := Mean(x), Mean(y)
+
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+
+	r := sxy / math.Sqrt(sxx*syy)
+	// Rounding can push a perfect correlation just past ±1
+	return math.Max(-1, math.Min(1, r))
+}
+
+// correlationP is the two-sided p-value of t = r·sqrt((n-2)/(1-r²)) on n-2 degrees of freedom
+func correlationP(r float64, n int) float64 {
+	if math.IsNaN(r) || n < 3 {
+		return math.NaN()
+	}
+	if math.Abs(r) == 1 {
+		return 0
+	}
+	df := float64(n - 2)
+	t := r * math.Sqrt(df/(1-r*r))
+	return 2 * StudentTCDF(-math.Abs(t), df)
+}
@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStudentTCDF(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"StudentTCDF(0, 7)", StudentTCDF(0, 7), 0.5},
+		{"StudentTCDF(1, 1), Cauchy", StudentTCDF(1, 1), 0.75},
+		{"StudentTCDF(2.228139, 10)", StudentTCDF(2.228138851986, 10), 0.975},
+		{"StudentTCDF(-2.228139, 10)", StudentTCDF(-2.228138851986, 10), 0.025},
+	}
+	for _, tt := range tests {
+		if !near(tt.got, tt.want, 1e-8) {
+			t.Errorf("%s = %.12g, want %.12g", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestPearson(t *testing.T) {
+	c := Pearson([]float64{1, 2, 3, 4, 5}, []float64{2, 4, 5, 4, 5}, 0.95)
+	if !near(c.R, 0.7745966692414834, 1e-12) || !near(c.P, 0.12402706265755459, 1e-8) || c.N != 5 {
+		t.Errorf("Pearson = %+v, want r 0.7746 and p 0.1240 over 5 pairs", c)
+	}
+	if c.Lower >= c.R || c.Upper <= c.R {
+		t.Errorf("interval [%g, %g] does not contain r %g", c.Lower, c.Upper, c.R)
+	}
+
+	perfect := Pearson([]float64{1, 2, 3, 4}, []float64{-2, -4, -6, -8}, 0.95)
+	if perfect.R != -1 || perfect.P != 0 || perfect.Lower != -1 || perfect.Upper != -1 {
+		t.Errorf("perfect negative correlation = %+v", perfect)
+	}
+
+	constant := Pearson([]float64{1, 2, 3, 4}, []float64{5, 5, 5, 5}, 0.95)
+	if !math.IsNaN(constant.R) || !math.IsNaN(constant.P) || !math.IsNaN(constant.Lower) {
+		t.Errorf("constant series = %+v, want NaN", constant)
+	}
+}
+
+func TestFisherInterval(t *testing.T) {
+	c := newCorrelation(0.5, 28, 0.95, 1)
+	if !near(c.Lower, 0.15602836252908595, 1e-9) || !near(c.Upper, 0.7358184794439376, 1e-9) {
+		t.Errorf("interval of r 0.5 over 28 pairs = [%g, %g], want [0.1560, 0.7358]", c.Lower, c.Upper)
+	}
+
+	// The Spearman variance widens the interval
+	s := newCorrelation(0.5, 28, 0.95, 1.06)
+	if s.Lower >= c.Lower || s.Upper <= c.Upper {
+		t.Errorf("Spearman interval [%g, %g] is not wider than [%g, %g]", s.Lower, s.Upper, c.Lower, c.Upper)
+	}
+}
+
+func TestSpearman(t *testing.T) {
+	// Ties in y get the average of their ranks
+	c := Spearman([]float64{1, 2, 3, 4, 5}, []float64{2, 4, 5, 4, 5}, 0.95)
+	if !near(c.R, 0.7378647873726218, 1e-12) {
+		t.Errorf("Spearman r = %g, want 0.7379", c.R)
+	}
+
+	// Any monotone relation is a perfect rank correlation
+	x := []float64{1, 2, 3, 4, 5, 6}
+	y := make([]float64, len(x))
+	for i, v := range x {
+		y[i] = v * v * v
+	}
+	if r := Spearman(x, y, 0.95).R; r != 1 {
+		t.Errorf("Spearman r of a cubic = %g, want 1", r)
+	}
+	if r := Pearson(x, y, 0.95).R; r >= 1 {
+		t.Errorf("Pearson r of a cubic = %g, want below 1", r)
+	}
+}
+
+func TestRanks(t *testing.T) {
+	got := Ranks([]float64{30, 10, 20, 20, 40, 20})
+	want := []float64{5, 1, 3, 3, 6, 3}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Ranks = %v, want %v", got, want)
+		}
+	}
+}
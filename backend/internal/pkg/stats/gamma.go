@@ -0,0 +1,98 @@
+package stats
+
+import "math"
+
+// RegularizedGammaP returns the regularized lower incomplete gamma function P(a, x), evaluated
+// with the series below a+1 and the continued fraction of Numerical Recipes above it
+func RegularizedGammaP(a, x float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case math.IsInf(x, 1):
+		return 1
+	}
+
+	lga, _ := math.Lgamma(a)
+	front := math.Exp(a*math.Log(x) - x - lga)
+	if x < a+1 {
+		return front * gammaSeries(a, x)
+	}
+	return 1 - front*gammaFraction(a, x)
+}
+
+func gammaSeries(a, x float64) float64 {
+	const (
+		maxIterations = 1000
+		epsilon       = 1e-14
+	)
+
+	term := 1 / a
+	sum := term
+	for n := 1; n <= maxIterations; n++ {
+		term *= x / (a + float64(n))
+		sum += term
+		if math.Abs(term) < math.Abs(sum)*epsilon {
+			break
+		}
+	}
+	return sum
+}
+
+func gammaFraction(a, x float64) float64 {
+	const (
+		maxIterations = 1000
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i <= maxIterations; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
+
+// ChiSquareQuantile returns the p-quantile of the chi-square distribution with df degrees of
+// freedom, which need not be an integer, found by bisection on its distribution function
+func ChiSquareQuantile(p, df float64) float64 {
+	switch {
+	case p <= 0 || df <= 0:
+		return 0
+	case p >= 1:
+		return math.Inf(1)
+	}
+
+	cdf := func(x float64) float64 { return RegularizedGammaP(df/2, x/2) }
+
+	lo, hi := 0.0, math.Max(df, 1)
+	for cdf(hi) < p {
+		lo, hi = hi, hi*2
+	}
+	for i := 0; i < 200 && hi-lo > 1e-12*hi; i++ {
+		mid := (lo + hi) / 2
+		if cdf(mid) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
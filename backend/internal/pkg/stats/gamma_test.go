@@ -0,0 +1,25 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRegularizedGammaAndChiSquare(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"RegularizedGammaP(1, 2), exponential", RegularizedGammaP(1, 2), 1 - math.Exp(-2)},
+		{"RegularizedGammaP(0.5, 2), erf", RegularizedGammaP(0.5, 2), 0.9544997361036416},
+		{"RegularizedGammaP(3, 0)", RegularizedGammaP(3, 0), 0},
+		{"ChiSquareQuantile(0.95, 1)", ChiSquareQuantile(0.95, 1), 3.841458820694124},
+		{"ChiSquareQuantile(0.95, 10)", ChiSquareQuantile(0.95, 10), 18.307038053275146},
+		{"ChiSquareQuantile(0.025, 4)", ChiSquareQuantile(0.025, 4), 0.4844185570879305},
+	}
+	for _, tt := range tests {
+		if !near(tt.got, tt.want, 1e-8) {
+			t.Errorf("%s = %.12g, want %.12g", tt.name, tt.got, tt.want)
+		}
+	}
+}
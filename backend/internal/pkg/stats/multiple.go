@@ -0,0 +1,29 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// BenjaminiHochberg returns the Benjamini-Hochberg adjusted p-values (false discovery rate q-values)
+// of ps in their original order. NaN p-values are left out of the family and stay NaN.
+func BenjaminiHochberg(ps []float64) []float64 {
+	adjusted := make([]float64, len(ps))
+	var order []int
+	for i, p := range ps {
+		adjusted[i] = math.NaN()
+		if !math.IsNaN(p) {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return ps[order[a]] < ps[order[b]] })
+
+	m := float64(len(order))
+	running := 1.0
+	for rank := len(order); rank >= 1; rank-- {
+		i := order[rank-1]
+		running = math.Min(running, ps[i]*m/float64(rank))
+		adjusted[i] = running
+	}
+	return adjusted
+}
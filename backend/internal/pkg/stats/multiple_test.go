@@ -0,0 +1,16 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBenjaminiHochberg(t *testing.T) {
+	got := BenjaminiHochberg([]float64{0.01, 0.04, math.NaN(), 0.03, 0.005})
+	want := []float64{0.02, 0.04, math.NaN(), 0.04, 0.02}
+	for i := range want {
+		if !near(got[i], want[i], 1e-15) {
+			t.Fatalf("BenjaminiHochberg = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func near(got, want, tolerance float64) bool {
+	if math.IsNaN(want) {
+		return math.IsNaN(got)
+	}
+	return math.Abs(got-want) <= tolerance
+}
+
+func TestNormalDistribution(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"NormalCDF(1.96)", NormalCDF(1.96), 0.9750021048517795},
+		{"NormalQuantile(0.5)", NormalQuantile(0.5), 0},
+		{"NormalQuantile(0.975)", NormalQuantile(0.975), 1.959963984540054},
+		{"NormalQuantile(0.001)", NormalQuantile(0.001), -3.090232306167813},
+	}
+	for _, tt := range tests {
+		if !near(tt.got, tt.want, 1e-8) {
+			t.Errorf("%s = %.12g, want %.12g", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummaries(t *testing.T) {
+	if q := Quantile([]float64{4, 1, 3, 2}, 0.25); q != 1.75 {
+		t.Errorf("Quantile(0.25) = %g, want 1.75", q)
+	}
+	if m := Mean(nil); !math.IsNaN(m) {
+		t.Errorf("Mean(nil) = %g, want NaN", m)
+	}
+}
+
+func TestSlope(t *testing.T) {
+	if s := Slope([]float64{2015, 2016, 2017, 2018}, []float64{90, 92.5, 95, 97.5}); !near(s, 2.5, 1e-12) {
+		t.Errorf("Slope = %g, want 2.5", s)
+	}
+	if s := Slope([]float64{1, 1}, []float64{2, 3}); !math.IsNaN(s) {
+		t.Errorf("Slope without distinct x = %g, want NaN", s)
+	}
+}
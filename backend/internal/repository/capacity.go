@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// CapacityPredicate is a models.CapacityFilter compiled into the row conditions every backend
+// applies to bed counts
+type CapacityPredicate struct {
+	startYear *uint16
+	endYear   *uint16
+	profiles  []string
+}
+
+// CompileCapacityFilter validates a capacity filter and compiles it into a predicate. Validation
+// errors wrap models.ErrInvalidFilter.
+func CompileCapacityFilter(filter models.CapacityFilter) (*CapacityPredicate, error) {
+	// The years are validated exactly like those of a disease filter
+	if _, err := CompileFilter(models.DiseaseFilter{StartYear: filter.StartYear, EndYear: filter.EndYear}); err != nil {
+		return nil, err
+	}
+
+	p := CapacityPredicate{profiles: nonEmpty(filter.Profiles)}
+	if filter.StartYear != nil {
+		y := uint16(*filter.StartYear)
+		p.startYear = &y
+	}
+	if filter.EndYear != nil {
+		y := uint16(*filter.EndYear)
+		p.endYear = &y
+	}
+	return &p, nil
+}
+
+// SQL renders the predicate as a WHERE condition over the hospital_beds table with its arguments
+func (p *CapacityPredicate) SQL() (string, []interface{}) {
+	conds := []string{"1=1"}
+	var args []interface{}
+
+	if p.startYear != nil {
+		conds = append(conds, "year >= ?")
+		args = append(args, *p.startYear)
+	}
+	if p.endYear != nil {
+		conds = append(conds, "year <= ?")
+		args = append(args, *p.endYear)
+	}
+	if len(p.profiles) > 0 {
+		conds = append(conds, "profile IN ("+placeholders(len(p.profiles))+")")
+		for _, profile := range p.profiles {
+			args = append(args, profile)
+		}
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// Match reports whether a bed count satisfies the predicate
+func (p *CapacityPredicate) Match(b *models.HospitalBeds) bool {
+	if p.startYear != nil && b.Year < *p.startYear {
+		return false
+	}
+	if p.endYear != nil && b.Year > *p.endYear {
+		return false
+	}
+	return len(p.profiles) == 0 || contains(p.profiles, b.Profile)
+}
+
+// DefaultBedProfiles links the profiles of the Statbank bed counts to the categories of the
+// reported infectious diseases. Infectious-disease beds treat every category but the sexually
+// transmitted infections, which fall to dermatovenerology; phthisiopneumology treats the
+// respiratory infections, tuberculosis among them. The other profiles treat none.
+var DefaultBedProfiles = []models.BedProfile{
+	{Profile: "Dermatovenerologie", Name: "Dermatovenereology", Categories: []string{"STIs"}},
+	{Profile: "Ftiziopneumologie", Name: "Phthisiopneumology", Categories: []string{"Respiratory Infections"}},
+	{Profile: "Ginecologie", Name: "Gynaecology", Categories: []string{}},
+	{Profile: "Infectioase", Name: "Infectious diseases", Categories: []string{
+		"COVID-19", "Intestinal Infections", "Other Infectious Diseases", "Respiratory Infections", "Viral Hepatitis",
+	}},
+	{Profile: "Narcologie", Name: "Addiction medicine", Categories: []string{}},
+	{Profile: "Neurologie", Name: "Neurology", Categories: []string{}},
+	{Profile: "Oftalmologie", Name: "Ophthalmology", Categories: []string{}},
+	{Profile: "Oncologie", Name: "Oncology", Categories: []string{}},
+	{Profile: "Otorinolaringologie", Name: "Otorhinolaryngology", Categories: []string{}},
+	{Profile: "Pentru copii bolnavi (neinfectiosi)", Name: "Paediatric (non-infectious)", Categories: []string{}},
+	{Profile: "Pentru femei gravide si lauze (in maternitati si sectiile din spitale)", Name: "Maternity", Categories: []string{}},
+	{Profile: "Profil chirurgical", Name: "Surgical", Categories: []string{}},
+	{Profile: "Profil terapeutic", Name: "Internal medicine", Categories: []string{}},
+	{Profile: "Psihiatrie", Name: "Psychiatry", Categories: []string{}},
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+func TestCapacityPredicateSQLAgreesWithMatch(t *testing.T) {
+	beds := []models.HospitalBeds{
+		{Profile: "Infectioase", Year: 2019},
+		{Profile: "Ftiziopneumologie", Year: 2021},
+		{Profile: "Ginecologie", Year: 2023},
+	}
+	filters := []models.CapacityFilter{
+		{},
+		{Profiles: []string{"Infectioase", "Ginecologie"}},
+		{StartYear: intPtr(2020), EndYear: intPtr(2022)},
+	}
+
+	for _, filter := range filters {
+		p, err := CompileCapacityFilter(filter)
+		if err != nil {
+			t.Fatalf("CompileCapacityFilter(%+v): %v", filter, err)
+		}
+		where, args := p.SQL()
+		for i := range beds {
+			b := &beds[i]
+			row := map[string]any{"profile": b.Profile, "year": b.Year}
+			if sql, match := evalWhere(t, where, args, row), p.Match(b); sql != match {
+				t.Errorf("%+v on %+v: SQL %q selects it: %v, Match: %v", filter, *b, where, sql, match)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// AlertRepository stores disease alerts in the ClickHouse alerts table
+type AlertRepository struct {
+	db *database.DB
+}
+
+// NewAlertRepository creates a new AlertRepository
+func NewAlertRepository(db *database.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+// List retrieves all alerts, newest first
+func (r *AlertRepository) List(ctx context.Context) ([]models.Alert, error) {
+	query := `
+		SELECT id, disease_id, severity, message, created_at, expires_at
+		FROM alerts
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []models.Alert
+	for rows.Next() {
+		var id, diseaseID uint32
+		var expiresAt *time.Time
+		var a models.Alert
+		if err := rows.Scan(&id, &diseaseID, &a.Severity, &a.Message, &a.CreatedAt, &expiresAt); err != nil {
+			return nil, fmt.Errorf("error scanning alert row: %w", err)
+		}
+		a.ID = int(id)
+		a.DiseaseID = int(diseaseID)
+		if expiresAt != nil {
+			a.ExpiresAt = *expiresAt
+		}
+		alerts = append(alerts, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert rows: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// Create adds a new alert
+func (r *AlertRepository) Create(ctx context.Context, alert *models.Alert) error {
+	var maxID uint32
+	if err := r.db.GetConn().QueryRow(ctx, `SELECT max(id) FROM alerts`).Scan(&maxID); err != nil {
+		return fmt.Errorf("error allocating alert ID: %w", err)
+	}
+	alert.ID = int(maxID) + 1
+	alert.CreatedAt = time.Now().UTC()
+
+	// A zero expiry means the alert never expires and is stored as NULL
+	var expiresAt *time.Time
+	if !alert.ExpiresAt.IsZero() {
+		expiresAt = &alert.ExpiresAt
+	}
+
+	query := `
+		INSERT INTO alerts (id, disease_id, severity, message, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	err := r.db.GetConn().Exec(ctx, query,
+		uint32(alert.ID), uint32(alert.DiseaseID), alert.Severity, alert.Message, alert.CreatedAt, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating alert: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an alert
+func (r *AlertRepository) Delete(ctx context.Context, id int) error {
+	if err := r.db.GetConn().Exec(ctx, `ALTER TABLE alerts DELETE WHERE id = ?`, uint32(id)); err != nil {
+		return fmt.Errorf("error deleting alert: %w", err)
+	}
+
+	return nil
+}
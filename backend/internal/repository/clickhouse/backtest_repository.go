@@ -0,0 +1,101 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// BacktestRepository stores forecast backtest results in the ClickHouse forecast_backtests table.
+// The table is a ReplacingMergeTree on run_at, so a newer run supersedes older rows with the
+// same disease, model, horizon and level.
+type BacktestRepository struct {
+	db *database.DB
+}
+
+// NewBacktestRepository creates a new BacktestRepository
+func NewBacktestRepository(db *database.DB) *BacktestRepository {
+	return &BacktestRepository{db: db}
+}
+
+// Save stores results
+func (r *BacktestRepository) Save(ctx context.Context, results []models.BacktestResult) error {
+	batch, err := r.db.GetConn().PrepareBatch(ctx, `
+		INSERT INTO forecast_backtests (
+			disease, model, horizon, level, first_origin_year, first_origin_quarter,
+			last_origin_year, last_origin_quarter, origins, skipped_origins, forecasts,
+			mae, mape, smape, mase, coverage, run_at
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("error preparing backtest batch: %w", err)
+	}
+
+	for _, res := range results {
+		err := batch.Append(
+			res.Disease, res.Model, uint8(res.Horizon), res.Level,
+			uint16(res.FirstOrigin.Year), uint8(res.FirstOrigin.Quarter),
+			uint16(res.LastOrigin.Year), uint8(res.LastOrigin.Quarter),
+			uint32(res.Origins), uint32(res.SkippedOrigins), uint32(res.Forecasts),
+			res.MAE, res.MAPE, res.SMAPE, res.MASE, res.Coverage, res.RunAt,
+		)
+		if err != nil {
+			return fmt.Errorf("error appending backtest result: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("error saving backtest results: %w", err)
+	}
+	return nil
+}
+
+// List returns the stored results, most recent run first
+func (r *BacktestRepository) List(ctx context.Context, disease, model string) ([]models.BacktestResult, error) {
+	query := `
+		SELECT
+			disease, model, horizon, level, first_origin_year, first_origin_quarter,
+			last_origin_year, last_origin_quarter, origins, skipped_origins, forecasts,
+			mae, mape, smape, mase, coverage, run_at
+		FROM forecast_backtests FINAL
+		WHERE (? = '' OR disease = ?) AND (? = '' OR model = ?)
+		ORDER BY run_at DESC, disease, model, horizon
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query, disease, disease, model, model)
+	if err != nil {
+		return nil, fmt.Errorf("error querying backtest results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.BacktestResult
+	for rows.Next() {
+		var horizon, firstQuarter, lastQuarter uint8
+		var firstYear, lastYear uint16
+		var origins, skipped, forecasts uint32
+		var res models.BacktestResult
+
+		err := rows.Scan(
+			&res.Disease, &res.Model, &horizon, &res.Level, &firstYear, &firstQuarter,
+			&lastYear, &lastQuarter, &origins, &skipped, &forecasts,
+			&res.MAE, &res.MAPE, &res.SMAPE, &res.MASE, &res.Coverage, &res.RunAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning backtest row: %w", err)
+		}
+
+		res.Horizon = int(horizon)
+		res.FirstOrigin = models.YearQuarter{Year: int(firstYear), Quarter: int(firstQuarter)}
+		res.LastOrigin = models.YearQuarter{Year: int(lastYear), Quarter: int(lastQuarter)}
+		res.Origins, res.SkippedOrigins, res.Forecasts = int(origins), int(skipped), int(forecasts)
+		results = append(results, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating backtest rows: %w", err)
+	}
+
+	return results, nil
+}
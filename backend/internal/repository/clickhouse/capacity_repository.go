@@ -0,0 +1,109 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// CapacityRepository reads bed profiles from the ClickHouse bed_profiles table and stores bed
+// counts in hospital_beds, a ReplacingMergeTree keyed by profile and year
+type CapacityRepository struct {
+	db *database.DB
+}
+
+// NewCapacityRepository creates a new CapacityRepository
+func NewCapacityRepository(db *database.DB) *CapacityRepository {
+	return &CapacityRepository{db: db}
+}
+
+// Profiles retrieves all bed profiles
+func (r *CapacityRepository) Profiles(ctx context.Context) ([]models.BedProfile, error) {
+	rows, err := r.db.GetConn().Query(ctx, `SELECT profile, name, categories FROM bed_profiles FINAL ORDER BY profile`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying bed profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []models.BedProfile
+	for rows.Next() {
+		var p models.BedProfile
+		if err := rows.Scan(&p.Profile, &p.Name, &p.Categories); err != nil {
+			return nil, fmt.Errorf("error scanning bed profile: %w", err)
+		}
+		profiles = append(profiles, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bed profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// Beds retrieves the bed counts matching the filter
+func (r *CapacityRepository) Beds(ctx context.Context, filter models.CapacityFilter) ([]models.HospitalBeds, error) {
+	pred, err := repository.CompileCapacityFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := pred.SQL()
+	query := `
+		SELECT profile, year, beds, status
+		FROM hospital_beds FINAL
+		WHERE ` + where + `
+		ORDER BY profile, year
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying hospital beds: %w", err)
+	}
+	defer rows.Close()
+
+	var beds []models.HospitalBeds
+	for rows.Next() {
+		var b models.HospitalBeds
+		var status string
+		if err := rows.Scan(&b.Profile, &b.Year, &b.Beds, &status); err != nil {
+			return nil, fmt.Errorf("error scanning hospital beds: %w", err)
+		}
+		b.Status = models.ValueStatus(status)
+		beds = append(beds, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hospital beds: %w", err)
+	}
+
+	return beds, nil
+}
+
+// Save stores bed counts, superseding those with the same profile and year once the table merges
+func (r *CapacityRepository) Save(ctx context.Context, beds []models.HospitalBeds) error {
+	batch, err := r.db.GetConn().PrepareBatch(ctx, `
+		INSERT INTO hospital_beds (profile, year, beds, status)
+	`)
+	if err != nil {
+		return fmt.Errorf("error preparing hospital beds batch: %w", err)
+	}
+
+	for _, b := range beds {
+		status := b.Status
+		if status == "" {
+			status = models.StatusObserved
+		}
+		if err := batch.Append(b.Profile, b.Year, b.Beds, string(status)); err != nil {
+			return fmt.Errorf("error appending hospital beds: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("error saving hospital beds: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// CategoryRepository stores disease categories in the ClickHouse categories table
+type CategoryRepository struct {
+	db *database.DB
+}
+
+// NewCategoryRepository creates a new CategoryRepository
+func NewCategoryRepository(db *database.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// List retrieves all disease categories
+func (r *CategoryRepository) List(ctx context.Context) ([]models.Category, error) {
+	rows, err := r.db.GetConn().Query(ctx, `SELECT id, name FROM categories ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		var id uint32
+		var c models.Category
+		if err := rows.Scan(&id, &c.Name); err != nil {
+			return nil, fmt.Errorf("error scanning category row: %w", err)
+		}
+		c.ID = int(id)
+		categories = append(categories, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating category rows: %w", err)
+	}
+
+	return categories, nil
+}
+
+// GetByID retrieves a specific category by ID
+func (r *CategoryRepository) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	var cid uint32
+	var c models.Category
+
+	row := r.db.GetConn().QueryRow(ctx, `SELECT id, name FROM categories WHERE id = ?`, uint32(id))
+	if err := row.Scan(&cid, &c.Name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("category %d: %w", id, models.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error scanning category: %w", err)
+	}
+	c.ID = int(cid)
+
+	return &c, nil
+}
+
+// Create adds a new category, allocating the next free ID when none is given
+func (r *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	if category.ID == 0 {
+		var maxID uint32
+		if err := r.db.GetConn().QueryRow(ctx, `SELECT max(id) FROM categories`).Scan(&maxID); err != nil {
+			return fmt.Errorf("error allocating category ID: %w", err)
+		}
+		category.ID = int(maxID) + 1
+	}
+
+	err := r.db.GetConn().Exec(ctx, `INSERT INTO categories (id, name) VALUES (?, ?)`, uint32(category.ID), category.Name)
+	if err != nil {
+		return fmt.Errorf("error creating category: %w", err)
+	}
+
+	return nil
+}
+
+// Update renames an existing category
+func (r *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	err := r.db.GetConn().Exec(ctx, `ALTER TABLE categories UPDATE name = ? WHERE id = ?`, category.Name, uint32(category.ID))
+	if err != nil {
+		return fmt.Errorf("error updating category: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a category
+func (r *CategoryRepository) Delete(ctx context.Context, id int) error {
+	if err := r.db.GetConn().Exec(ctx, `ALTER TABLE categories DELETE WHERE id = ?`, uint32(id)); err != nil {
+		return fmt.Errorf("error deleting category: %w", err)
+	}
+
+	return nil
+}
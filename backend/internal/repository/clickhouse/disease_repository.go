@@ -0,0 +1,326 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+const diseaseColumns = `
+	id, name, category, year, quarter, region, age_group, cases, deaths,
+	recoveries, population, incidence_rate, prevalence_rate, mortality_rate, status
+`
+
+// reportedCondition selects the records whose source cell reported a value
+const reportedCondition = `status NOT IN ('not_available', 'confidential')`
+
+// DiseaseRepository stores disease records in the ClickHouse diseases table
+type DiseaseRepository struct {
+	db *database.DB
+}
+
+// NewDiseaseRepository creates a new DiseaseRepository
+func NewDiseaseRepository(db *database.DB) *DiseaseRepository {
+	return &DiseaseRepository{db: db}
+}
+
+// List retrieves diseases based on filter criteria
+func (r *DiseaseRepository) List(ctx context.Context, filter models.DiseaseFilter) ([]models.Disease, error) {
+	pred, err := repository.CompileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := repository.SortSpec(filter)
+	if err != nil {
+		return nil, err
+	}
+	ordering, err := repository.CompileSort(spec)
+	if err != nil {
+		return nil, err
+	}
+	keyset, err := repository.CompileCursor(filter, ordering)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := pred.SQL()
+	if keyset != nil {
+		cond, keyArgs := keyset.SQL()
+		where += " AND " + cond
+		args = append(args, keyArgs...)
+	}
+	query := `SELECT ` + diseaseColumns + ` FROM diseases WHERE ` + where + ` ` + ordering.SQL()
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.GetConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying diseases: %w", err)
+	}
+	defer rows.Close()
+
+	var diseases []models.Disease
+	for rows.Next() {
+		var d models.Disease
+		if err := scanDisease(rows, &d); err != nil {
+			return nil, fmt.Errorf("error scanning disease row: %w", err)
+		}
+		diseases = append(diseases, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating disease rows: %w", err)
+	}
+
+	return diseases, nil
+}
+
+// GetByID retrieves a specific disease by ID
+func (r *DiseaseRepository) GetByID(ctx context.Context, id string) (*models.Disease, error) {
+	query := `SELECT ` + diseaseColumns + ` FROM diseases WHERE id = ?`
+
+	var d models.Disease
+	if err := scanDisease(r.db.GetConn().QueryRow(ctx, query, id), &d); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("disease %q: %w", id, models.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error scanning disease: %w", err)
+	}
+
+	return &d, nil
+}
+
+// Create adds a new disease record
+func (r *DiseaseRepository) Create(ctx context.Context, disease *models.Disease) error {
+	query := `
+		INSERT INTO diseases (
+			id, name, category, year, quarter, region, age_group, cases, deaths,
+			recoveries, population, incidence_rate, prevalence_rate, mortality_rate, status
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+
+	ageGroup := disease.AgeGroup
+	if ageGroup == "" {
+		ageGroup = models.AgeGroupAll
+	}
+	err := r.db.GetConn().Exec(ctx, query,
+		disease.ID, disease.Name, disease.Category, disease.Year, disease.Quarter, disease.Region, ageGroup,
+		disease.Cases, disease.Deaths, disease.Recoveries, disease.Population,
+		disease.IncidenceRate, disease.PrevalenceRate, disease.MortalityRate, string(disease.Status),
+	)
+	if err != nil {
+		return fmt.Errorf("error creating disease: %w", err)
+	}
+
+	return nil
+}
+
+// Update updates an existing disease record
+func (r *DiseaseRepository) Update(ctx context.Context, disease *models.Disease) error {
+	query := `
+		ALTER TABLE diseases UPDATE
+			name = ?,
+			category = ?,
+			region = ?,
+			cases = ?,
+			deaths = ?,
+			recoveries = ?,
+			population = ?,
+			incidence_rate = ?,
+			prevalence_rate = ?,
+			mortality_rate = ?,
+			status = ?
+		WHERE id = ?
+	`
+
+	err := r.db.GetConn().Exec(ctx, query,
+		disease.Name, disease.Category, disease.Region,
+		disease.Cases, disease.Deaths, disease.Recoveries, disease.Population,
+		disease.IncidenceRate, disease.PrevalenceRate, disease.MortalityRate,
+		string(disease.Status), disease.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating disease: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a disease record
+func (r *DiseaseRepository) Delete(ctx context.Context, id string) error {
+	if err := r.db.GetConn().Exec(ctx, `ALTER TABLE diseases DELETE WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("error deleting disease: %w", err)
+	}
+
+	return nil
+}
+
+// Count returns the number of records matching the filter
+func (r *DiseaseRepository) Count(ctx context.Context, filter models.DiseaseFilter) (int, error) {
+	pred, err := repository.CompileFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := pred.SQL()
+	var count uint64
+	if err := r.db.GetConn().QueryRow(ctx, `SELECT count() FROM diseases WHERE `+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting diseases: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// Totals sums cases, deaths and recoveries over the matching records and counts the suppressed ones
+func (r *DiseaseRepository) Totals(ctx context.Context, filter models.DiseaseFilter) (*repository.Totals, error) {
+	pred, err := repository.CompileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := pred.SQL()
+	query := `
+		SELECT
+			SUM(cases) as total_cases,
+			SUM(deaths) as total_deaths,
+			SUM(recoveries) as total_recoveries,
+			ifNotFinite(avgIf(incidence_rate, ` + reportedCondition + `), 0) as avg_rate,
+			COUNT() as matched,
+			countIf(status = 'not_available') as not_available,
+			countIf(status = 'confidential') as confidential
+		FROM diseases
+		WHERE ` + where
+
+	var t repository.Totals
+	var notAvailable, confidential uint64
+	row := r.db.GetConn().QueryRow(ctx, query, args...)
+	if err := row.Scan(&t.Cases, &t.Deaths, &t.Recoveries, &t.AvgIncidence, &t.MatchedRecords, &notAvailable, &confidential); err != nil {
+		return nil, fmt.Errorf("error aggregating diseases: %w", err)
+	}
+	t.Suppressed = models.SuppressedCells{NotAvailable: int(notAvailable), Confidential: int(confidential)}
+
+	return &t, nil
+}
+
+// TimeSeries retrieves per-quarter aggregates for each disease
+func (r *DiseaseRepository) TimeSeries(ctx context.Context, filter models.DiseaseFilter) ([]models.DiseaseTimePoint, error) {
+	pred, err := repository.CompileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := pred.SQL()
+	query := `
+		SELECT
+			year,
+			quarter,
+			name,
+			SUM(cases) as total_cases,
+			ifNotFinite(avgIf(incidence_rate, ` + reportedCondition + `), 0) as incidence_rate,
+			ifNotFinite(avgIf(mortality_rate, ` + reportedCondition + `), 0) as mortality_rate,
+			if(total_cases > 0, SUM(recoveries) / total_cases * 100, 0) as recovery_rate,
+			countIf(` + reportedCondition + `) as reported,
+			countIf(status = 'not_available') as not_available,
+			countIf(status = 'confidential') as confidential
+		FROM diseases
+		WHERE ` + where + `
+		GROUP BY year, quarter, name
+		ORDER BY year, quarter, name
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying time series: %w", err)
+	}
+	defer rows.Close()
+
+	var points []models.DiseaseTimePoint
+	for rows.Next() {
+		var year uint16
+		var quarter uint8
+		var reported, notAvailable, confidential uint64
+		var p models.DiseaseTimePoint
+
+		if err := rows.Scan(
+			&year, &quarter, &p.Name, &p.Cases, &p.IncidenceRate, &p.MortalityRate, &p.RecoveryRate,
+			&reported, &notAvailable, &confidential,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning time series row: %w", err)
+		}
+
+		p.Year = int(year)
+		p.Quarter = int(quarter)
+		p.Suppressed = models.SuppressedCells{NotAvailable: int(notAvailable), Confidential: int(confidential)}
+		p.Status = models.AggregateStatus(int(reported), p.Cases, p.Suppressed)
+		points = append(points, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating time series rows: %w", err)
+	}
+
+	return points, nil
+}
+
+// Rebase sets the population of the reported records of a year, region and age group and
+// recomputes their rates, mirroring repository.Rebase; the assignments of a mutation all read
+// the old row. The mutation runs synchronously, so the rates are rebased once it returns.
+func (r *DiseaseRepository) Rebase(ctx context.Context, year int, region, ageGroup string, population uint32) (int, error) {
+	if population == 0 {
+		return 0, nil
+	}
+
+	where := `year = ? AND region = ? AND age_group = ? AND ` + reportedCondition
+	var count uint64
+	if err := r.db.GetConn().QueryRow(ctx, `SELECT count() FROM diseases WHERE `+where, uint16(year), region, ageGroup).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting diseases to rebase: %w", err)
+	}
+	if count == 0 {
+		return 0, nil
+	}
+
+	query := `
+		ALTER TABLE diseases UPDATE
+			incidence_rate = if(cases > 0, cases * 100000 / ?, if(population > 0, incidence_rate * population / ?, incidence_rate)),
+			mortality_rate = if(deaths > 0, deaths * 100000 / ?, if(population > 0, mortality_rate * population / ?, mortality_rate)),
+			prevalence_rate = if(population > 0, prevalence_rate * population / ?, prevalence_rate),
+			population = ?
+		WHERE ` + where + `
+		SETTINGS mutations_sync = 1`
+
+	p := float64(population)
+	args := []interface{}{p, p, p, p, p, population, uint16(year), region, ageGroup}
+	if err := r.db.GetConn().Exec(ctx, query, args...); err != nil {
+		return 0, fmt.Errorf("error rebasing diseases: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// rowScanner is satisfied by both driver.Row and driver.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanDisease scans the diseaseColumns projection into d
+func scanDisease(row rowScanner, d *models.Disease) error {
+	var status string
+	if err := row.Scan(
+		&d.ID, &d.Name, &d.Category, &d.Year, &d.Quarter, &d.Region, &d.AgeGroup,
+		&d.Cases, &d.Deaths, &d.Recoveries, &d.Population,
+		&d.IncidenceRate, &d.PrevalenceRate, &d.MortalityRate, &status,
+	); err != nil {
+		return err
+	}
+	d.Status = models.ValueStatus(status)
+	return nil
+}
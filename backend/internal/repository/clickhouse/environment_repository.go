@@ -0,0 +1,255 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// EnvironmentRepository reads the monthly station observations of the environment_observations table
+// and the yearly pollutant emissions of the emissions table
+type EnvironmentRepository struct {
+	db *database.DB
+}
+
+// NewEnvironmentRepository creates a new EnvironmentRepository
+func NewEnvironmentRepository(db *database.DB) *EnvironmentRepository {
+	return &EnvironmentRepository{db: db}
+}
+
+// Factors lists the observed variables
+func (r *EnvironmentRepository) Factors(ctx context.Context) ([]string, error) {
+	rows, err := r.db.GetConn().Query(ctx, "SELECT DISTINCT variable FROM environment_observations FINAL ORDER BY variable")
+	if err != nil {
+		return nil, fmt.Errorf("error querying environmental factors: %w", err)
+	}
+	defer rows.Close()
+
+	var factors []string
+	for rows.Next() {
+		var factor string
+		if err := rows.Scan(&factor); err != nil {
+			return nil, fmt.Errorf("error scanning environmental factor: %w", err)
+		}
+		factors = append(factors, factor)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating environmental factors: %w", err)
+	}
+
+	return factors, nil
+}
+
+// Quarterly rolls a variable up per station and quarter, keeping complete quarters only, and
+// averages the stations, matching repository.QuarterlyRollup
+func (r *EnvironmentRepository) Quarterly(ctx context.Context, factor string, startYear, endYear *int) ([]models.EnvironmentPoint, error) {
+	start, end := yearBounds(startYear, endYear)
+	query := `
+		SELECT year, quarter, AVG(station_value) AS value
+		FROM (
+			SELECT
+				station,
+				year,
+				intDiv(month - 1, 3) + 1 AS quarter,
+				if(? = 'sum', SUM(value), AVG(value)) AS station_value
+			FROM environment_observations FINAL
+			WHERE variable = ? AND year BETWEEN ? AND ?
+			GROUP BY station, year, quarter
+			HAVING count() = 3
+		)
+		GROUP BY year, quarter
+		ORDER BY year, quarter
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query, models.VariableAggregation(factor), factor, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error querying environmental series: %w", err)
+	}
+	defer rows.Close()
+
+	var points []models.EnvironmentPoint
+	for rows.Next() {
+		var year uint16
+		var quarter uint8
+		p := models.EnvironmentPoint{Factor: factor}
+
+		if err := rows.Scan(&year, &quarter, &p.Value); err != nil {
+			return nil, fmt.Errorf("error scanning environmental series row: %w", err)
+		}
+
+		p.Year = int(year)
+		p.Quarter = int(quarter)
+		points = append(points, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating environmental series rows: %w", err)
+	}
+
+	return points, nil
+}
+
+// Monthly averages a variable over the stations per month
+func (r *EnvironmentRepository) Monthly(ctx context.Context, factor string, startYear, endYear *int) ([]models.EnvironmentPoint, error) {
+	start, end := yearBounds(startYear, endYear)
+	query := `
+		SELECT year, month, AVG(value) AS value
+		FROM environment_observations FINAL
+		WHERE variable = ? AND year BETWEEN ? AND ?
+		GROUP BY year, month
+		ORDER BY year, month
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query, factor, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error querying monthly environmental series: %w", err)
+	}
+	defer rows.Close()
+
+	var points []models.EnvironmentPoint
+	for rows.Next() {
+		var year uint16
+		var month uint8
+		p := models.EnvironmentPoint{Factor: factor}
+
+		if err := rows.Scan(&year, &month, &p.Value); err != nil {
+			return nil, fmt.Errorf("error scanning monthly environmental series row: %w", err)
+		}
+
+		p.Year = int(year)
+		p.Month = int(month)
+		p.Quarter = (p.Month-1)/3 + 1
+		points = append(points, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating monthly environmental series rows: %w", err)
+	}
+
+	return points, nil
+}
+
+// Observations returns the monthly observations matching the filter
+func (r *EnvironmentRepository) Observations(ctx context.Context, filter models.EnvironmentFilter) ([]models.EnvironmentObservation, error) {
+	pred, err := repository.CompileEnvironmentFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := pred.SQL()
+	query := `
+		SELECT station, variable, year, month, value
+		FROM environment_observations FINAL
+		WHERE ` + where + `
+		ORDER BY station, variable, year, month
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying environment observations: %w", err)
+	}
+	defer rows.Close()
+
+	var observations []models.EnvironmentObservation
+	for rows.Next() {
+		var o models.EnvironmentObservation
+		if err := rows.Scan(&o.Station, &o.Variable, &o.Year, &o.Month, &o.Value); err != nil {
+			return nil, fmt.Errorf("error scanning environment observation: %w", err)
+		}
+		observations = append(observations, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating environment observations: %w", err)
+	}
+
+	return observations, nil
+}
+
+// Stations summarises the observations per station
+func (r *EnvironmentRepository) Stations(ctx context.Context) ([]models.Station, error) {
+	query := `
+		SELECT station, arraySort(groupUniqArray(variable)), min(year), max(year)
+		FROM environment_observations FINAL
+		GROUP BY station
+		ORDER BY station
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying stations: %w", err)
+	}
+	defer rows.Close()
+
+	var stations []models.Station
+	for rows.Next() {
+		var st models.Station
+		var firstYear, lastYear uint16
+		if err := rows.Scan(&st.Name, &st.Variables, &firstYear, &lastYear); err != nil {
+			return nil, fmt.Errorf("error scanning station: %w", err)
+		}
+		st.FirstYear, st.LastYear = int(firstYear), int(lastYear)
+		stations = append(stations, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stations: %w", err)
+	}
+
+	return stations, nil
+}
+
+// Emissions retrieves the emissions matching the filter
+func (r *EnvironmentRepository) Emissions(ctx context.Context, filter models.EmissionFilter) ([]models.Emission, error) {
+	pred, err := repository.CompileEmissionFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := pred.SQL()
+	query := `
+		SELECT pollutant, year, value, status
+		FROM emissions FINAL
+		WHERE ` + where + `
+		ORDER BY pollutant, year
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying emissions: %w", err)
+	}
+	defer rows.Close()
+
+	var emissions []models.Emission
+	for rows.Next() {
+		var e models.Emission
+		var status string
+		if err := rows.Scan(&e.Pollutant, &e.Year, &e.Value, &status); err != nil {
+			return nil, fmt.Errorf("error scanning emission: %w", err)
+		}
+		e.Status = models.ValueStatus(status)
+		emissions = append(emissions, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating emissions: %w", err)
+	}
+
+	return emissions, nil
+}
+
+// yearBounds turns optional year bounds into the inclusive UInt16 range they select
+func yearBounds(startYear, endYear *int) (uint16, uint16) {
+	start, end := uint16(0), uint16(65535)
+	if startYear != nil && *startYear > 0 {
+		start = uint16(*startYear)
+	}
+	if endYear != nil && *endYear >= 0 && *endYear < 65535 {
+		end = uint16(*endYear)
+	}
+	return start, end
+}
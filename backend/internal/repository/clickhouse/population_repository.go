@@ -0,0 +1,81 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// PopulationRepository stores population entries in the ClickHouse population table. The table is
+// a ReplacingMergeTree on revised_at, so a revision supersedes the entries it restates.
+type PopulationRepository struct {
+	db *database.DB
+}
+
+// NewPopulationRepository creates a new PopulationRepository
+func NewPopulationRepository(db *database.DB) *PopulationRepository {
+	return &PopulationRepository{db: db}
+}
+
+// List retrieves the entries matching the filter
+func (r *PopulationRepository) List(ctx context.Context, filter models.PopulationFilter) ([]models.PopulationEntry, error) {
+	pred, err := repository.CompilePopulationFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := pred.SQL()
+	query := `
+		SELECT year, age, medium, sex, population
+		FROM population FINAL
+		WHERE ` + where + `
+		ORDER BY year, medium, sex, age
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying population: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.PopulationEntry
+	for rows.Next() {
+		var e models.PopulationEntry
+		if err := rows.Scan(&e.Year, &e.Age, &e.Medium, &e.Sex, &e.Population); err != nil {
+			return nil, fmt.Errorf("error scanning population entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating population entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Save stores entries, superseding those with the same year, age, medium and sex
+func (r *PopulationRepository) Save(ctx context.Context, entries []models.PopulationEntry) error {
+	batch, err := r.db.GetConn().PrepareBatch(ctx, `
+		INSERT INTO population (year, age, medium, sex, population, revised_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("error preparing population batch: %w", err)
+	}
+
+	revisedAt := time.Now().UTC()
+	for _, e := range entries {
+		if err := batch.Append(e.Year, e.Age, e.Medium, e.Sex, e.Population, revisedAt); err != nil {
+			return fmt.Errorf("error appending population entry: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("error saving population: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+// Package clickhouse implements the repository contracts on top of a ClickHouse connection.
+package clickhouse
+
+import (
+	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// NewStore creates a store whose repositories all share the given connection
+func NewStore(db *database.DB) *repository.Store {
+	return &repository.Store{
+		Diseases:    NewDiseaseRepository(db),
+		Categories:  NewCategoryRepository(db),
+		Alerts:      NewAlertRepository(db),
+		Backtests:   NewBacktestRepository(db),
+		Environment: NewEnvironmentRepository(db),
+		Population:  NewPopulationRepository(db),
+		Stratified:  NewStratifiedRepository(db),
+		Vaccination: NewVaccinationRepository(db),
+		Capacity:    NewCapacityRepository(db),
+	}
+}
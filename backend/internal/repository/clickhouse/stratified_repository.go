@@ -0,0 +1,86 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// StratifiedRepository stores stratified facts in the ClickHouse stratified_facts table, a
+// ReplacingMergeTree keyed by indicator, year and stratum
+type StratifiedRepository struct {
+	db *database.DB
+}
+
+// NewStratifiedRepository creates a new StratifiedRepository
+func NewStratifiedRepository(db *database.DB) *StratifiedRepository {
+	return &StratifiedRepository{db: db}
+}
+
+// Facts retrieves the facts matching the filter
+func (r *StratifiedRepository) Facts(ctx context.Context, filter models.StratifiedFilter) ([]models.StratifiedFact, error) {
+	pred, err := repository.CompileStratifiedFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := pred.SQL()
+	query := `
+		SELECT indicator, year, age, sex, medium, value, status
+		FROM stratified_facts FINAL
+		WHERE ` + where + `
+		ORDER BY indicator, year, medium, sex, age
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying stratified facts: %w", err)
+	}
+	defer rows.Close()
+
+	var facts []models.StratifiedFact
+	for rows.Next() {
+		var f models.StratifiedFact
+		var status string
+		if err := rows.Scan(&f.Indicator, &f.Year, &f.Age, &f.Sex, &f.Medium, &f.Value, &status); err != nil {
+			return nil, fmt.Errorf("error scanning stratified fact: %w", err)
+		}
+		f.Status = models.ValueStatus(status)
+		facts = append(facts, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stratified facts: %w", err)
+	}
+
+	return facts, nil
+}
+
+// Save stores facts, superseding those with the same indicator, year, age, sex and medium once
+// the table merges
+func (r *StratifiedRepository) Save(ctx context.Context, facts []models.StratifiedFact) error {
+	batch, err := r.db.GetConn().PrepareBatch(ctx, `
+		INSERT INTO stratified_facts (indicator, year, age, sex, medium, value, status)
+	`)
+	if err != nil {
+		return fmt.Errorf("error preparing stratified facts batch: %w", err)
+	}
+
+	for _, f := range facts {
+		status := f.Status
+		if status == "" {
+			status = models.StatusObserved
+		}
+		if err := batch.Append(f.Indicator, f.Year, f.Age, f.Sex, f.Medium, f.Value, string(status)); err != nil {
+			return fmt.Errorf("error appending stratified fact: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("error saving stratified facts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// VaccinationRepository reads vaccines from the ClickHouse vaccines table and stores coverage
+// figures in vaccination_coverage, a ReplacingMergeTree keyed by antigen, age and year
+type VaccinationRepository struct {
+	db *database.DB
+}
+
+// NewVaccinationRepository creates a new VaccinationRepository
+func NewVaccinationRepository(db *database.DB) *VaccinationRepository {
+	return &VaccinationRepository{db: db}
+}
+
+// Vaccines retrieves all vaccines
+func (r *VaccinationRepository) Vaccines(ctx context.Context) ([]models.Vaccine, error) {
+	rows, err := r.db.GetConn().Query(ctx, `SELECT antigen, name, diseases FROM vaccines FINAL ORDER BY antigen`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying vaccines: %w", err)
+	}
+	defer rows.Close()
+
+	var vaccines []models.Vaccine
+	for rows.Next() {
+		var v models.Vaccine
+		if err := rows.Scan(&v.Antigen, &v.Name, &v.Diseases); err != nil {
+			return nil, fmt.Errorf("error scanning vaccine: %w", err)
+		}
+		vaccines = append(vaccines, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vaccines: %w", err)
+	}
+
+	return vaccines, nil
+}
+
+// Coverage retrieves the coverage figures matching the filter
+func (r *VaccinationRepository) Coverage(ctx context.Context, filter models.VaccinationFilter) ([]models.VaccinationCoverage, error) {
+	pred, err := repository.CompileVaccinationFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := pred.SQL()
+	query := `
+		SELECT antigen, age, year, coverage, status
+		FROM vaccination_coverage FINAL
+		WHERE ` + where + `
+		ORDER BY antigen, age, year
+	`
+
+	rows, err := r.db.GetConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying vaccination coverage: %w", err)
+	}
+	defer rows.Close()
+
+	var coverage []models.VaccinationCoverage
+	for rows.Next() {
+		var c models.VaccinationCoverage
+		var status string
+		if err := rows.Scan(&c.Antigen, &c.Age, &c.Year, &c.Coverage, &status); err != nil {
+			return nil, fmt.Errorf("error scanning vaccination coverage: %w", err)
+		}
+		c.Status = models.ValueStatus(status)
+		coverage = append(coverage, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vaccination coverage: %w", err)
+	}
+
+	return coverage, nil
+}
+
+// Save stores coverage figures, superseding those with the same antigen, age and year once the
+// table merges
+func (r *VaccinationRepository) Save(ctx context.Context, coverage []models.VaccinationCoverage) error {
+	batch, err := r.db.GetConn().PrepareBatch(ctx, `
+		INSERT INTO vaccination_coverage (antigen, age, year, coverage, status)
+	`)
+	if err != nil {
+		return fmt.Errorf("error preparing vaccination coverage batch: %w", err)
+	}
+
+	for _, c := range coverage {
+		status := c.Status
+		if status == "" {
+			status = models.StatusObserved
+		}
+		if err := batch.Append(c.Antigen, c.Age, c.Year, c.Coverage, string(status)); err != nil {
+			return fmt.Errorf("error appending vaccination coverage: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("error saving vaccination coverage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// cursorToken is the decoded form of an opaque pagination cursor: the sort spec it was issued
+// for and the sort-key values of the last record on the page
+type cursorToken struct {
+	Sort   string `json:"s"`
+	Values []any  `json:"v"`
+}
+
+// Keyset is a decoded cursor restricting a listing to the records after a given position.
+// Its condition is an OR over the prefixes of the ordering, ending in the record ID, which is not
+// part of the table's ordering key: ClickHouse can at best skip granules on the leading sort field,
+// then filters and sorts every remaining match. Deep pages thus avoid reading and discarding an
+// OFFSET worth of rows but still cost about as much as a scan of the filtered records.
+type Keyset struct {
+	ordering Ordering
+	values   []any
+}
+
+// EncodeCursor returns an opaque cursor pointing just past d under the given ordering
+func EncodeCursor(ordering Ordering, d *models.Disease) string {
+	token := cursorToken{Sort: ordering.String(), Values: make([]any, 0, len(ordering))}
+	for _, k := range ordering {
+		token.Values = append(token.Values, sortFields[k.Field].value(d))
+	}
+
+	raw, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor validates a cursor against the ordering of the current request.
+// Errors wrap models.ErrInvalidFilter.
+func DecodeCursor(cursor string, ordering Ordering) (*Keyset, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", models.ErrInvalidFilter)
+	}
+
+	var token cursorToken
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&token); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", models.ErrInvalidFilter)
+	}
+
+	if token.Sort != ordering.String() {
+		return nil, fmt.Errorf("%w: cursor was issued for sort %q, not %q", models.ErrInvalidFilter, token.Sort, ordering.String())
+	}
+	if len(token.Values) != len(ordering) {
+		return nil, fmt.Errorf("%w: malformed cursor", models.ErrInvalidFilter)
+	}
+
+	// Restore each value to the type its field compares with
+	values := make([]any, len(ordering))
+	for i, k := range ordering {
+		v, ok := decodeCursorValue(token.Values[i], sortFields[k.Field].value(&models.Disease{}))
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed cursor", models.ErrInvalidFilter)
+		}
+		values[i] = v
+	}
+
+	return &Keyset{ordering: ordering, values: values}, nil
+}
+
+// CompileCursor decodes the cursor of the filter, if any, for the given ordering.
+// It returns nil when the filter has no cursor.
+func CompileCursor(filter models.DiseaseFilter, ordering Ordering) (*Keyset, error) {
+	if filter.Cursor == "" {
+		return nil, nil
+	}
+	if filter.Offset > 0 {
+		return nil, fmt.Errorf("%w: cursor and offset cannot be combined", models.ErrInvalidFilter)
+	}
+	return DecodeCursor(filter.Cursor, ordering)
+}
+
+// SQL renders the keyset as a WHERE condition selecting the records after the cursor
+func (k *Keyset) SQL() (string, []interface{}) {
+	ors := make([]string, 0, len(k.ordering))
+	var args []interface{}
+
+	for i, key := range k.ordering {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, sortFields[k.ordering[j].Field].sql+" = ?")
+			args = append(args, k.values[j])
+		}
+
+		op := " > ?"
+		if key.Desc {
+			op = " < ?"
+		}
+		ands = append(ands, sortFields[key.Field].sql+op)
+		args = append(args, k.values[i])
+
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+// After reports whether d sorts after the cursor position
+func (k *Keyset) After(d *models.Disease) bool {
+	for i, key := range k.ordering {
+		if c := compareValues(sortFields[key.Field].value(d), k.values[i]); c != 0 {
+			return (c > 0) != key.Desc
+		}
+	}
+	return false
+}
+
+// decodeCursorValue converts a JSON-decoded cursor value to the type of the example value
+func decodeCursorValue(v any, example any) (any, bool) {
+	switch example.(type) {
+	case string:
+		s, ok := v.(string)
+		return s, ok
+	case uint64:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, false
+		}
+		i, err := n.Int64()
+		if err != nil || i < 0 {
+			return nil, false
+		}
+		return uint64(i), true
+	case float64:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, false
+		}
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return nil, false
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// pageThrough lists the records under an ordering a few at a time, each page starting after the
+// cursor of the one before, as a client following nextCursor does
+func pageThrough(t *testing.T, ordering Ordering, records []models.Disease, limit int) []string {
+	t.Helper()
+
+	var ids []string
+	var keyset *Keyset
+	for pages := 0; pages <= len(records); pages++ {
+		var page []models.Disease
+		for _, d := range records {
+			if keyset == nil || keyset.After(&d) {
+				page = append(page, d)
+			}
+		}
+		sort.SliceStable(page, func(i, j int) bool { return ordering.Less(&page[i], &page[j]) })
+		if len(page) <= limit {
+			for _, d := range page {
+				ids = append(ids, d.ID)
+			}
+			return ids
+		}
+
+		page = page[:limit]
+		for _, d := range page {
+			ids = append(ids, d.ID)
+		}
+		var err error
+		if keyset, err = DecodeCursor(EncodeCursor(ordering, &page[limit-1]), ordering); err != nil {
+			t.Fatalf("DecodeCursor: %v", err)
+		}
+	}
+	t.Fatal("paging did not terminate")
+	return nil
+}
+
+func TestKeysetPagingVisitsEveryRecordOnce(t *testing.T) {
+	for _, spec := range []string{"", "-year", "-cases,name", "region,-quarter", "-deathsPerCase", "incidenceRate"} {
+		ordering, err := CompileSort(spec)
+		if err != nil {
+			t.Fatalf("CompileSort(%q): %v", spec, err)
+		}
+
+		all := append([]models.Disease(nil), testDiseases...)
+		sort.SliceStable(all, func(i, j int) bool { return ordering.Less(&all[i], &all[j]) })
+		var want []string
+		for _, d := range all {
+			want = append(want, d.ID)
+		}
+
+		for _, limit := range []int{1, 2, 3, len(testDiseases)} {
+			if got := pageThrough(t, ordering, testDiseases, limit); !equalStrings(got, want) {
+				t.Errorf("sort %q, limit %d: paged %v, want %v", spec, limit, got, want)
+			}
+		}
+	}
+}
+
+func TestKeysetSQLAgreesWithAfter(t *testing.T) {
+	for _, spec := range []string{"", "-year", "-cases,name", "region,-quarter,-deaths"} {
+		ordering, err := CompileSort(spec)
+		if err != nil {
+			t.Fatalf("CompileSort(%q): %v", spec, err)
+		}
+
+		for i := range testDiseases {
+			keyset, err := DecodeCursor(EncodeCursor(ordering, &testDiseases[i]), ordering)
+			if err != nil {
+				t.Fatalf("DecodeCursor: %v", err)
+			}
+			where, args := keyset.SQL()
+			for j := range testDiseases {
+				d := &testDiseases[j]
+				if sql, after := evalWhere(t, where, args, diseaseRow(d)), keyset.After(d); sql != after {
+					t.Errorf("sort %q after %s: SQL %q selects %s: %v, After: %v",
+						spec, testDiseases[i].ID, where, d.ID, sql, after)
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeCursorRejectsForeignCursors(t *testing.T) {
+	byYear, _ := CompileSort("-year")
+	byCases, _ := CompileSort("cases")
+	valid := EncodeCursor(byYear, &testDiseases[0])
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "%%%"},
+		{"not JSON", base64.RawURLEncoding.EncodeToString([]byte("year=2020"))},
+		{"issued for another sort", EncodeCursor(byCases, &testDiseases[0])},
+		{"too few values", base64.RawURLEncoding.EncodeToString([]byte(`{"s":"-year,id","v":[2020]}`))},
+		{"string for a number", base64.RawURLEncoding.EncodeToString([]byte(`{"s":"-year,id","v":["2020","x"]}`))},
+		{"negative year", base64.RawURLEncoding.EncodeToString([]byte(`{"s":"-year,id","v":[-1,"x"]}`))},
+	}
+	for _, tt := range tests {
+		if _, err := DecodeCursor(tt.cursor, byYear); !errors.Is(err, models.ErrInvalidFilter) {
+			t.Errorf("%s: error = %v, want ErrInvalidFilter", tt.name, err)
+		}
+	}
+
+	if _, err := DecodeCursor(valid, byYear); err != nil {
+		t.Errorf("valid cursor: %v", err)
+	}
+	if _, err := CompileCursor(models.DiseaseFilter{Cursor: valid, Offset: 10}, byYear); !errors.Is(err, models.ErrInvalidFilter) {
+		t.Errorf("cursor with offset: error = %v, want ErrInvalidFilter", err)
+	}
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// disabilitySortFields whitelists the sortable fields of models.DisabilityRate by their JSON names.
+// Missing rates and changes sort below every value.
+var disabilitySortFields = map[string]func(r *models.DisabilityRate) any{
+	"year":          func(r *models.DisabilityRate) any { return uint64(r.Year) },
+	"age":           func(r *models.DisabilityRate) any { return r.Age },
+	"sex":           func(r *models.DisabilityRate) any { return r.Sex },
+	"medium":        func(r *models.DisabilityRate) any { return r.Medium },
+	"persons":       func(r *models.DisabilityRate) any { return r.Persons },
+	"adults":        func(r *models.DisabilityRate) any { return r.Adults },
+	"ratePer10k":    func(r *models.DisabilityRate) any { return orLowest(r.RatePer10k) },
+	"change":        func(r *models.DisabilityRate) any { return orLowest(r.Change) },
+	"changePercent": func(r *models.DisabilityRate) any { return orLowest(r.ChangePercent) },
+}
+
+// disabilityStratum identifies a row of the disability table; it ends every disability ordering
+var disabilityStratum = []string{"year", "age", "sex", "medium"}
+
+// DisabilityOrdering is a validated multi-key sort of disability rows. It always ends with the
+// year and stratum so that the rows have a total order.
+type DisabilityOrdering []SortKey
+
+// CompileDisabilitySort validates a sort spec over the fields of models.DisabilityRate, written as
+// for CompileSort. An empty spec sorts by year and stratum.
+func CompileDisabilitySort(spec string) (DisabilityOrdering, error) {
+	ordering, err := parseSort(spec, DisabilitySortableFields(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range disabilityStratum {
+		if !sortsBy(ordering, field) {
+			ordering = append(ordering, SortKey{Field: field})
+		}
+	}
+	return ordering, nil
+}
+
+// DisabilitySortSpec combines the sort, sortBy and sortOrder fields of a disability filter into a
+// single sort spec as SortSpec does
+func DisabilitySortSpec(filter models.DisabilityFilter) (string, error) {
+	return sortSpec(filter.Sort, filter.SortBy, filter.SortOrder)
+}
+
+// DisabilitySortableFields returns the names accepted in a disability sort spec
+func DisabilitySortableFields() []string {
+	fields := make([]string, 0, len(disabilitySortFields))
+	for f := range disabilitySortFields {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Less reports whether a sorts before b; ages are ordered youngest first as AgeLess does
+func (o DisabilityOrdering) Less(a, b *models.DisabilityRate) bool {
+	for _, k := range o {
+		var c int
+		if k.Field == "age" {
+			c = compareAges(a.Age, b.Age)
+		} else {
+			value := disabilitySortFields[k.Field]
+			c = compareValues(value(a), value(b))
+		}
+		if c != 0 {
+			return (c < 0) != k.Desc
+		}
+	}
+	return false
+}
+
+// compareAges compares two ages in the order of AgeLess
+func compareAges(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case AgeLess(a, b):
+		return -1
+	default:
+		return 1
+	}
+}
+
+// orLowest returns a value or, when missing, one below every value
+func orLowest(v *float64) float64 {
+	if v == nil {
+		return math.Inf(-1)
+	}
+	return *v
+}
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// monthsPerQuarter is the number of monthly observations a complete quarter holds
+const monthsPerQuarter = 3
+
+// EnvironmentPredicate is a models.EnvironmentFilter compiled into the row conditions every
+// backend applies to environment observations
+type EnvironmentPredicate struct {
+	variables []string
+	stations  []string
+	startYear *uint16
+	endYear   *uint16
+	months    []uint8
+}
+
+// CompileEnvironmentFilter validates an environment filter and compiles it into a predicate.
+// Quarters select their three months. Validation errors wrap models.ErrInvalidFilter.
+func CompileEnvironmentFilter(filter models.EnvironmentFilter) (*EnvironmentPredicate, error) {
+	// The year and quarter conditions are validated exactly like those of a disease filter
+	if _, err := CompileFilter(models.DiseaseFilter{StartYear: filter.StartYear, EndYear: filter.EndYear, Quarters: filter.Quarters}); err != nil {
+		return nil, err
+	}
+
+	p := EnvironmentPredicate{
+		variables: nonEmpty(filter.Variables),
+		stations:  nonEmpty(filter.Stations),
+	}
+	if filter.StartYear != nil {
+		y := uint16(*filter.StartYear)
+		p.startYear = &y
+	}
+	if filter.EndYear != nil {
+		y := uint16(*filter.EndYear)
+		p.endYear = &y
+	}
+	for _, q := range filter.Quarters {
+		for m := 1; m <= monthsPerQuarter; m++ {
+			p.months = append(p.months, uint8((q-1)*monthsPerQuarter+m))
+		}
+	}
+
+	switch filter.Granularity {
+	case "", models.GranularityQuarter, models.GranularityMonth:
+	default:
+		return nil, fmt.Errorf("%w: granularity must be %q or %q", models.ErrInvalidFilter, models.GranularityQuarter, models.GranularityMonth)
+	}
+
+	return &p, nil
+}
+
+// SQL renders the predicate as a WHERE condition over environment_observations with its arguments
+func (p *EnvironmentPredicate) SQL() (string, []interface{}) {
+	conds := []string{"1=1"}
+	var args []interface{}
+
+	if len(p.variables) > 0 {
+		conds = append(conds, "variable IN ("+placeholders(len(p.variables))+")")
+		for _, v := range p.variables {
+			args = append(args, v)
+		}
+	}
+
+	if len(p.stations) > 0 {
+		conds = append(conds, "station IN ("+placeholders(len(p.stations))+")")
+		for _, s := range p.stations {
+			args = append(args, s)
+		}
+	}
+
+	if p.startYear != nil {
+		conds = append(conds, "year >= ?")
+		args = append(args, *p.startYear)
+	}
+
+	if p.endYear != nil {
+		conds = append(conds, "year <= ?")
+		args = append(args, *p.endYear)
+	}
+
+	if len(p.months) > 0 {
+		conds = append(conds, "month IN ("+placeholders(len(p.months))+")")
+		for _, m := range p.months {
+			args = append(args, m)
+		}
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// Match reports whether an observation satisfies the predicate
+func (p *EnvironmentPredicate) Match(o *models.EnvironmentObservation) bool {
+	if len(p.variables) > 0 && !contains(p.variables, o.Variable) {
+		return false
+	}
+	if len(p.stations) > 0 && !contains(p.stations, o.Station) {
+		return false
+	}
+	if p.startYear != nil && o.Year < *p.startYear {
+		return false
+	}
+	if p.endYear != nil && o.Year > *p.endYear {
+		return false
+	}
+	if len(p.months) > 0 && !contains(p.months, o.Month) {
+		return false
+	}
+	return true
+}
+
+// EmissionPredicate is a models.EmissionFilter compiled into the row conditions every backend
+// applies to emissions
+type EmissionPredicate struct {
+	pollutants []string
+	startYear  *uint16
+	endYear    *uint16
+}
+
+// CompileEmissionFilter validates an emission filter and compiles it into a predicate. Validation
+// errors wrap models.ErrInvalidFilter.
+func CompileEmissionFilter(filter models.EmissionFilter) (*EmissionPredicate, error) {
+	// The years are validated exactly like those of a disease filter
+	if _, err := CompileFilter(models.DiseaseFilter{StartYear: filter.StartYear, EndYear: filter.EndYear}); err != nil {
+		return nil, err
+	}
+
+	p := EmissionPredicate{pollutants: nonEmpty(filter.Pollutants)}
+	for _, pollutant := range p.pollutants {
+		if !contains(models.Pollutants, pollutant) {
+			return nil, fmt.Errorf("%w: pollutants: %q is not one of %s", models.ErrInvalidFilter,
+				pollutant, strings.Join(models.Pollutants, ", "))
+		}
+	}
+	if filter.StartYear != nil {
+		y := uint16(*filter.StartYear)
+		p.startYear = &y
+	}
+	if filter.EndYear != nil {
+		y := uint16(*filter.EndYear)
+		p.endYear = &y
+	}
+	return &p, nil
+}
+
+// SQL renders the predicate as a WHERE condition over the emissions table with its arguments
+func (p *EmissionPredicate) SQL() (string, []interface{}) {
+	conds := []string{"1=1"}
+	var args []interface{}
+
+	if len(p.pollutants) > 0 {
+		conds = append(conds, "pollutant IN ("+placeholders(len(p.pollutants))+")")
+		for _, pollutant := range p.pollutants {
+			args = append(args, pollutant)
+		}
+	}
+	if p.startYear != nil {
+		conds = append(conds, "year >= ?")
+		args = append(args, *p.startYear)
+	}
+	if p.endYear != nil {
+		conds = append(conds, "year <= ?")
+		args = append(args, *p.endYear)
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// Match reports whether an emission satisfies the predicate
+func (p *EmissionPredicate) Match(e *models.Emission) bool {
+	if len(p.pollutants) > 0 && !contains(p.pollutants, e.Pollutant) {
+		return false
+	}
+	if p.startYear != nil && e.Year < *p.startYear {
+		return false
+	}
+	return p.endYear == nil || e.Year <= *p.endYear
+}
+
+// QuarterlyRollup rolls the monthly observations of one variable up into quarters. Each station's
+// quarter is the mean or sum of its three months, as models.VariableAggregation prescribes, and
+// quarters missing a month are left out; the stations are then averaged. Every backend applies
+// this same rule.
+func QuarterlyRollup(variable string, observations []models.EnvironmentObservation) []models.EnvironmentPoint {
+	type stationQuarter struct {
+		station       string
+		year, quarter int
+	}
+	type quarter struct{ year, quarter int }
+	type group struct {
+		sum float64
+		n   int
+	}
+
+	stations := make(map[stationQuarter]*group)
+	for _, o := range observations {
+		k := stationQuarter{o.Station, int(o.Year), (int(o.Month)-1)/monthsPerQuarter + 1}
+		g, ok := stations[k]
+		if !ok {
+			g = &group{}
+			stations[k] = g
+		}
+		g.sum += o.Value
+		g.n++
+	}
+
+	sum := models.VariableAggregation(variable) == models.AggregateSum
+	quarters := make(map[quarter]*group)
+	for k, g := range stations {
+		if g.n < monthsPerQuarter {
+			continue
+		}
+		value := g.sum
+		if !sum {
+			value /= float64(g.n)
+		}
+
+		q := quarter{k.year, k.quarter}
+		qg, ok := quarters[q]
+		if !ok {
+			qg = &group{}
+			quarters[q] = qg
+		}
+		qg.sum += value
+		qg.n++
+	}
+
+	points := make([]models.EnvironmentPoint, 0, len(quarters))
+	for q, g := range quarters {
+		points = append(points, models.EnvironmentPoint{
+			Year: q.year, Quarter: q.quarter, Factor: variable, Value: g.sum / float64(g.n),
+		})
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Year != points[j].Year {
+			return points[i].Year < points[j].Year
+		}
+		return points[i].Quarter < points[j].Quarter
+	})
+	return points
+}
+
+// MonthlyMeans averages the observations of one variable over the stations for each month
+func MonthlyMeans(variable string, observations []models.EnvironmentObservation) []models.EnvironmentPoint {
+	type month struct{ year, month int }
+	type group struct {
+		sum float64
+		n   int
+	}
+
+	months := make(map[month]*group)
+	for _, o := range observations {
+		k := month{int(o.Year), int(o.Month)}
+		g, ok := months[k]
+		if !ok {
+			g = &group{}
+			months[k] = g
+		}
+		g.sum += o.Value
+		g.n++
+	}
+
+	points := make([]models.EnvironmentPoint, 0, len(months))
+	for k, g := range months {
+		points = append(points, models.EnvironmentPoint{
+			Year:    k.year,
+			Quarter: (k.month-1)/monthsPerQuarter + 1,
+			Month:   k.month,
+			Factor:  variable,
+			Value:   g.sum / float64(g.n),
+		})
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Year != points[j].Year {
+			return points[i].Year < points[j].Year
+		}
+		return points[i].Month < points[j].Month
+	})
+	return points
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+func TestEnvironmentPredicateSQLAgreesWithMatch(t *testing.T) {
+	observations := []models.EnvironmentObservation{
+		{Station: "Chisinau", Variable: models.VariableAirTemperature, Year: 2020, Month: 1},
+		{Station: "Chisinau", Variable: models.VariablePrecipitation, Year: 2020, Month: 4},
+		{Station: "Balti", Variable: models.VariableAirTemperature, Year: 2021, Month: 6},
+		{Station: "Cahul", Variable: models.VariableWindSpeed, Year: 2021, Month: 12},
+	}
+	tests := []struct {
+		name   string
+		filter models.EnvironmentFilter
+		want   int
+	}{
+		{"everything", models.EnvironmentFilter{}, 4},
+		{"variables", models.EnvironmentFilter{Variables: []string{models.VariableAirTemperature}}, 2},
+		{"stations and years", models.EnvironmentFilter{Stations: []string{"Chisinau", "Cahul"}, StartYear: intPtr(2021)}, 1},
+		{"quarters select their months", models.EnvironmentFilter{Quarters: []int{2}}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := CompileEnvironmentFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("CompileEnvironmentFilter: %v", err)
+			}
+			where, args := p.SQL()
+
+			got := 0
+			for i := range observations {
+				o := &observations[i]
+				row := map[string]any{"station": o.Station, "variable": o.Variable, "year": o.Year, "month": o.Month}
+				match := p.Match(o)
+				if sql := evalWhere(t, where, args, row); sql != match {
+					t.Errorf("%+v: SQL %q selects it: %v, Match: %v", *o, where, sql, match)
+				}
+				if match {
+					got++
+				}
+			}
+			if got != tt.want {
+				t.Errorf("matched %d observations, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmissionPredicateSQLAgreesWithMatch(t *testing.T) {
+	emissions := []models.Emission{
+		{Pollutant: models.PollutantCarbonMonoxide, Year: 2014},
+		{Pollutant: models.PollutantNitrogenDioxide, Year: 2018},
+		{Pollutant: models.PollutantNitrogenDioxide, Year: 2023},
+	}
+	filters := []models.EmissionFilter{
+		{},
+		{Pollutants: []string{models.PollutantNitrogenDioxide}},
+		{StartYear: intPtr(2015), EndYear: intPtr(2020)},
+	}
+
+	for _, filter := range filters {
+		p, err := CompileEmissionFilter(filter)
+		if err != nil {
+			t.Fatalf("CompileEmissionFilter(%+v): %v", filter, err)
+		}
+		where, args := p.SQL()
+		for i := range emissions {
+			e := &emissions[i]
+			row := map[string]any{"pollutant": e.Pollutant, "year": e.Year}
+			if sql, match := evalWhere(t, where, args, row), p.Match(e); sql != match {
+				t.Errorf("%+v on %+v: SQL %q selects it: %v, Match: %v", filter, *e, where, sql, match)
+			}
+		}
+	}
+
+	if _, err := CompileEmissionFilter(models.EmissionFilter{Pollutants: []string{"ozone"}}); !errors.Is(err, models.ErrInvalidFilter) {
+		t.Errorf("unknown pollutant: error = %v, want ErrInvalidFilter", err)
+	}
+}
+
+func TestQuarterlyRollupKeepsCompleteQuarters(t *testing.T) {
+	var observations []models.EnvironmentObservation
+	for month := uint8(1); month <= 5; month++ {
+		observations = append(observations,
+			models.EnvironmentObservation{Station: "Chisinau", Variable: models.VariablePrecipitation, Year: 2020, Month: month, Value: 10},
+			models.EnvironmentObservation{Station: "Balti", Variable: models.VariablePrecipitation, Year: 2020, Month: month, Value: 20},
+		)
+	}
+
+	points := QuarterlyRollup(models.VariablePrecipitation, observations)
+	if len(points) != 1 {
+		t.Fatalf("got %d quarters, want only the complete first one: %+v", len(points), points)
+	}
+	// Each station's three months are summed, then the stations averaged
+	if p := points[0]; p.Year != 2020 || p.Quarter != 1 || p.Value != 45 {
+		t.Errorf("got %+v, want 2020 Q1 = 45", p)
+	}
+}
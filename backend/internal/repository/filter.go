@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// Predicate is a models.DiseaseFilter compiled into the row conditions every backend applies.
+// SQL and Match are kept side by side so both backends select exactly the same records.
+type Predicate struct {
+	startYear  *uint16
+	endYear    *uint16
+	quarters   []uint8
+	regions    []string
+	categories []string
+	names      []string
+	ageGroup   string
+	diseaseIDs []string
+	minCases   *uint32
+	maxCases   *uint32
+}
+
+// CompileFilter validates the row conditions of a filter and compiles them into a Predicate.
+// Sorting and pagination fields are ignored. Validation errors wrap models.ErrInvalidFilter.
+func CompileFilter(filter models.DiseaseFilter) (*Predicate, error) {
+	var p Predicate
+
+	if filter.StartYear != nil {
+		y, err := compileYear("startYear", *filter.StartYear)
+		if err != nil {
+			return nil, err
+		}
+		p.startYear = &y
+	}
+
+	if filter.EndYear != nil {
+		y, err := compileYear("endYear", *filter.EndYear)
+		if err != nil {
+			return nil, err
+		}
+		p.endYear = &y
+	}
+
+	if p.startYear != nil && p.endYear != nil && *p.startYear > *p.endYear {
+		return nil, fmt.Errorf("%w: startYear %d is after endYear %d", models.ErrInvalidFilter, *p.startYear, *p.endYear)
+	}
+
+	for _, q := range filter.Quarters {
+		if q < 1 || q > 4 {
+			return nil, fmt.Errorf("%w: quarter %d is not between 1 and 4", models.ErrInvalidFilter, q)
+		}
+		p.quarters = append(p.quarters, uint8(q))
+	}
+
+	p.regions = nonEmpty(filter.Regions)
+	p.categories = nonEmpty(filter.Categories)
+	p.names = nonEmpty(filter.Names)
+
+	switch p.ageGroup = strings.TrimSpace(filter.AgeGroup); p.ageGroup {
+	case "":
+		p.ageGroup = models.AgeGroupAll
+	case models.AgeGroupAll, models.AgeGroupChildren:
+	default:
+		return nil, fmt.Errorf("%w: ageGroup %q is not %q or %q", models.ErrInvalidFilter,
+			filter.AgeGroup, models.AgeGroupAll, models.AgeGroupChildren)
+	}
+	p.diseaseIDs = nonEmpty(filter.DiseaseIDs)
+
+	if filter.MinCases != nil {
+		v, err := compileCases("minCases", *filter.MinCases)
+		if err != nil {
+			return nil, err
+		}
+		p.minCases = &v
+	}
+
+	if filter.MaxCases != nil {
+		v, err := compileCases("maxCases", *filter.MaxCases)
+		if err != nil {
+			return nil, err
+		}
+		p.maxCases = &v
+	}
+
+	if p.minCases != nil && p.maxCases != nil && *p.minCases > *p.maxCases {
+		return nil, fmt.Errorf("%w: minCases %d exceeds maxCases %d", models.ErrInvalidFilter, *p.minCases, *p.maxCases)
+	}
+
+	return &p, nil
+}
+
+// SQL renders the predicate as a WHERE condition over the diseases table with its arguments
+func (p *Predicate) SQL() (string, []interface{}) {
+	conds := []string{"1=1"}
+	var args []interface{}
+
+	if p.startYear != nil {
+		conds = append(conds, "year >= ?")
+		args = append(args, *p.startYear)
+	}
+
+	if p.endYear != nil {
+		conds = append(conds, "year <= ?")
+		args = append(args, *p.endYear)
+	}
+
+	if len(p.quarters) > 0 {
+		conds = append(conds, "quarter IN ("+placeholders(len(p.quarters))+")")
+		for _, q := range p.quarters {
+			args = append(args, q)
+		}
+	}
+
+	if len(p.regions) > 0 {
+		conds = append(conds, "region IN ("+placeholders(len(p.regions))+")")
+		for _, r := range p.regions {
+			args = append(args, r)
+		}
+	}
+
+	if len(p.categories) > 0 {
+		conds = append(conds, "category IN ("+placeholders(len(p.categories))+")")
+		for _, c := range p.categories {
+			args = append(args, c)
+		}
+	}
+
+	if len(p.names) > 0 {
+		conds = append(conds, "name IN ("+placeholders(len(p.names))+")")
+		for _, n := range p.names {
+			args = append(args, n)
+		}
+	}
+
+	conds = append(conds, "age_group = ?")
+	args = append(args, p.ageGroup)
+
+	if len(p.diseaseIDs) > 0 {
+		ors := make([]string, 0, len(p.diseaseIDs))
+		for _, id := range p.diseaseIDs {
+			ors = append(ors, "(id = ? OR startsWith(id, ?))")
+			args = append(args, id, id+"_")
+		}
+		conds = append(conds, "("+strings.Join(ors, " OR ")+")")
+	}
+
+	if p.minCases != nil {
+		conds = append(conds, "cases >= ?")
+		args = append(args, *p.minCases)
+	}
+
+	if p.maxCases != nil {
+		conds = append(conds, "cases <= ?")
+		args = append(args, *p.maxCases)
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// Match reports whether a record satisfies the predicate
+func (p *Predicate) Match(d *models.Disease) bool {
+	if p.startYear != nil && d.Year < *p.startYear {
+		return false
+	}
+	if p.endYear != nil && d.Year > *p.endYear {
+		return false
+	}
+	if len(p.quarters) > 0 && !contains(p.quarters, d.Quarter) {
+		return false
+	}
+	if len(p.regions) > 0 && !contains(p.regions, d.Region) {
+		return false
+	}
+	if len(p.categories) > 0 && !contains(p.categories, d.Category) {
+		return false
+	}
+	if len(p.names) > 0 && !contains(p.names, d.Name) {
+		return false
+	}
+	if ageGroup(d) != p.ageGroup {
+		return false
+	}
+	if len(p.diseaseIDs) > 0 && !matchesDiseaseID(p.diseaseIDs, d.ID) {
+		return false
+	}
+	if p.minCases != nil && d.Cases < *p.minCases {
+		return false
+	}
+	if p.maxCases != nil && d.Cases > *p.maxCases {
+		return false
+	}
+	return true
+}
+
+// ageGroup returns the age group of a record, all ages when it has none
+func ageGroup(d *models.Disease) string {
+	if d.AgeGroup == "" {
+		return models.AgeGroupAll
+	}
+	return d.AgeGroup
+}
+
+// matchesDiseaseID reports whether a record ID belongs to one of the requested diseases.
+// A disease ID selects the record with exactly that ID and every record derived from it
+// with a "_<year>_<quarter>" style suffix.
+func matchesDiseaseID(ids []string, recordID string) bool {
+	for _, id := range ids {
+		if recordID == id || strings.HasPrefix(recordID, id+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+func compileYear(name string, year int) (uint16, error) {
+	if year < 0 || year > 65535 {
+		return 0, fmt.Errorf("%w: %s %d is out of range", models.ErrInvalidFilter, name, year)
+	}
+	return uint16(year), nil
+}
+
+// compileCases validates a case count bound against the UInt32 cases column
+func compileCases(name string, cases int) (uint32, error) {
+	if cases < 0 || int64(cases) > math.MaxUint32 {
+		return 0, fmt.Errorf("%w: %s %d is out of range", models.ErrInvalidFilter, name, cases)
+	}
+	return uint32(cases), nil
+}
+
+// nonEmpty drops blank values so that "regions=" does not filter everything out
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func contains[T comparable](values []T, v T) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholders returns n comma-separated query placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+func intPtr(v int) *int { return &v }
+
+// testDiseases covers every column a disease filter or sort reads, with children's records
+// alongside those of all ages
+var testDiseases = []models.Disease{
+	{ID: "flu_2019_1", Name: "Gripa", Category: "Respiratory Infections", Year: 2019, Quarter: 1, Region: "Moldova", Cases: 1200, Deaths: 3},
+	{ID: "flu_2019_2", Name: "Gripa", Category: "Respiratory Infections", Year: 2019, Quarter: 2, Region: "Moldova", Cases: 300},
+	{ID: "flu_2020_1", Name: "Gripa", Category: "Respiratory Infections", Year: 2020, Quarter: 1, Region: "Moldova", Cases: 900, Deaths: 1},
+	{ID: "flu_2020_1_children", Name: "Gripa", Category: "Respiratory Infections", Year: 2020, Quarter: 1, Region: "Moldova", AgeGroup: models.AgeGroupChildren, Cases: 400},
+	{ID: "hvb_2019_3", Name: "Hepatita B", Category: "Viral Hepatitis", Year: 2019, Quarter: 3, Region: "Moldova", AgeGroup: models.AgeGroupAll, Cases: 40},
+	{ID: "hvb_2021_4", Name: "Hepatita B", Category: "Viral Hepatitis", Year: 2021, Quarter: 4, Region: "Chisinau", Cases: 0},
+	{ID: "salm_2021_4", Name: "Salmoneloza", Category: "Intestinal Infections", Year: 2021, Quarter: 4, Region: "Chisinau", Cases: 75, Deaths: 0},
+	{ID: "hvbx_2021_4", Name: "Hepatita B cronica", Category: "Viral Hepatitis", Year: 2021, Quarter: 4, Region: "Moldova", Cases: 12},
+}
+
+// diseaseRow returns the columns of a record as the diseases table stores them
+func diseaseRow(d *models.Disease) map[string]any {
+	return map[string]any{
+		"id":              d.ID,
+		"name":            d.Name,
+		"category":        d.Category,
+		"region":          d.Region,
+		"age_group":       ageGroup(d),
+		"year":            d.Year,
+		"quarter":         d.Quarter,
+		"cases":           d.Cases,
+		"deaths":          d.Deaths,
+		"recoveries":      d.Recoveries,
+		"population":      d.Population,
+		"incidence_rate":  d.IncidenceRate,
+		"prevalence_rate": d.PrevalenceRate,
+		"mortality_rate":  d.MortalityRate,
+	}
+}
+
+func TestPredicateSQLAgreesWithMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter models.DiseaseFilter
+		want   []string
+	}{
+		{
+			name:   "defaults to all ages",
+			filter: models.DiseaseFilter{},
+			want:   []string{"flu_2019_1", "flu_2019_2", "flu_2020_1", "hvb_2019_3", "hvb_2021_4", "salm_2021_4", "hvbx_2021_4"},
+		},
+		{
+			name:   "children",
+			filter: models.DiseaseFilter{AgeGroup: models.AgeGroupChildren},
+			want:   []string{"flu_2020_1_children"},
+		},
+		{
+			name:   "years",
+			filter: models.DiseaseFilter{StartYear: intPtr(2020), EndYear: intPtr(2020)},
+			want:   []string{"flu_2020_1"},
+		},
+		{
+			name:   "quarters and regions",
+			filter: models.DiseaseFilter{Quarters: []int{3, 4}, Regions: []string{"Moldova"}},
+			want:   []string{"hvb_2019_3", "hvbx_2021_4"},
+		},
+		{
+			name:   "categories and names, ignoring blanks",
+			filter: models.DiseaseFilter{Categories: []string{"Viral Hepatitis", " "}, Names: []string{"Hepatita B"}},
+			want:   []string{"hvb_2019_3", "hvb_2021_4"},
+		},
+		{
+			name:   "disease IDs select derived records by prefix",
+			filter: models.DiseaseFilter{DiseaseIDs: []string{"hvb", "salm_2021_4"}},
+			want:   []string{"hvb_2019_3", "hvb_2021_4", "salm_2021_4"},
+		},
+		{
+			name:   "case bounds are inclusive",
+			filter: models.DiseaseFilter{MinCases: intPtr(40), MaxCases: intPtr(900)},
+			want:   []string{"flu_2019_2", "flu_2020_1", "hvb_2019_3", "salm_2021_4"},
+		},
+		{
+			name:   "zero maxCases",
+			filter: models.DiseaseFilter{MaxCases: intPtr(0)},
+			want:   []string{"hvb_2021_4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := CompileFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("CompileFilter: %v", err)
+			}
+			where, args := p.SQL()
+
+			var got []string
+			for i := range testDiseases {
+				d := &testDiseases[i]
+				match := p.Match(d)
+				if sql := evalWhere(t, where, args, diseaseRow(d)); sql != match {
+					t.Errorf("%s: SQL %q selects it: %v, Match: %v", d.ID, where, sql, match)
+				}
+				if match {
+					got = append(got, d.ID)
+				}
+			}
+			if !equalStrings(got, tt.want) {
+				t.Errorf("matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileFilterRejectsInvalidConditions(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter models.DiseaseFilter
+	}{
+		{"negative year", models.DiseaseFilter{StartYear: intPtr(-1)}},
+		{"year beyond UInt16", models.DiseaseFilter{EndYear: intPtr(70000)}},
+		{"start after end", models.DiseaseFilter{StartYear: intPtr(2021), EndYear: intPtr(2020)}},
+		{"quarter 0", models.DiseaseFilter{Quarters: []int{0}}},
+		{"quarter 5", models.DiseaseFilter{Quarters: []int{1, 5}}},
+		{"unknown age group", models.DiseaseFilter{AgeGroup: "adults"}},
+		{"negative minCases", models.DiseaseFilter{MinCases: intPtr(-1)}},
+		{"minCases beyond UInt32", models.DiseaseFilter{MinCases: intPtr(1 << 32)}},
+		{"maxCases beyond UInt32", models.DiseaseFilter{MaxCases: intPtr(1<<32 + 5)}},
+		{"minCases above maxCases", models.DiseaseFilter{MinCases: intPtr(10), MaxCases: intPtr(9)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompileFilter(tt.filter); !errors.Is(err, models.ErrInvalidFilter) {
+				t.Errorf("CompileFilter error = %v, want ErrInvalidFilter", err)
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// AlertRepository keeps disease alerts in process memory
+type AlertRepository struct {
+	mu     sync.RWMutex
+	alerts []models.Alert
+	nextID int
+}
+
+// NewAlertRepository creates an AlertRepository holding the given alerts
+func NewAlertRepository(alerts []models.Alert) *AlertRepository {
+	r := &AlertRepository{alerts: append([]models.Alert(nil), alerts...)}
+	for _, a := range alerts {
+		if a.ID > r.nextID {
+			r.nextID = a.ID
+		}
+	}
+	return r
+}
+
+// List retrieves all alerts, newest first
+func (r *AlertRepository) List(_ context.Context) ([]models.Alert, error) {
+	r.mu.RLock()
+	alerts := append([]models.Alert(nil), r.alerts...)
+	r.mu.RUnlock()
+
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].CreatedAt.After(alerts[j].CreatedAt) })
+	return alerts, nil
+}
+
+// Create adds a new alert
+func (r *AlertRepository) Create(_ context.Context, alert *models.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	alert.ID = r.nextID
+	alert.CreatedAt = time.Now().UTC()
+	r.alerts = append(r.alerts, *alert)
+
+	return nil
+}
+
+// Delete removes an alert
+func (r *AlertRepository) Delete(_ context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.alerts[:0]
+	for _, a := range r.alerts {
+		if a.ID != id {
+			kept = append(kept, a)
+		}
+	}
+	r.alerts = kept
+
+	return nil
+}
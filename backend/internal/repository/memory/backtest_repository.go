@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// backtestKey identifies the result a newer run replaces
+type backtestKey struct {
+	disease, model string
+	horizon        int
+	level          float64
+}
+
+// BacktestRepository keeps forecast backtest results in process memory
+type BacktestRepository struct {
+	mu      sync.RWMutex
+	results map[backtestKey]models.BacktestResult
+}
+
+// NewBacktestRepository creates an empty BacktestRepository
+func NewBacktestRepository() *BacktestRepository {
+	return &BacktestRepository{results: make(map[backtestKey]models.BacktestResult)}
+}
+
+// Save stores results, replacing earlier ones for the same disease, model, horizon and level
+func (r *BacktestRepository) Save(_ context.Context, results []models.BacktestResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, res := range results {
+		r.results[backtestKey{res.Disease, res.Model, res.Horizon, res.Level}] = res
+	}
+	return nil
+}
+
+// List returns the stored results, most recent run first
+func (r *BacktestRepository) List(_ context.Context, disease, model string) ([]models.BacktestResult, error) {
+	r.mu.RLock()
+	var results []models.BacktestResult
+	for _, res := range r.results {
+		if (disease == "" || res.Disease == disease) && (model == "" || res.Model == model) {
+			results = append(results, res)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if !a.RunAt.Equal(b.RunAt) {
+			return a.RunAt.After(b.RunAt)
+		}
+		if a.Disease != b.Disease {
+			return a.Disease < b.Disease
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Horizon < b.Horizon
+	})
+	return results, nil
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// CapacityRepository keeps bed profiles and bed counts in process memory
+type CapacityRepository struct {
+	mu       sync.RWMutex
+	profiles []models.BedProfile
+	beds     []models.HospitalBeds
+}
+
+// NewCapacityRepository creates a CapacityRepository holding the given profiles and bed counts
+func NewCapacityRepository(profiles []models.BedProfile, beds []models.HospitalBeds) *CapacityRepository {
+	r := &CapacityRepository{profiles: append([]models.BedProfile(nil), profiles...)}
+	sort.SliceStable(r.profiles, func(i, j int) bool { return r.profiles[i].Profile < r.profiles[j].Profile })
+	_ = r.Save(context.Background(), beds)
+	return r
+}
+
+// Profiles retrieves all bed profiles
+func (r *CapacityRepository) Profiles(_ context.Context) ([]models.BedProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]models.BedProfile(nil), r.profiles...), nil
+}
+
+// Beds retrieves the bed counts matching the filter
+func (r *CapacityRepository) Beds(_ context.Context, filter models.CapacityFilter) ([]models.HospitalBeds, error) {
+	pred, err := repository.CompileCapacityFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []models.HospitalBeds
+	for i := range r.beds {
+		if pred.Match(&r.beds[i]) {
+			matched = append(matched, r.beds[i])
+		}
+	}
+	return matched, nil
+}
+
+// Save stores bed counts, replacing those with the same profile and year; counts without a
+// status are observed
+func (r *CapacityRepository) Save(_ context.Context, beds []models.HospitalBeds) error {
+	type key struct {
+		profile string
+		year    uint16
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := make(map[key]int, len(r.beds))
+	for i, b := range r.beds {
+		index[key{b.Profile, b.Year}] = i
+	}
+	for _, b := range beds {
+		if b.Status == "" {
+			b.Status = models.StatusObserved
+		}
+		k := key{b.Profile, b.Year}
+		if i, ok := index[k]; ok {
+			r.beds[i] = b
+			continue
+		}
+		index[k] = len(r.beds)
+		r.beds = append(r.beds, b)
+	}
+
+	sort.SliceStable(r.beds, func(i, j int) bool {
+		a, b := r.beds[i], r.beds[j]
+		if a.Profile != b.Profile {
+			return a.Profile < b.Profile
+		}
+		return a.Year < b.Year
+	})
+	return nil
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// CategoryRepository keeps disease categories in process memory
+type CategoryRepository struct {
+	mu         sync.RWMutex
+	categories map[int]models.Category
+}
+
+// NewCategoryRepository creates a CategoryRepository holding the given categories
+func NewCategoryRepository(categories []models.Category) *CategoryRepository {
+	r := &CategoryRepository{categories: make(map[int]models.Category, len(categories))}
+	for _, c := range categories {
+		r.categories[c.ID] = c
+	}
+	return r
+}
+
+// List retrieves all disease categories
+func (r *CategoryRepository) List(_ context.Context) ([]models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	categories := make([]models.Category, 0, len(r.categories))
+	for _, c := range r.categories {
+		categories = append(categories, c)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].ID < categories[j].ID })
+
+	return categories, nil
+}
+
+// GetByID retrieves a specific category by ID
+func (r *CategoryRepository) GetByID(_ context.Context, id int) (*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.categories[id]
+	if !ok {
+		return nil, fmt.Errorf("category %d: %w", id, models.ErrNotFound)
+	}
+	return &c, nil
+}
+
+// Create adds a new category, allocating the next free ID when none is given
+func (r *CategoryRepository) Create(_ context.Context, category *models.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if category.ID == 0 {
+		for id := range r.categories {
+			if id > category.ID {
+				category.ID = id
+			}
+		}
+		category.ID++
+	}
+	r.categories[category.ID] = *category
+
+	return nil
+}
+
+// Update renames an existing category
+func (r *CategoryRepository) Update(_ context.Context, category *models.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.categories[category.ID]; ok {
+		r.categories[category.ID] = *category
+	}
+	return nil
+}
+
+// Delete removes a category
+func (r *CategoryRepository) Delete(_ context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.categories, id)
+	return nil
+}
@@ -0,0 +1,299 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// DiseaseRepository keeps disease records in process memory
+type DiseaseRepository struct {
+	mu      sync.RWMutex
+	records []models.Disease
+}
+
+// NewDiseaseRepository creates a DiseaseRepository holding the given records; records without a
+// status are taken as observed and records without an age group as counting all ages
+func NewDiseaseRepository(records []models.Disease) *DiseaseRepository {
+	r := &DiseaseRepository{records: append([]models.Disease(nil), records...)}
+	for i := range r.records {
+		if r.records[i].Status == "" {
+			r.records[i].Status = models.StatusObserved
+		}
+		if r.records[i].AgeGroup == "" {
+			r.records[i].AgeGroup = models.AgeGroupAll
+		}
+	}
+	return r
+}
+
+// List retrieves diseases based on filter criteria
+func (r *DiseaseRepository) List(_ context.Context, filter models.DiseaseFilter) ([]models.Disease, error) {
+	spec, err := repository.SortSpec(filter)
+	if err != nil {
+		return nil, err
+	}
+	ordering, err := repository.CompileSort(spec)
+	if err != nil {
+		return nil, err
+	}
+	keyset, err := repository.CompileCursor(filter, ordering)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	matched, err := r.matching(filter)
+	r.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if keyset != nil {
+		after := matched[:0]
+		for i := range matched {
+			if keyset.After(&matched[i]) {
+				after = append(after, matched[i])
+			}
+		}
+		matched = after
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool { return ordering.Less(&matched[i], &matched[j]) })
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(matched) {
+			return nil, nil
+		}
+		matched = matched[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+
+	return matched, nil
+}
+
+// GetByID retrieves a specific disease by ID
+func (r *DiseaseRepository) GetByID(_ context.Context, id string) (*models.Disease, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := range r.records {
+		if r.records[i].ID == id {
+			d := r.records[i]
+			return &d, nil
+		}
+	}
+
+	return nil, fmt.Errorf("disease %q: %w", id, models.ErrNotFound)
+}
+
+// Create adds a new disease record
+func (r *DiseaseRepository) Create(_ context.Context, disease *models.Disease) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = append(r.records, *disease)
+	return nil
+}
+
+// Update updates an existing disease record
+func (r *DiseaseRepository) Update(_ context.Context, disease *models.Disease) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Like an ALTER TABLE UPDATE mutation, every record with the ID is rewritten and
+	// the time coordinates are left untouched
+	for i := range r.records {
+		d := &r.records[i]
+		if d.ID != disease.ID {
+			continue
+		}
+		d.Name = disease.Name
+		d.Category = disease.Category
+		d.Region = disease.Region
+		d.Cases = disease.Cases
+		d.Deaths = disease.Deaths
+		d.Recoveries = disease.Recoveries
+		d.Population = disease.Population
+		d.IncidenceRate = disease.IncidenceRate
+		d.PrevalenceRate = disease.PrevalenceRate
+		d.MortalityRate = disease.MortalityRate
+		d.Status = disease.Status
+	}
+
+	return nil
+}
+
+// Delete removes a disease record
+func (r *DiseaseRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.records[:0]
+	for _, d := range r.records {
+		if d.ID != id {
+			kept = append(kept, d)
+		}
+	}
+	r.records = kept
+
+	return nil
+}
+
+// Count returns the number of records matching the filter
+func (r *DiseaseRepository) Count(_ context.Context, filter models.DiseaseFilter) (int, error) {
+	r.mu.RLock()
+	matched, err := r.matching(filter)
+	r.mu.RUnlock()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(matched), nil
+}
+
+// Totals sums cases, deaths and recoveries over the matching records and counts the suppressed ones
+func (r *DiseaseRepository) Totals(_ context.Context, filter models.DiseaseFilter) (*repository.Totals, error) {
+	r.mu.RLock()
+	matched, err := r.matching(filter)
+	r.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var t repository.Totals
+	var incidenceSum float64
+	reported := 0
+	for _, d := range matched {
+		if d.Status.Suppressed() {
+			t.Suppressed.Add(d.Status)
+			continue
+		}
+		t.Cases += uint64(d.Cases)
+		t.Deaths += uint64(d.Deaths)
+		t.Recoveries += uint64(d.Recoveries)
+		incidenceSum += d.IncidenceRate
+		reported++
+	}
+	t.MatchedRecords = uint64(len(matched))
+	if reported > 0 {
+		t.AvgIncidence = incidenceSum / float64(reported)
+	}
+
+	return &t, nil
+}
+
+// TimeSeries retrieves per-quarter aggregates for each disease
+func (r *DiseaseRepository) TimeSeries(_ context.Context, filter models.DiseaseFilter) ([]models.DiseaseTimePoint, error) {
+	r.mu.RLock()
+	matched, err := r.matching(filter)
+	r.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		year    uint16
+		quarter uint8
+		name    string
+	}
+	type group struct {
+		cases, recoveries          uint64
+		incidenceSum, mortalitySum float64
+		n                          int
+		suppressed                 models.SuppressedCells
+	}
+
+	groups := make(map[key]*group)
+	for _, d := range matched {
+		k := key{d.Year, d.Quarter, d.Name}
+		g, ok := groups[k]
+		if !ok {
+			g = &group{}
+			groups[k] = g
+		}
+		if d.Status.Suppressed() {
+			g.suppressed.Add(d.Status)
+			continue
+		}
+		g.cases += uint64(d.Cases)
+		g.recoveries += uint64(d.Recoveries)
+		g.incidenceSum += d.IncidenceRate
+		g.mortalitySum += d.MortalityRate
+		g.n++
+	}
+
+	points := make([]models.DiseaseTimePoint, 0, len(groups))
+	for k, g := range groups {
+		p := models.DiseaseTimePoint{
+			Year:       int(k.year),
+			Quarter:    int(k.quarter),
+			Name:       k.name,
+			Cases:      g.cases,
+			Status:     models.AggregateStatus(g.n, g.cases, g.suppressed),
+			Suppressed: g.suppressed,
+		}
+		if g.n > 0 {
+			p.IncidenceRate = g.incidenceSum / float64(g.n)
+			p.MortalityRate = g.mortalitySum / float64(g.n)
+		}
+		if g.cases > 0 {
+			p.RecoveryRate = float64(g.recoveries) / float64(g.cases) * 100
+		}
+		points = append(points, p)
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		a, b := points[i], points[j]
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Quarter != b.Quarter {
+			return a.Quarter < b.Quarter
+		}
+		return a.Name < b.Name
+	})
+
+	return points, nil
+}
+
+// Rebase sets the population of the reported records of a year, region and age group and
+// recomputes their rates
+func (r *DiseaseRepository) Rebase(_ context.Context, year int, region, ageGroup string, population uint32) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := 0
+	for i := range r.records {
+		d := &r.records[i]
+		if int(d.Year) != year || d.Region != region || d.AgeGroup != ageGroup || d.Status.Suppressed() {
+			continue
+		}
+		repository.Rebase(d, population)
+		changed++
+	}
+
+	return changed, nil
+}
+
+// matching returns copies of the records accepted by the filter; callers must hold the lock
+func (r *DiseaseRepository) matching(filter models.DiseaseFilter) ([]models.Disease, error) {
+	pred, err := repository.CompileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []models.Disease
+	for i := range r.records {
+		if pred.Match(&r.records[i]) {
+			matched = append(matched, r.records[i])
+		}
+	}
+	return matched, nil
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// TestTotalsIgnoreChildrenImport checks that importing the children's records, which repeat cases
+// the records of all ages count, leaves the totals and time series of a default filter unchanged
+func TestTotalsIgnoreChildrenImport(t *testing.T) {
+	ctx := context.Background()
+	repo := NewDiseaseRepository([]models.Disease{
+		{ID: "flu_2020_1", Name: "Gripa", Category: "Respiratory Infections", Region: models.NationalRegion, Year: 2020, Quarter: 1, Cases: 900, Deaths: 2, IncidenceRate: 34},
+		{ID: "flu_2020_2", Name: "Gripa", Category: "Respiratory Infections", Region: models.NationalRegion, Year: 2020, Quarter: 2, Cases: 300, IncidenceRate: 11},
+		{ID: "salm_2020_1", Name: "Salmoneloza", Category: "Intestinal Infections", Region: models.NationalRegion, Year: 2020, Quarter: 1, Cases: 75},
+	})
+
+	filter := models.DiseaseFilter{}
+	totals, err := repo.Totals(ctx, filter)
+	if err != nil {
+		t.Fatalf("Totals: %v", err)
+	}
+	series, err := repo.TimeSeries(ctx, filter)
+	if err != nil {
+		t.Fatalf("TimeSeries: %v", err)
+	}
+
+	for _, d := range []models.Disease{
+		{ID: "flu_2020_1_children", Name: "Gripa", Category: "Respiratory Infections", Region: models.NationalRegion, AgeGroup: models.AgeGroupChildren, Year: 2020, Quarter: 1, Cases: 400, IncidenceRate: 61},
+		{ID: "flu_2020_2_children", Name: "Gripa", Category: "Respiratory Infections", Region: models.NationalRegion, AgeGroup: models.AgeGroupChildren, Year: 2020, Quarter: 2, Cases: 120, IncidenceRate: 18},
+	} {
+		d := d
+		if err := repo.Create(ctx, &d); err != nil {
+			t.Fatalf("Create %s: %v", d.ID, err)
+		}
+	}
+
+	after, err := repo.Totals(ctx, filter)
+	if err != nil {
+		t.Fatalf("Totals: %v", err)
+	}
+	if *after != *totals {
+		t.Errorf("totals after the children's import = %+v, want %+v", *after, *totals)
+	}
+	seriesAfter, err := repo.TimeSeries(ctx, filter)
+	if err != nil {
+		t.Fatalf("TimeSeries: %v", err)
+	}
+	if !reflect.DeepEqual(seriesAfter, series) {
+		t.Errorf("time series after the children's import = %+v, want %+v", seriesAfter, series)
+	}
+
+	children, err := repo.Totals(ctx, models.DiseaseFilter{AgeGroup: models.AgeGroupChildren})
+	if err != nil {
+		t.Fatalf("Totals: %v", err)
+	}
+	if children.Cases != 520 {
+		t.Errorf("children's cases = %d, want 520", children.Cases)
+	}
+}
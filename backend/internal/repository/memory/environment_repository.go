@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// EnvironmentRepository keeps monthly environment observations and yearly emissions in process
+// memory
+type EnvironmentRepository struct {
+	observations []models.EnvironmentObservation
+	emissions    []models.Emission
+}
+
+// NewEnvironmentRepository creates an EnvironmentRepository holding the given observations and
+// emissions
+func NewEnvironmentRepository(observations []models.EnvironmentObservation, emissions []models.Emission) *EnvironmentRepository {
+	return &EnvironmentRepository{
+		observations: append([]models.EnvironmentObservation(nil), observations...),
+		emissions:    append([]models.Emission(nil), emissions...),
+	}
+}
+
+// Factors lists the observed variables
+func (r *EnvironmentRepository) Factors(_ context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	var factors []string
+	for _, o := range r.observations {
+		if !seen[o.Variable] {
+			seen[o.Variable] = true
+			factors = append(factors, o.Variable)
+		}
+	}
+	sort.Strings(factors)
+	return factors, nil
+}
+
+// Quarterly rolls the observations of a variable up into quarters
+func (r *EnvironmentRepository) Quarterly(_ context.Context, factor string, startYear, endYear *int) ([]models.EnvironmentPoint, error) {
+	return repository.QuarterlyRollup(factor, r.matching(factor, startYear, endYear)), nil
+}
+
+// Monthly averages the observations of a variable over the stations per month
+func (r *EnvironmentRepository) Monthly(_ context.Context, factor string, startYear, endYear *int) ([]models.EnvironmentPoint, error) {
+	return repository.MonthlyMeans(factor, r.matching(factor, startYear, endYear)), nil
+}
+
+func (r *EnvironmentRepository) matching(variable string, startYear, endYear *int) []models.EnvironmentObservation {
+	var matched []models.EnvironmentObservation
+	for _, o := range r.observations {
+		if o.Variable != variable {
+			continue
+		}
+		if startYear != nil && int(o.Year) < *startYear {
+			continue
+		}
+		if endYear != nil && int(o.Year) > *endYear {
+			continue
+		}
+		matched = append(matched, o)
+	}
+	return matched
+}
+
+// Observations returns the observations matching the filter
+func (r *EnvironmentRepository) Observations(_ context.Context, filter models.EnvironmentFilter) ([]models.EnvironmentObservation, error) {
+	pred, err := repository.CompileEnvironmentFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []models.EnvironmentObservation
+	for i := range r.observations {
+		if pred.Match(&r.observations[i]) {
+			matched = append(matched, r.observations[i])
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if a.Station != b.Station {
+			return a.Station < b.Station
+		}
+		if a.Variable != b.Variable {
+			return a.Variable < b.Variable
+		}
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		return a.Month < b.Month
+	})
+
+	return matched, nil
+}
+
+// Stations summarises the observations per station
+func (r *EnvironmentRepository) Stations(_ context.Context) ([]models.Station, error) {
+	byName := make(map[string]*models.Station)
+	variables := make(map[string]map[string]bool)
+	for _, o := range r.observations {
+		st, ok := byName[o.Station]
+		if !ok {
+			st = &models.Station{Name: o.Station, FirstYear: int(o.Year), LastYear: int(o.Year)}
+			byName[o.Station] = st
+			variables[o.Station] = make(map[string]bool)
+		}
+		if !variables[o.Station][o.Variable] {
+			variables[o.Station][o.Variable] = true
+			st.Variables = append(st.Variables, o.Variable)
+		}
+		if int(o.Year) < st.FirstYear {
+			st.FirstYear = int(o.Year)
+		}
+		if int(o.Year) > st.LastYear {
+			st.LastYear = int(o.Year)
+		}
+	}
+
+	stations := make([]models.Station, 0, len(byName))
+	for _, st := range byName {
+		sort.Strings(st.Variables)
+		stations = append(stations, *st)
+	}
+	sort.Slice(stations, func(i, j int) bool { return stations[i].Name < stations[j].Name })
+
+	return stations, nil
+}
+
+// Emissions returns the emissions matching the filter
+func (r *EnvironmentRepository) Emissions(_ context.Context, filter models.EmissionFilter) ([]models.Emission, error) {
+	pred, err := repository.CompileEmissionFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []models.Emission
+	for i := range r.emissions {
+		if pred.Match(&r.emissions[i]) {
+			matched = append(matched, r.emissions[i])
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].Pollutant != matched[j].Pollutant {
+			return matched[i].Pollutant < matched[j].Pollutant
+		}
+		return matched[i].Year < matched[j].Year
+	})
+
+	return matched, nil
+}
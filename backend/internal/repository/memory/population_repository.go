@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// PopulationRepository keeps population entries in process memory
+type PopulationRepository struct {
+	mu      sync.RWMutex
+	entries []models.PopulationEntry
+}
+
+// NewPopulationRepository creates a PopulationRepository holding the given entries
+func NewPopulationRepository(entries []models.PopulationEntry) *PopulationRepository {
+	r := &PopulationRepository{}
+	_ = r.Save(context.Background(), entries)
+	return r
+}
+
+// List retrieves the entries matching the filter
+func (r *PopulationRepository) List(_ context.Context, filter models.PopulationFilter) ([]models.PopulationEntry, error) {
+	pred, err := repository.CompilePopulationFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []models.PopulationEntry
+	for i := range r.entries {
+		if pred.Match(&r.entries[i]) {
+			matched = append(matched, r.entries[i])
+		}
+	}
+	return matched, nil
+}
+
+// Save stores entries, replacing those with the same year, age, medium and sex
+func (r *PopulationRepository) Save(_ context.Context, entries []models.PopulationEntry) error {
+	type key struct {
+		year             uint16
+		age, medium, sex string
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := make(map[key]int, len(r.entries))
+	for i, e := range r.entries {
+		index[key{e.Year, e.Age, e.Medium, e.Sex}] = i
+	}
+	for _, e := range entries {
+		k := key{e.Year, e.Age, e.Medium, e.Sex}
+		if i, ok := index[k]; ok {
+			r.entries[i] = e
+			continue
+		}
+		index[k] = len(r.entries)
+		r.entries = append(r.entries, e)
+	}
+
+	sort.SliceStable(r.entries, func(i, j int) bool {
+		a, b := r.entries[i], r.entries[j]
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Medium != b.Medium {
+			return a.Medium < b.Medium
+		}
+		if a.Sex != b.Sex {
+			return a.Sex < b.Sex
+		}
+		return a.Age < b.Age
+	})
+	return nil
+}
@@ -0,0 +1,73 @@
+// Package memory implements the repository contracts in process memory, so the API can run
+// without a database.
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// Seed is the JSON document an in-memory store can be preloaded from
+type Seed struct {
+	Categories  []models.Category               `json:"categories"`
+	Diseases    []models.Disease                `json:"diseases"`
+	Alerts      []models.Alert                  `json:"alerts"`
+	Environment []models.EnvironmentObservation `json:"environment"`
+	Emissions   []models.Emission               `json:"emissions"`
+	Population  []models.PopulationEntry        `json:"population"`
+	Stratified  []models.StratifiedFact         `json:"stratified"`
+	Vaccines    []models.Vaccine                `json:"vaccines"`
+	Vaccination []models.VaccinationCoverage    `json:"vaccination"`
+	BedProfiles []models.BedProfile             `json:"bedProfiles"`
+	Beds        []models.HospitalBeds           `json:"beds"`
+}
+
+// NewStore creates an in-memory store from a seed. Categories, vaccines and bed profiles default
+// to repository.DefaultCategories, repository.DefaultVaccines and repository.DefaultBedProfiles
+// when the seed has none.
+func NewStore(seed Seed) *repository.Store {
+	if len(seed.Categories) == 0 {
+		seed.Categories = repository.DefaultCategories
+	}
+	if len(seed.Vaccines) == 0 {
+		seed.Vaccines = repository.DefaultVaccines
+	}
+	if len(seed.BedProfiles) == 0 {
+		seed.BedProfiles = repository.DefaultBedProfiles
+	}
+
+	return &repository.Store{
+		Diseases:    NewDiseaseRepository(seed.Diseases),
+		Categories:  NewCategoryRepository(seed.Categories),
+		Alerts:      NewAlertRepository(seed.Alerts),
+		Backtests:   NewBacktestRepository(),
+		Environment: NewEnvironmentRepository(seed.Environment, seed.Emissions),
+		Population:  NewPopulationRepository(seed.Population),
+		Stratified:  NewStratifiedRepository(seed.Stratified),
+		Vaccination: NewVaccinationRepository(seed.Vaccines, seed.Vaccination),
+		Capacity:    NewCapacityRepository(seed.BedProfiles, seed.Beds),
+	}
+}
+
+// LoadStore creates an in-memory store, preloading it from a JSON seed file when a path is given
+func LoadStore(path string) (*repository.Store, error) {
+	var seed Seed
+	if path == "" {
+		return NewStore(seed), nil
+	}
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading seed file: %w", err)
+	}
+
+	if err := json.Unmarshal(bytes, &seed); err != nil {
+		return nil, fmt.Errorf("error parsing seed file: %w", err)
+	}
+
+	return NewStore(seed), nil
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// StratifiedRepository keeps stratified facts in process memory
+type StratifiedRepository struct {
+	mu    sync.RWMutex
+	facts []models.StratifiedFact
+}
+
+// NewStratifiedRepository creates a StratifiedRepository holding the given facts
+func NewStratifiedRepository(facts []models.StratifiedFact) *StratifiedRepository {
+	r := &StratifiedRepository{}
+	_ = r.Save(context.Background(), facts)
+	return r
+}
+
+// Facts retrieves the facts matching the filter
+func (r *StratifiedRepository) Facts(_ context.Context, filter models.StratifiedFilter) ([]models.StratifiedFact, error) {
+	pred, err := repository.CompileStratifiedFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []models.StratifiedFact
+	for i := range r.facts {
+		if pred.Match(&r.facts[i]) {
+			matched = append(matched, r.facts[i])
+		}
+	}
+	return matched, nil
+}
+
+// Save stores facts, replacing those with the same indicator, year, age, sex and medium; facts
+// without a status are observed
+func (r *StratifiedRepository) Save(_ context.Context, facts []models.StratifiedFact) error {
+	type key struct {
+		indicator        string
+		year             uint16
+		age, sex, medium string
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := make(map[key]int, len(r.facts))
+	for i, f := range r.facts {
+		index[key{f.Indicator, f.Year, f.Age, f.Sex, f.Medium}] = i
+	}
+	for _, f := range facts {
+		if f.Status == "" {
+			f.Status = models.StatusObserved
+		}
+		k := key{f.Indicator, f.Year, f.Age, f.Sex, f.Medium}
+		if i, ok := index[k]; ok {
+			r.facts[i] = f
+			continue
+		}
+		index[k] = len(r.facts)
+		r.facts = append(r.facts, f)
+	}
+	return nil
+}
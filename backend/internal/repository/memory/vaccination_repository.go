@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// VaccinationRepository keeps vaccines and coverage figures in process memory
+type VaccinationRepository struct {
+	mu       sync.RWMutex
+	vaccines []models.Vaccine
+	coverage []models.VaccinationCoverage
+}
+
+// NewVaccinationRepository creates a VaccinationRepository holding the given vaccines and coverage
+func NewVaccinationRepository(vaccines []models.Vaccine, coverage []models.VaccinationCoverage) *VaccinationRepository {
+	r := &VaccinationRepository{vaccines: append([]models.Vaccine(nil), vaccines...)}
+	sort.SliceStable(r.vaccines, func(i, j int) bool { return r.vaccines[i].Antigen < r.vaccines[j].Antigen })
+	_ = r.Save(context.Background(), coverage)
+	return r
+}
+
+// Vaccines retrieves all vaccines
+func (r *VaccinationRepository) Vaccines(_ context.Context) ([]models.Vaccine, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]models.Vaccine(nil), r.vaccines...), nil
+}
+
+// Coverage retrieves the coverage figures matching the filter
+func (r *VaccinationRepository) Coverage(_ context.Context, filter models.VaccinationFilter) ([]models.VaccinationCoverage, error) {
+	pred, err := repository.CompileVaccinationFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []models.VaccinationCoverage
+	for i := range r.coverage {
+		if pred.Match(&r.coverage[i]) {
+			matched = append(matched, r.coverage[i])
+		}
+	}
+	return matched, nil
+}
+
+// Save stores coverage figures, replacing those with the same antigen, age and year; figures
+// without a status are observed
+func (r *VaccinationRepository) Save(_ context.Context, coverage []models.VaccinationCoverage) error {
+	type key struct {
+		antigen, age string
+		year         uint16
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := make(map[key]int, len(r.coverage))
+	for i, c := range r.coverage {
+		index[key{c.Antigen, c.Age, c.Year}] = i
+	}
+	for _, c := range coverage {
+		if c.Status == "" {
+			c.Status = models.StatusObserved
+		}
+		k := key{c.Antigen, c.Age, c.Year}
+		if i, ok := index[k]; ok {
+			r.coverage[i] = c
+			continue
+		}
+		index[k] = len(r.coverage)
+		r.coverage = append(r.coverage, c)
+	}
+
+	sort.SliceStable(r.coverage, func(i, j int) bool {
+		a, b := r.coverage[i], r.coverage[j]
+		if a.Antigen != b.Antigen {
+			return a.Antigen < b.Antigen
+		}
+		if a.Age != b.Age {
+			return a.Age < b.Age
+		}
+		return a.Year < b.Year
+	})
+	return nil
+}
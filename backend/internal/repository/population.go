@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// RatePer is the population base of incidence, prevalence and mortality rates
+const RatePer = 100000
+
+// PopulationPredicate is a models.PopulationFilter compiled into the row conditions every backend
+// applies to population entries
+type PopulationPredicate struct {
+	startYear *uint16
+	endYear   *uint16
+	ages      []string
+	media     []string
+	sexes     []string
+}
+
+// CompilePopulationFilter validates a population filter and compiles it into a predicate.
+// Validation errors wrap models.ErrInvalidFilter.
+func CompilePopulationFilter(filter models.PopulationFilter) (*PopulationPredicate, error) {
+	// The years are validated exactly like those of a disease filter
+	if _, err := CompileFilter(models.DiseaseFilter{StartYear: filter.StartYear, EndYear: filter.EndYear}); err != nil {
+		return nil, err
+	}
+
+	p := PopulationPredicate{
+		ages:  nonEmpty(filter.Ages),
+		media: nonEmpty(filter.Media),
+		sexes: nonEmpty(filter.Sexes),
+	}
+	if filter.StartYear != nil {
+		y := uint16(*filter.StartYear)
+		p.startYear = &y
+	}
+	if filter.EndYear != nil {
+		y := uint16(*filter.EndYear)
+		p.endYear = &y
+	}
+
+	for _, m := range p.media {
+		if m != models.MediumUrban && m != models.MediumRural {
+			return nil, fmt.Errorf("%w: media: %q is not %q or %q", models.ErrInvalidFilter, m, models.MediumUrban, models.MediumRural)
+		}
+	}
+	for _, s := range p.sexes {
+		if s != models.SexMale && s != models.SexFemale {
+			return nil, fmt.Errorf("%w: sexes: %q is not %q or %q", models.ErrInvalidFilter, s, models.SexMale, models.SexFemale)
+		}
+	}
+
+	return &p, nil
+}
+
+// SQL renders the predicate as a WHERE condition over the population table with its arguments
+func (p *PopulationPredicate) SQL() (string, []interface{}) {
+	conds := []string{"1=1"}
+	var args []interface{}
+
+	if p.startYear != nil {
+		conds = append(conds, "year >= ?")
+		args = append(args, *p.startYear)
+	}
+	if p.endYear != nil {
+		conds = append(conds, "year <= ?")
+		args = append(args, *p.endYear)
+	}
+
+	for _, in := range []struct {
+		column string
+		values []string
+	}{{"age", p.ages}, {"medium", p.media}, {"sex", p.sexes}} {
+		if len(in.values) == 0 {
+			continue
+		}
+		conds = append(conds, in.column+" IN ("+placeholders(len(in.values))+")")
+		for _, v := range in.values {
+			args = append(args, v)
+		}
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// Match reports whether an entry satisfies the predicate
+func (p *PopulationPredicate) Match(e *models.PopulationEntry) bool {
+	return p.matchStrata(e.Year, e.Age, e.Medium, e.Sex)
+}
+
+// matchStrata reports whether a year and stratum satisfy the predicate
+func (p *PopulationPredicate) matchStrata(year uint16, age, medium, sex string) bool {
+	if p.startYear != nil && year < *p.startYear {
+		return false
+	}
+	if p.endYear != nil && year > *p.endYear {
+		return false
+	}
+	if len(p.ages) > 0 && !contains(p.ages, age) {
+		return false
+	}
+	if len(p.media) > 0 && !contains(p.media, medium) {
+		return false
+	}
+	if len(p.sexes) > 0 && !contains(p.sexes, sex) {
+		return false
+	}
+	return true
+}
+
+// PopulationTotals sums the entries per year over media and sexes, using the AgeTotal entries
+// where a year has them and the single ages otherwise
+func PopulationTotals(entries []models.PopulationEntry) map[int]uint64 {
+	totals := make(map[int]uint64)
+	byAge := make(map[int]uint64)
+	for _, e := range entries {
+		if e.Age == models.AgeTotal {
+			totals[int(e.Year)] += uint64(e.Population)
+		} else {
+			byAge[int(e.Year)] += uint64(e.Population)
+		}
+	}
+
+	for year, total := range byAge {
+		if _, ok := totals[year]; !ok {
+			totals[year] = total
+		}
+	}
+	return totals
+}
+
+// ChildPopulationTotals sums the single ages under models.ChildAgeLimit per year over media and
+// sexes, the population the rates of the children's records are computed against
+func ChildPopulationTotals(entries []models.PopulationEntry) map[int]uint64 {
+	totals := make(map[int]uint64)
+	for _, e := range entries {
+		if age, err := strconv.Atoi(e.Age); err == nil && age < models.ChildAgeLimit {
+			totals[int(e.Year)] += uint64(e.Population)
+		}
+	}
+	return totals
+}
+
+// Denominators turns January 1 totals into the mid-year populations rates are computed against.
+// The mid-year population of a year is the mean of its January 1 figure and that of the next
+// year; without the next figure the year's own January 1 figure stands in for it.
+func Denominators(totals map[int]uint64) []models.PopulationDenominator {
+	years := make([]int, 0, len(totals))
+	for year := range totals {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+
+	denominators := make([]models.PopulationDenominator, 0, len(years))
+	for _, year := range years {
+		d := models.PopulationDenominator{Year: year, January1: totals[year], MidYear: float64(totals[year])}
+		if next, ok := totals[year+1]; ok {
+			d.MidYear = (float64(totals[year]) + float64(next)) / 2
+			d.Interpolated = true
+		}
+		denominators = append(denominators, d)
+	}
+	return denominators
+}
+
+// Rebase sets the population of a record and recomputes its rates per 100,000 against it.
+// Incidence and mortality follow from the cases and deaths where they are recorded; prevalence,
+// whose numerator is not stored, and rates without recorded counts are rescaled from the previous
+// population, or kept as they are when the record had none. Suppressed records are left alone.
+func Rebase(d *models.Disease, population uint32) {
+	if d.Status.Suppressed() || population == 0 {
+		return
+	}
+
+	rebase := func(rate float64, count uint32) float64 {
+		switch {
+		case count > 0:
+			return float64(count) * RatePer / float64(population)
+		case d.Population > 0:
+			return rate * float64(d.Population) / float64(population)
+		default:
+			return rate
+		}
+	}
+
+	d.IncidenceRate = rebase(d.IncidenceRate, d.Cases)
+	d.MortalityRate = rebase(d.MortalityRate, d.Deaths)
+	d.PrevalenceRate = rebase(d.PrevalenceRate, 0)
+	d.Population = population
+}
+
+// RoundPopulation rounds a mid-year population to the whole number records store
+func RoundPopulation(p float64) uint32 {
+	return uint32(math.Round(p))
+}
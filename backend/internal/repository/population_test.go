@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+var testPopulation = []models.PopulationEntry{
+	{Year: 2020, Age: "0", Medium: models.MediumUrban, Sex: models.SexMale, Population: 100},
+	{Year: 2020, Age: "0", Medium: models.MediumRural, Sex: models.SexFemale, Population: 90},
+	{Year: 2020, Age: "85+", Medium: models.MediumRural, Sex: models.SexFemale, Population: 10},
+	{Year: 2021, Age: "17", Medium: models.MediumUrban, Sex: models.SexFemale, Population: 80},
+	{Year: 2021, Age: models.AgeTotal, Medium: models.MediumUrban, Sex: models.SexFemale, Population: 500},
+}
+
+func populationRow(e *models.PopulationEntry) map[string]any {
+	return map[string]any{"year": e.Year, "age": e.Age, "medium": e.Medium, "sex": e.Sex}
+}
+
+func TestPopulationPredicateSQLAgreesWithMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter models.PopulationFilter
+		want   int
+	}{
+		{"everything", models.PopulationFilter{}, 5},
+		{"years", models.PopulationFilter{StartYear: intPtr(2021)}, 2},
+		{"ages", models.PopulationFilter{Ages: []string{"0", "85+"}}, 3},
+		{"media and sexes", models.PopulationFilter{Media: []string{models.MediumUrban}, Sexes: []string{models.SexFemale}}, 2},
+		{"every dimension", models.PopulationFilter{StartYear: intPtr(2020), EndYear: intPtr(2020), Ages: []string{"0"}, Sexes: []string{models.SexMale}}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := CompilePopulationFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("CompilePopulationFilter: %v", err)
+			}
+			where, args := p.SQL()
+
+			got := 0
+			for i := range testPopulation {
+				e := &testPopulation[i]
+				match := p.Match(e)
+				if sql := evalWhere(t, where, args, populationRow(e)); sql != match {
+					t.Errorf("%+v: SQL %q selects it: %v, Match: %v", *e, where, sql, match)
+				}
+				if match {
+					got++
+				}
+			}
+			if got != tt.want {
+				t.Errorf("matched %d entries, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompilePopulationFilterRejectsUnknownStrata(t *testing.T) {
+	for _, filter := range []models.PopulationFilter{
+		{Media: []string{"suburban"}},
+		{Sexes: []string{"m"}},
+		{StartYear: intPtr(2022), EndYear: intPtr(2021)},
+	} {
+		if _, err := CompilePopulationFilter(filter); !errors.Is(err, models.ErrInvalidFilter) {
+			t.Errorf("CompilePopulationFilter(%+v) error = %v, want ErrInvalidFilter", filter, err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Package repository defines the storage-agnostic data access contracts used by the services.
+package repository
+
+import (
+	"context"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// DiseaseRepository provides access to disease records
+type DiseaseRepository interface {
+	// List returns the records matching the filter, honouring its sort, limit and offset or cursor.
+	// A zero limit returns every matching record.
+	List(ctx context.Context, filter models.DiseaseFilter) ([]models.Disease, error)
+	// GetByID returns a single record or models.ErrNotFound.
+	GetByID(ctx context.Context, id string) (*models.Disease, error)
+	// Create inserts a new record.
+	Create(ctx context.Context, disease *models.Disease) error
+	// Update overwrites the mutable fields of an existing record.
+	Update(ctx context.Context, disease *models.Disease) error
+	// Delete removes a record.
+	Delete(ctx context.Context, id string) error
+	// Count returns the number of records matching the filter, ignoring limit, offset and cursor.
+	Count(ctx context.Context, filter models.DiseaseFilter) (int, error)
+	// Totals aggregates the records matching the filter; suppressed records are counted, not summed.
+	Totals(ctx context.Context, filter models.DiseaseFilter) (*Totals, error)
+	// TimeSeries aggregates the records matching the filter per year, quarter and disease name,
+	// ordered by year, quarter and name. Suppressed records are counted, not summed.
+	TimeSeries(ctx context.Context, filter models.DiseaseFilter) ([]models.DiseaseTimePoint, error)
+	// Rebase sets the population of the reported records of a year, region and age group and
+	// recomputes their rates against it, as Rebase does for a single record. It returns the
+	// number of records changed.
+	Rebase(ctx context.Context, year int, region, ageGroup string, population uint32) (int, error)
+}
+
+// CategoryRepository provides access to disease categories
+type CategoryRepository interface {
+	// List returns all categories ordered by ID.
+	List(ctx context.Context) ([]models.Category, error)
+	// GetByID returns a single category or models.ErrNotFound.
+	GetByID(ctx context.Context, id int) (*models.Category, error)
+	// Create inserts a new category, assigning its ID when it is zero.
+	Create(ctx context.Context, category *models.Category) error
+	// Update renames an existing category.
+	Update(ctx context.Context, category *models.Category) error
+	// Delete removes a category.
+	Delete(ctx context.Context, id int) error
+}
+
+// AlertRepository provides access to disease alerts
+type AlertRepository interface {
+	// List returns all alerts, newest first.
+	List(ctx context.Context) ([]models.Alert, error)
+	// Create inserts a new alert, assigning its ID and creation time.
+	Create(ctx context.Context, alert *models.Alert) error
+	// Delete removes an alert.
+	Delete(ctx context.Context, id int) error
+}
+
+// BacktestRepository stores forecast backtest results
+type BacktestRepository interface {
+	// Save stores results, replacing earlier ones for the same disease, model, horizon and level.
+	Save(ctx context.Context, results []models.BacktestResult) error
+	// List returns the stored results, most recent run first. Empty arguments match everything.
+	List(ctx context.Context, disease, model string) ([]models.BacktestResult, error)
+}
+
+// EnvironmentRepository provides access to environment observations, where each observed
+// variable is a factor, and to the yearly emissions of pollutants
+type EnvironmentRepository interface {
+	// Factors returns the names of the observed variables in alphabetical order.
+	Factors(ctx context.Context) ([]string, error)
+	// Quarterly returns the quarterly rollup (see QuarterlyRollup) of a variable within the given
+	// years, ordered by year and quarter. Nil bounds are open.
+	Quarterly(ctx context.Context, factor string, startYear, endYear *int) ([]models.EnvironmentPoint, error)
+	// Monthly returns the station mean of a variable per month within the given years, ordered by
+	// year and month.
+	Monthly(ctx context.Context, factor string, startYear, endYear *int) ([]models.EnvironmentPoint, error)
+	// Observations returns the monthly observations matching the filter, ordered by station,
+	// variable, year and month.
+	Observations(ctx context.Context, filter models.EnvironmentFilter) ([]models.EnvironmentObservation, error)
+	// Stations returns the stations that have observations, ordered by name.
+	Stations(ctx context.Context) ([]models.Station, error)
+	// Emissions returns the yearly road-transport emissions matching the filter, ordered by
+	// pollutant and year.
+	Emissions(ctx context.Context, filter models.EmissionFilter) ([]models.Emission, error)
+}
+
+// PopulationRepository provides access to the January 1 population by age, medium and sex
+type PopulationRepository interface {
+	// List returns the entries matching the filter, ordered by year, medium, sex and age.
+	List(ctx context.Context, filter models.PopulationFilter) ([]models.PopulationEntry, error)
+	// Save stores entries, replacing those with the same year, age, medium and sex.
+	Save(ctx context.Context, entries []models.PopulationEntry) error
+}
+
+// StratifiedRepository provides access to indicators broken down by age, sex and medium
+type StratifiedRepository interface {
+	// Facts returns the facts matching the filter's indicator, years and strata; grouping and
+	// banding are left to Stratify.
+	Facts(ctx context.Context, filter models.StratifiedFilter) ([]models.StratifiedFact, error)
+	// Save stores facts, replacing those with the same indicator, year, age, sex and medium.
+	Save(ctx context.Context, facts []models.StratifiedFact) error
+}
+
+// VaccinationRepository provides access to vaccination coverage and the diseases each vaccine
+// protects against
+type VaccinationRepository interface {
+	// Vaccines returns the vaccines ordered by antigen.
+	Vaccines(ctx context.Context) ([]models.Vaccine, error)
+	// Coverage returns the coverage figures matching the filter, ordered by antigen, age and year.
+	Coverage(ctx context.Context, filter models.VaccinationFilter) ([]models.VaccinationCoverage, error)
+	// Save stores coverage figures, replacing those with the same antigen, age and year.
+	Save(ctx context.Context, coverage []models.VaccinationCoverage) error
+}
+
+// CapacityRepository provides access to hospital bed counts and the disease categories each bed
+// profile treats
+type CapacityRepository interface {
+	// Profiles returns the bed profiles ordered by profile.
+	Profiles(ctx context.Context) ([]models.BedProfile, error)
+	// Beds returns the bed counts matching the filter, ordered by profile and year.
+	Beds(ctx context.Context, filter models.CapacityFilter) ([]models.HospitalBeds, error)
+	// Save stores bed counts, replacing those with the same profile and year.
+	Save(ctx context.Context, beds []models.HospitalBeds) error
+}
+
+// Totals holds the aggregates computed over a set of disease records
+type Totals struct {
+	Cases          uint64
+	Deaths         uint64
+	Recoveries     uint64
+	AvgIncidence   float64 // over the reported records
+	MatchedRecords uint64
+	Suppressed     models.SuppressedCells
+}
+
+// Store bundles the repositories of a single storage backend
+type Store struct {
+	Diseases    DiseaseRepository
+	Categories  CategoryRepository
+	Alerts      AlertRepository
+	Backtests   BacktestRepository
+	Environment EnvironmentRepository
+	Population  PopulationRepository
+	Stratified  StratifiedRepository
+	Vaccination VaccinationRepository
+	Capacity    CapacityRepository
+}
+
+// DefaultCategories is the category set every fresh store starts with
+var DefaultCategories = []models.Category{
+	{ID: 1, Name: "Respiratory Infections"},
+	{ID: 2, Name: "Viral Hepatitis"},
+	{ID: 3, Name: "STIs"},
+	{ID: 4, Name: "COVID-19"},
+	{ID: 5, Name: "Intestinal Infections"},
+	{ID: 6, Name: "Other Infectious Diseases"},
+}
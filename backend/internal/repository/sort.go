@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// sortField describes how a sortable field of models.Disease is ordered in each backend.
+// value returns a string, uint64 or float64 so that fields can be compared and encoded in cursors.
+type sortField struct {
+	sql   string
+	value func(d *models.Disease) any
+}
+
+// sortFields whitelists the sortable fields by their JSON names. Besides the stored columns it
+// includes derived ratios, which treat records without cases as zero.
+var sortFields = map[string]sortField{
+	"id":                {"id", func(d *models.Disease) any { return d.ID }},
+	"name":              {"name", func(d *models.Disease) any { return d.Name }},
+	"category":          {"category", func(d *models.Disease) any { return d.Category }},
+	"region":            {"region", func(d *models.Disease) any { return d.Region }},
+	"year":              {"year", func(d *models.Disease) any { return uint64(d.Year) }},
+	"quarter":           {"quarter", func(d *models.Disease) any { return uint64(d.Quarter) }},
+	"cases":             {"cases", func(d *models.Disease) any { return uint64(d.Cases) }},
+	"deaths":            {"deaths", func(d *models.Disease) any { return uint64(d.Deaths) }},
+	"recoveries":        {"recoveries", func(d *models.Disease) any { return uint64(d.Recoveries) }},
+	"population":        {"population", func(d *models.Disease) any { return uint64(d.Population) }},
+	"incidenceRate":     {"incidence_rate", func(d *models.Disease) any { return d.IncidenceRate }},
+	"prevalenceRate":    {"prevalence_rate", func(d *models.Disease) any { return d.PrevalenceRate }},
+	"mortalityRate":     {"mortality_rate", func(d *models.Disease) any { return d.MortalityRate }},
+	"deathsPerCase":     {"if(cases > 0, deaths / cases, 0)", func(d *models.Disease) any { return perCase(d.Deaths, d.Cases) }},
+	"recoveriesPerCase": {"if(cases > 0, recoveries / cases, 0)", func(d *models.Disease) any { return perCase(d.Recoveries, d.Cases) }},
+}
+
+// sortAliases accepts the ClickHouse column names previously passed through sortBy
+var sortAliases = map[string]string{
+	"incidence_rate":  "incidenceRate",
+	"prevalence_rate": "prevalenceRate",
+	"mortality_rate":  "mortalityRate",
+}
+
+// OrderingKey is the ordering key of the ClickHouse diseases table
+var OrderingKey = []string{"year", "quarter", "category", "name", "region"}
+
+// SortKey is a single validated key of a sort spec
+type SortKey struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
+// Ordering is a validated multi-key sort. It always ends with the record ID so that
+// every backend produces the same total order.
+type Ordering []SortKey
+
+// CompileSort validates a sort spec such as "-cases,name,year", where a leading "-" sorts
+// descending and a leading "+" or none ascending. An empty spec sorts by the table ordering key.
+// Errors wrap models.ErrInvalidFilter and list the allowed fields.
+func CompileSort(spec string) (Ordering, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = strings.Join(OrderingKey, ",")
+	}
+
+	ordering, err := parseSort(spec, SortableFields(), sortAliases)
+	if err != nil {
+		return nil, err
+	}
+	if !sortsBy(ordering, "id") {
+		ordering = append(ordering, SortKey{Field: "id"})
+	}
+
+	return ordering, nil
+}
+
+// parseSort splits a sort spec into its keys, resolving aliases. Fields not among those allowed or
+// given more than once produce an error wrapping models.ErrInvalidFilter.
+func parseSort(spec string, allowed []string, aliases map[string]string) ([]SortKey, error) {
+	var keys []SortKey
+	seen := make(map[string]bool)
+
+	for _, raw := range strings.Split(spec, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		key := SortKey{Field: raw}
+		switch raw[0] {
+		case '-':
+			key.Desc = true
+			key.Field = raw[1:]
+		case '+':
+			key.Field = raw[1:]
+		}
+		if alias, ok := aliases[key.Field]; ok {
+			key.Field = alias
+		}
+
+		if !contains(allowed, key.Field) {
+			return nil, fmt.Errorf("%w: unknown sort field %q; allowed fields: %s",
+				models.ErrInvalidFilter, key.Field, strings.Join(allowed, ", "))
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("%w: sort field %q given more than once", models.ErrInvalidFilter, key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// sortsBy reports whether the keys include a field
+func sortsBy(keys []SortKey, field string) bool {
+	for _, k := range keys {
+		if k.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// SortSpec combines the sort, sortBy and sortOrder fields of a filter into a single sort spec.
+// The sort field takes precedence; sortBy with sortOrder is kept for compatibility.
+func SortSpec(filter models.DiseaseFilter) (string, error) {
+	return sortSpec(filter.Sort, filter.SortBy, filter.SortOrder)
+}
+
+// sortSpec combines a sort spec with a sortBy field and its sortOrder, the spec taking precedence
+func sortSpec(spec, sortBy, sortOrder string) (string, error) {
+	if spec != "" {
+		return spec, nil
+	}
+	if sortBy == "" {
+		return "", nil
+	}
+
+	switch strings.ToLower(sortOrder) {
+	case "", "asc":
+		return sortBy, nil
+	case "desc":
+		return "-" + sortBy, nil
+	default:
+		return "", fmt.Errorf("%w: sortOrder must be asc or desc", models.ErrInvalidFilter)
+	}
+}
+
+// SortableFields returns the names accepted in a sort spec
+func SortableFields() []string {
+	fields := make([]string, 0, len(sortFields))
+	for f := range sortFields {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// SQL renders the ordering as an ORDER BY clause
+func (o Ordering) SQL() string {
+	parts := make([]string, 0, len(o))
+	for _, k := range o {
+		part := sortFields[k.Field].sql
+		if k.Desc {
+			part += " DESC"
+		}
+		parts = append(parts, part)
+	}
+	return "ORDER BY " + strings.Join(parts, ", ")
+}
+
+// String renders the ordering back into its canonical sort spec
+func (o Ordering) String() string {
+	parts := make([]string, 0, len(o))
+	for _, k := range o {
+		if k.Desc {
+			parts = append(parts, "-"+k.Field)
+		} else {
+			parts = append(parts, k.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Less reports whether a sorts before b
+func (o Ordering) Less(a, b *models.Disease) bool {
+	for _, k := range o {
+		f := sortFields[k.Field]
+		if c := compareValues(f.value(a), f.value(b)); c != 0 {
+			return (c < 0) != k.Desc
+		}
+	}
+	return false
+}
+
+func perCase(count, cases uint32) float64 {
+	if cases == 0 {
+		return 0
+	}
+	return float64(count) / float64(cases)
+}
+
+// compareValues compares two sort values of the same field
+func compareValues(a, b any) int {
+	switch av := a.(type) {
+	case string:
+		return strings.Compare(av, b.(string))
+	case uint64:
+		return compareNumbers(av, b.(uint64))
+	case float64:
+		return compareNumbers(av, b.(float64))
+	}
+	return 0
+}
+
+func compareNumbers[T uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
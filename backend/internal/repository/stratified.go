@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// StratifiedPredicate is a models.StratifiedFilter compiled into the row conditions every backend
+// applies to stratified facts
+type StratifiedPredicate struct {
+	indicator string
+	strata    *PopulationPredicate
+}
+
+// CompileStratifiedFilter validates a stratified filter and compiles it into a predicate. The
+// years and strata are validated like those of a population filter; validation errors wrap
+// models.ErrInvalidFilter.
+func CompileStratifiedFilter(filter models.StratifiedFilter) (*StratifiedPredicate, error) {
+	strata, err := CompilePopulationFilter(models.PopulationFilter{
+		StartYear: filter.StartYear,
+		EndYear:   filter.EndYear,
+		Ages:      filter.Ages,
+		Media:     filter.Media,
+		Sexes:     filter.Sexes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dim := range filter.GroupBy {
+		if dim != models.DimensionAge && dim != models.DimensionSex && dim != models.DimensionMedium {
+			return nil, fmt.Errorf("%w: groupBy: %q is not %q, %q or %q", models.ErrInvalidFilter,
+				dim, models.DimensionAge, models.DimensionSex, models.DimensionMedium)
+		}
+	}
+	if filter.BandWidth < 0 {
+		return nil, fmt.Errorf("%w: bandWidth must not be negative", models.ErrInvalidFilter)
+	}
+	if filter.OpenAge < 0 {
+		return nil, fmt.Errorf("%w: openAge must not be negative", models.ErrInvalidFilter)
+	}
+
+	return &StratifiedPredicate{indicator: filter.Indicator, strata: strata}, nil
+}
+
+// SQL renders the predicate as a WHERE condition over the stratified_facts table with its arguments
+func (p *StratifiedPredicate) SQL() (string, []interface{}) {
+	where, args := p.strata.SQL()
+	if p.indicator == "" {
+		return where, args
+	}
+	return "indicator = ? AND " + where, append([]interface{}{p.indicator}, args...)
+}
+
+// Match reports whether a fact satisfies the predicate
+func (p *StratifiedPredicate) Match(f *models.StratifiedFact) bool {
+	if p.indicator != "" && f.Indicator != p.indicator {
+		return false
+	}
+	return p.strata.matchStrata(f.Year, f.Age, f.Medium, f.Sex)
+}
+
+// PopulationFacts turns population entries into facts of the population indicator
+func PopulationFacts(entries []models.PopulationEntry) []models.StratifiedFact {
+	facts := make([]models.StratifiedFact, 0, len(entries))
+	for _, e := range entries {
+		facts = append(facts, models.StratifiedFact{
+			Indicator: models.IndicatorPopulation,
+			Year:      e.Year,
+			Age:       e.Age,
+			Sex:       e.Sex,
+			Medium:    e.Medium,
+			Value:     float64(e.Population),
+			Status:    models.StatusObserved,
+		})
+	}
+	return facts
+}
+
+// AgeRange returns the first and last year of an age such as "7", "18-29" or "85+", last being -1
+// for open groups. It reports false for AgeTotal, AgeUnknown and other ages that are not ranges.
+func AgeRange(age string) (first, last int, ok bool) {
+	if strings.HasSuffix(age, "+") {
+		first, err := strconv.Atoi(strings.TrimSuffix(age, "+"))
+		return first, -1, err == nil
+	}
+	if from, to, found := strings.Cut(age, "-"); found {
+		first, err1 := strconv.Atoi(from)
+		last, err2 := strconv.Atoi(to)
+		return first, last, err1 == nil && err2 == nil && first <= last
+	}
+	first, err := strconv.Atoi(age)
+	return first, first, err == nil
+}
+
+// AgeBand returns the band of width years holding an age, or the open band from openAge. Ages that
+// are not ranges or straddle two bands, such as "18-29" in five-year bands, are returned as they
+// are, as is every age when width is not positive.
+func AgeBand(age string, width, openAge int) string {
+	first, last, ok := AgeRange(age)
+	if width <= 0 || !ok {
+		return age
+	}
+	if first >= openAge {
+		return strconv.Itoa(openAge) + "+"
+	}
+
+	bandFirst := first / width * width
+	bandLast := bandFirst + width - 1
+	if bandLast >= openAge {
+		bandLast = openAge - 1
+	}
+	if last < 0 || last > bandLast {
+		return age
+	}
+	if bandFirst == bandLast {
+		return strconv.Itoa(bandFirst)
+	}
+	return strconv.Itoa(bandFirst) + "-" + strconv.Itoa(bandLast)
+}
+
+// AgeLess orders ages youngest first, with ages that are not ranges after them and AgeTotal last
+func AgeLess(a, b string) bool {
+	rank := func(age string) (int, int, int) {
+		if first, last, ok := AgeRange(age); ok {
+			if last < 0 {
+				last = first
+			}
+			return 0, first, last
+		}
+		if age == models.AgeTotal {
+			return 2, 0, 0
+		}
+		return 1, 0, 0
+	}
+
+	ra, fa, la := rank(a)
+	rb, fb, lb := rank(b)
+	switch {
+	case ra != rb:
+		return ra < rb
+	case fa != fb:
+		return fa < fb
+	case la != lb:
+		return la < lb
+	default:
+		return a < b
+	}
+}
+
+// Stratify sums facts into yearly series per stratum of the grouped dimensions, banding ages as
+// AgeBand does. When ages are summed over, the AgeTotal fact of a year, sex and medium is used where
+// it reports a value and the facts of single ages otherwise; when ages are kept apart the AgeTotal
+// facts are left out. Suppressed facts are counted rather than summed.
+func Stratify(facts []models.StratifiedFact, groupBy []string, bandWidth, openAge int) []models.StratifiedSeries {
+	byAge := contains(groupBy, models.DimensionAge)
+	bySex := contains(groupBy, models.DimensionSex)
+	byMedium := contains(groupBy, models.DimensionMedium)
+	if openAge <= 0 {
+		openAge = models.DefaultOpenAge
+	}
+
+	type cell struct {
+		indicator   string
+		year        uint16
+		sex, medium string
+	}
+	hasTotal := make(map[cell]bool)
+	for _, f := range facts {
+		if f.Age == models.AgeTotal && !f.Status.Suppressed() {
+			hasTotal[cell{f.Indicator, f.Year, f.Sex, f.Medium}] = true
+		}
+	}
+
+	type stratum struct {
+		indicator, age, sex, medium string
+	}
+	type point struct {
+		value      float64
+		reported   int
+		suppressed models.SuppressedCells
+	}
+	points := make(map[stratum]map[int]*point)
+	for _, f := range facts {
+		total := f.Age == models.AgeTotal
+		if byAge && total || !byAge && total != hasTotal[cell{f.Indicator, f.Year, f.Sex, f.Medium}] {
+			continue
+		}
+
+		s := stratum{indicator: f.Indicator}
+		if byAge {
+			s.age = AgeBand(f.Age, bandWidth, openAge)
+		}
+		if bySex {
+			s.sex = f.Sex
+		}
+		if byMedium {
+			s.medium = f.Medium
+		}
+
+		if points[s] == nil {
+			points[s] = make(map[int]*point)
+		}
+		p := points[s][int(f.Year)]
+		if p == nil {
+			p = &point{}
+			points[s][int(f.Year)] = p
+		}
+		if f.Status.Suppressed() {
+			p.suppressed.Add(f.Status)
+			continue
+		}
+		p.value += f.Value
+		p.reported++
+	}
+
+	strata := make([]stratum, 0, len(points))
+	for s := range points {
+		strata = append(strata, s)
+	}
+	sort.Slice(strata, func(i, j int) bool {
+		a, b := strata[i], strata[j]
+		switch {
+		case a.indicator != b.indicator:
+			return a.indicator < b.indicator
+		case a.age != b.age:
+			return AgeLess(a.age, b.age)
+		case a.sex != b.sex:
+			return a.sex < b.sex
+		default:
+			return a.medium < b.medium
+		}
+	})
+
+	series := make([]models.StratifiedSeries, 0, len(strata))
+	for _, s := range strata {
+		years := make([]int, 0, len(points[s]))
+		for year := range points[s] {
+			years = append(years, year)
+		}
+		sort.Ints(years)
+
+		ss := models.StratifiedSeries{Indicator: s.indicator, Age: s.age, Sex: s.sex, Medium: s.medium}
+		for _, year := range years {
+			p := points[s][year]
+			ss.Points = append(ss.Points, models.StratifiedPoint{
+				Year:       year,
+				Value:      p.value,
+				Status:     models.AggregateStatus(p.reported, uint64(p.value+0.5), p.suppressed),
+				Suppressed: p.suppressed,
+			})
+		}
+		series = append(series, ss)
+	}
+	return series
+}
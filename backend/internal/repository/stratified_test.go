@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+func TestStratifiedPredicateSQLAgreesWithMatch(t *testing.T) {
+	facts := []models.StratifiedFact{
+		{Indicator: models.IndicatorDisability, Year: 2020, Age: "18-29", Sex: models.SexMale, Medium: models.MediumUrban},
+		{Indicator: models.IndicatorDisability, Year: 2021, Age: "50+", Sex: models.SexFemale, Medium: models.MediumRural},
+		{Indicator: models.IndicatorPopulation, Year: 2021, Age: "50+", Sex: models.SexFemale, Medium: models.MediumRural},
+	}
+	filters := []models.StratifiedFilter{
+		{},
+		{Indicator: models.IndicatorDisability},
+		{Indicator: models.IndicatorDisability, StartYear: intPtr(2021), Ages: []string{"50+"}},
+		{Indicator: models.IndicatorPopulation, Sexes: []string{models.SexMale}},
+	}
+
+	for _, filter := range filters {
+		p, err := CompileStratifiedFilter(filter)
+		if err != nil {
+			t.Fatalf("CompileStratifiedFilter(%+v): %v", filter, err)
+		}
+		where, args := p.SQL()
+		for i := range facts {
+			f := &facts[i]
+			row := map[string]any{"indicator": f.Indicator, "year": f.Year, "age": f.Age, "sex": f.Sex, "medium": f.Medium}
+			if sql, match := evalWhere(t, where, args, row), p.Match(f); sql != match {
+				t.Errorf("%+v on %+v: SQL %q selects it: %v, Match: %v", filter, *f, where, sql, match)
+			}
+		}
+	}
+}
+
+// deaths returns a deaths fact in the urban medium
+func deaths(year uint16, age, sex string, value float64, status models.ValueStatus) models.StratifiedFact {
+	return models.StratifiedFact{Indicator: models.IndicatorDeaths, Year: year, Age: age, Sex: sex, Medium: models.MediumUrban, Value: value, Status: status}
+}
+
+// seriesValues flattens series into "age/sex year" keys and their values
+func seriesValues(series []models.StratifiedSeries) map[string]models.StratifiedPoint {
+	values := make(map[string]models.StratifiedPoint)
+	for _, s := range series {
+		for _, p := range s.Points {
+			values[s.Age+"/"+s.Sex+" "+strconv.Itoa(p.Year)] = p
+		}
+	}
+	return values
+}
+
+func TestStratifySumsTotalsOrAges(t *testing.T) {
+	observed := models.StatusObserved
+	facts := []models.StratifiedFact{
+		// The total reports more than its single ages, which the source does not list exhaustively
+		deaths(2020, models.AgeTotal, models.SexMale, 100, observed),
+		deaths(2020, "0", models.SexMale, 30, observed),
+		deaths(2020, "1", models.SexMale, 40, observed),
+		// Without a total the single ages are summed
+		deaths(2020, "0", models.SexFemale, 20, observed),
+		deaths(2020, "1", models.SexFemale, 25, observed),
+		// A suppressed total is replaced by its single ages, of which a suppressed one is counted
+		deaths(2021, models.AgeTotal, models.SexMale, 0, models.StatusConfidential),
+		deaths(2021, "0", models.SexMale, 10, observed),
+		deaths(2021, "1", models.SexMale, 15, observed),
+		deaths(2021, "2", models.SexMale, 0, models.StatusNotAvailable),
+	}
+
+	tests := []struct {
+		name    string
+		groupBy []string
+		want    map[string]float64
+	}{
+		{"over everything", nil, map[string]float64{"/ 2020": 145, "/ 2021": 25}},
+		{"by sex", []string{models.DimensionSex}, map[string]float64{"/male 2020": 100, "/female 2020": 45, "/male 2021": 25}},
+		{"by age, leaving totals out", []string{models.DimensionAge}, map[string]float64{"0/ 2020": 50, "1/ 2020": 65, "0/ 2021": 10, "1/ 2021": 15, "2/ 2021": 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := seriesValues(Stratify(facts, tt.groupBy, 0, 0))
+			if len(got) != len(tt.want) {
+				t.Errorf("got points %v, want %v", got, tt.want)
+			}
+			for key, want := range tt.want {
+				if p, ok := got[key]; !ok || p.Value != want {
+					t.Errorf("%s = %+v, want %g", key, p, want)
+				}
+			}
+		})
+	}
+
+	p := seriesValues(Stratify(facts, nil, 0, 0))["/ 2021"]
+	if p.Suppressed != (models.SuppressedCells{NotAvailable: 1}) || p.Status != models.StatusObserved {
+		t.Errorf("2021 = %+v, want observed with the one age not available and the replaced total not counted", p)
+	}
+	p = seriesValues(Stratify(facts, []string{models.DimensionAge}, 0, 0))["2/ 2021"]
+	if p.Status != models.StatusNotAvailable {
+		t.Errorf("age 2 in 2021 = %+v, want not available", p)
+	}
+}
+
+func TestStratifyBandsAges(t *testing.T) {
+	var facts []models.StratifiedFact
+	for _, age := range []string{"0", "3", "7", "18-29", "25", "84", "85", "90", "95+", models.AgeTotal} {
+		facts = append(facts, deaths(2020, age, models.SexMale, 1, models.StatusObserved))
+	}
+
+	tests := []struct {
+		name           string
+		width, openAge int
+		want           map[string]float64
+	}{
+		// Groups straddling two bands are kept as they are
+		{"five-year bands", 5, 0, map[string]float64{"0-4/ 2020": 2, "5-9/ 2020": 1, "18-29/ 2020": 1, "25-29/ 2020": 1, "80-84/ 2020": 1, "85+/ 2020": 3}},
+		{"open band from 80", 5, 80, map[string]float64{"0-4/ 2020": 2, "5-9/ 2020": 1, "18-29/ 2020": 1, "25-29/ 2020": 1, "80+/ 2020": 4}},
+		{"bands cut short by the open age", 20, 30, map[string]float64{"0-19/ 2020": 3, "18-29/ 2020": 1, "20-29/ 2020": 1, "30+/ 2020": 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := seriesValues(Stratify(facts, []string{models.DimensionAge}, tt.width, tt.openAge))
+			if len(got) != len(tt.want) {
+				t.Errorf("got points %v, want %v", got, tt.want)
+			}
+			for key, want := range tt.want {
+				if p, ok := got[key]; !ok || p.Value != want {
+					t.Errorf("%s = %+v, want %g", key, p, want)
+				}
+			}
+		})
+	}
+}
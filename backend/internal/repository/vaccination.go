@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// VaccinationPredicate is a models.VaccinationFilter compiled into the row conditions every
+// backend applies to coverage figures
+type VaccinationPredicate struct {
+	startYear *uint16
+	endYear   *uint16
+	antigens  []string
+}
+
+// CompileVaccinationFilter validates a vaccination filter and compiles it into a predicate.
+// Validation errors wrap models.ErrInvalidFilter.
+func CompileVaccinationFilter(filter models.VaccinationFilter) (*VaccinationPredicate, error) {
+	// The years are validated exactly like those of a disease filter
+	if _, err := CompileFilter(models.DiseaseFilter{StartYear: filter.StartYear, EndYear: filter.EndYear}); err != nil {
+		return nil, err
+	}
+
+	p := VaccinationPredicate{antigens: nonEmpty(filter.Antigens)}
+	if filter.StartYear != nil {
+		y := uint16(*filter.StartYear)
+		p.startYear = &y
+	}
+	if filter.EndYear != nil {
+		y := uint16(*filter.EndYear)
+		p.endYear = &y
+	}
+	return &p, nil
+}
+
+// SQL renders the predicate as a WHERE condition over the vaccination_coverage table with its
+// arguments
+func (p *VaccinationPredicate) SQL() (string, []interface{}) {
+	conds := []string{"1=1"}
+	var args []interface{}
+
+	if p.startYear != nil {
+		conds = append(conds, "year >= ?")
+		args = append(args, *p.startYear)
+	}
+	if p.endYear != nil {
+		conds = append(conds, "year <= ?")
+		args = append(args, *p.endYear)
+	}
+	if len(p.antigens) > 0 {
+		conds = append(conds, "antigen IN ("+placeholders(len(p.antigens))+")")
+		for _, a := range p.antigens {
+			args = append(args, a)
+		}
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// Match reports whether a coverage figure satisfies the predicate
+func (p *VaccinationPredicate) Match(c *models.VaccinationCoverage) bool {
+	if p.startYear != nil && c.Year < *p.startYear {
+		return false
+	}
+	if p.endYear != nil && c.Year > *p.endYear {
+		return false
+	}
+	return len(p.antigens) == 0 || contains(p.antigens, c.Antigen)
+}
+
+// DefaultVaccines links the antigens of the Statbank coverage figures to the infectious diseases
+// they protect against. Diphtheria, tetanus, poliomyelitis and rubella are not among the reported
+// infectious diseases, so their vaccines have none.
+var DefaultVaccines = []models.Vaccine{
+	{Antigen: "Difterie si tetanos", Name: "Diphtheria and tetanus", Diseases: []string{}},
+	{Antigen: "Hepatita B", Name: "Hepatitis B", Diseases: []string{"Hepatite virala B (HVB) acuta"}},
+	{Antigen: "Parotidita epidemica", Name: "Mumps", Diseases: []string{"Oreionul (Parotidita epidemica)"}},
+	{Antigen: "Poliomielita", Name: "Poliomyelitis", Diseases: []string{}},
+	{Antigen: "Rubeola", Name: "Rubella", Diseases: []string{}},
+	{Antigen: "Rujeola", Name: "Measles", Diseases: []string{"Rujeola"}},
+	{Antigen: "Tuberculoza", Name: "Tuberculosis (BCG)", Diseases: []string{"Tuberculoza organelor respiratorii"}},
+	{Antigen: "Tusa convulsiva", Name: "Pertussis", Diseases: []string{"Tusea convulsiva"}},
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+func TestVaccinationPredicateSQLAgreesWithMatch(t *testing.T) {
+	coverage := []models.VaccinationCoverage{
+		{Antigen: "Hepatita B", Year: 2015},
+		{Antigen: "Rujeola", Year: 2018},
+		{Antigen: "Rujeola", Year: 2022},
+	}
+	filters := []models.VaccinationFilter{
+		{},
+		{Antigens: []string{"Rujeola"}},
+		{Antigens: []string{"Hepatita B", "Rujeola"}, EndYear: intPtr(2018)},
+	}
+
+	for _, filter := range filters {
+		p, err := CompileVaccinationFilter(filter)
+		if err != nil {
+			t.Fatalf("CompileVaccinationFilter(%+v): %v", filter, err)
+		}
+		where, args := p.SQL()
+		for i := range coverage {
+			c := &coverage[i]
+			row := map[string]any{"antigen": c.Antigen, "year": c.Year}
+			if sql, match := evalWhere(t, where, args, row), p.Match(c); sql != match {
+				t.Errorf("%+v on %+v: SQL %q selects it: %v, Match: %v", filter, *c, where, sql, match)
+			}
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// evalWhere evaluates a WHERE condition rendered by one of the predicates against a row of column
+// values, so that SQL can be checked against Match without a ClickHouse server. It understands the
+// subset the predicates emit: AND, OR, parentheses, comparisons, IN lists, startsWith and 1=1.
+func evalWhere(t *testing.T, where string, args []interface{}, row map[string]any) bool {
+	t.Helper()
+
+	e := &whereEval{tokens: tokenizeWhere(where), args: args, row: row}
+	result, err := e.or()
+	if err == nil && e.pos != len(e.tokens) {
+		err = fmt.Errorf("unexpected %q", e.tokens[e.pos])
+	}
+	if err == nil && e.arg != len(args) {
+		err = fmt.Errorf("%d placeholders for %d arguments", e.arg, len(args))
+	}
+	if err != nil {
+		t.Fatalf("evaluating %q: %v", where, err)
+	}
+	return result
+}
+
+type whereEval struct {
+	tokens []string
+	pos    int
+	args   []interface{}
+	arg    int
+	row    map[string]any
+}
+
+func tokenizeWhere(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case strings.ContainsRune("(),?", c):
+			tokens = append(tokens, string(c))
+			i++
+		case strings.ContainsRune("<>=!", c):
+			j := i + 1
+			if j < len(s) && s[j] == '=' {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			j := i
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_' || s[j] == '.') {
+				j++
+			}
+			if j == i {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+func (e *whereEval) peek() string {
+	if e.pos < len(e.tokens) {
+		return e.tokens[e.pos]
+	}
+	return ""
+}
+
+func (e *whereEval) expect(token string) error {
+	if e.peek() != token {
+		return fmt.Errorf("expected %q, got %q", token, e.peek())
+	}
+	e.pos++
+	return nil
+}
+
+// Both sides of AND and OR are always evaluated so that every placeholder is consumed in order
+func (e *whereEval) or() (bool, error) {
+	result, err := e.and()
+	for err == nil && strings.EqualFold(e.peek(), "OR") {
+		e.pos++
+		var next bool
+		next, err = e.and()
+		result = result || next
+	}
+	return result, err
+}
+
+func (e *whereEval) and() (bool, error) {
+	result, err := e.atom()
+	for err == nil && strings.EqualFold(e.peek(), "AND") {
+		e.pos++
+		var next bool
+		next, err = e.atom()
+		result = result && next
+	}
+	return result, err
+}
+
+func (e *whereEval) atom() (bool, error) {
+	switch e.peek() {
+	case "(":
+		e.pos++
+		result, err := e.or()
+		if err != nil {
+			return false, err
+		}
+		return result, e.expect(")")
+	case "startsWith":
+		e.pos++
+		if err := e.expect("("); err != nil {
+			return false, err
+		}
+		s, err := e.operand()
+		if err != nil {
+			return false, err
+		}
+		if err := e.expect(","); err != nil {
+			return false, err
+		}
+		prefix, err := e.operand()
+		if err != nil {
+			return false, err
+		}
+		return strings.HasPrefix(fmt.Sprint(s), fmt.Sprint(prefix)), e.expect(")")
+	}
+
+	left, err := e.operand()
+	if err != nil {
+		return false, err
+	}
+
+	if strings.EqualFold(e.peek(), "IN") {
+		e.pos++
+		if err := e.expect("("); err != nil {
+			return false, err
+		}
+		found := false
+		for {
+			v, err := e.operand()
+			if err != nil {
+				return false, err
+			}
+			found = found || compareWhere(left, v) == 0
+			if e.peek() != "," {
+				break
+			}
+			e.pos++
+		}
+		return found, e.expect(")")
+	}
+
+	op := e.peek()
+	e.pos++
+	right, err := e.operand()
+	if err != nil {
+		return false, err
+	}
+	c := compareWhere(left, right)
+	switch op {
+	case "=":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	}
+	return false, fmt.Errorf("unknown operator %q", op)
+}
+
+func (e *whereEval) operand() (any, error) {
+	token := e.peek()
+	e.pos++
+	switch {
+	case token == "?":
+		if e.arg >= len(e.args) {
+			return nil, fmt.Errorf("more placeholders than arguments")
+		}
+		e.arg++
+		return e.args[e.arg-1], nil
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of condition")
+	}
+	if n, err := strconv.ParseFloat(token, 64); err == nil {
+		return n, nil
+	}
+	v, ok := e.row[token]
+	if !ok {
+		return nil, fmt.Errorf("unknown column %q", token)
+	}
+	return v, nil
+}
+
+// compareWhere compares two values, numbers of any width numerically and everything else as text
+func compareWhere(a, b any) int {
+	fa, aNum := whereNumber(a)
+	fb, bNum := whereNumber(b)
+	if aNum && bNum {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func whereNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -37,7 +37,7 @@ func (h *Handler) Query(w http.ResponseWriter, r *http.Request) {
 	// Process the natural language query
 	result, err := h.service.ProcessQuery(r.Context(), req.Question)
 	if err != nil {
-		common.ErrorResponse(w, "Error processing AI query: "+err.Error(), http.StatusInternalServerError)
+		common.ErrorResponse(w, "Error processing AI query: "+err.Error(), common.StatusFromError(err))
 		return
 	}
 
@@ -2,6 +2,8 @@ package analytics
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/ktruedat/healthisis/backend/internal/models"
@@ -41,42 +43,151 @@ func (h *Handler) Correlation(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.service.AnalyzeCorrelation(r.Context(), &req)
 	if err != nil {
-		common.ErrorResponse(w, "Error analyzing correlation: "+err.Error(), http.StatusInternalServerError)
+		common.ErrorResponse(w, "Error analyzing correlation: "+err.Error(), common.StatusFromError(err))
 		return
 	}
 
 	common.JSONResponse(w, http.StatusOK, result)
 }
 
+// LaggedCorrelation handles POST /analytics/correlation/lagged
+func (h *Handler) LaggedCorrelation(w http.ResponseWriter, r *http.Request) {
+	var req models.CorrelationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.ErrorResponse(w, "Invalid request format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.Factor1 == "" || req.Factor2 == "" {
+		common.ErrorResponse(w, "Both factor1 and factor2 are required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.AnalyzeLaggedCorrelation(r.Context(), &req)
+	if err != nil {
+		common.ErrorResponse(w, "Error analyzing lagged correlation: "+err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, result)
+}
+
+// CorrelationMatrix handles POST /analytics/correlation/matrix
+func (h *Handler) CorrelationMatrix(w http.ResponseWriter, r *http.Request) {
+	var req models.CorrelationMatrixRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.ErrorResponse(w, "Invalid request format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	matrix, err := h.service.GetCorrelations(r.Context(), &req)
+	if err != nil {
+		common.ErrorResponse(w, "Error computing correlation matrix: "+err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, matrix)
+}
+
+// StandardizedRates handles POST /analytics/standardized-rates
+func (h *Handler) StandardizedRates(w http.ResponseWriter, r *http.Request) {
+	var req models.StandardizedRatesRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.ErrorResponse(w, "Invalid request format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rates, err := h.service.GetStandardizedRates(r.Context(), &req)
+	if err != nil {
+		common.ErrorResponse(w, "Error computing standardized rates: "+err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, rates)
+}
+
+// Vaccination handles POST /analytics/vaccination
+func (h *Handler) Vaccination(w http.ResponseWriter, r *http.Request) {
+	var req models.VaccinationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.ErrorResponse(w, "Invalid request format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	analysis, err := h.service.AnalyzeVaccination(r.Context(), &req)
+	if err != nil {
+		common.ErrorResponse(w, "Error analyzing vaccination coverage: "+err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, analysis)
+}
+
 // Forecast handles POST /analytics/forecast
 func (h *Handler) Forecast(w http.ResponseWriter, r *http.Request) {
-	var params map[string]interface{}
+	var req models.ForecastRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		common.ErrorResponse(w, "Invalid request format: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	// Validate required parameters
-	if _, ok := params["disease_id"]; !ok {
+	if req.DiseaseID == "" {
 		common.ErrorResponse(w, "disease_id is required", http.StatusBadRequest)
 		return
 	}
 
-	if _, ok := params["year"]; !ok {
+	if req.Year == 0 {
 		common.ErrorResponse(w, "year is required", http.StatusBadRequest)
 		return
 	}
 
-	forecast, err := h.service.GetDiseaseForecast(r.Context(), params)
+	forecast, err := h.service.GetDiseaseForecast(r.Context(), &req)
 	if err != nil {
-		common.ErrorResponse(w, "Error generating forecast: "+err.Error(), http.StatusInternalServerError)
+		common.ErrorResponse(w, "Error generating forecast: "+err.Error(), common.StatusFromError(err))
 		return
 	}
 
 	common.JSONResponse(w, http.StatusOK, forecast)
 }
 
+// Backtest handles POST /analytics/forecast/backtest
+func (h *Handler) Backtest(w http.ResponseWriter, r *http.Request) {
+	var req models.BacktestRequest
+
+	// An empty body backtests every model on every disease with the defaults
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		common.ErrorResponse(w, "Invalid request format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.RunBacktest(r.Context(), &req)
+	if err != nil {
+		common.ErrorResponse(w, "Error running backtest: "+err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, report)
+}
+
+// BacktestResults handles GET /analytics/forecast/backtest
+func (h *Handler) BacktestResults(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	results, err := h.service.GetBacktestResults(r.Context(), q.Get("disease_id"), q.Get("model"))
+	if err != nil {
+		common.ErrorResponse(w, "Error retrieving backtest results: "+err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"results": results})
+}
+
 // Query handles POST /analytics/query
 func (h *Handler) Query(w http.ResponseWriter, r *http.Request) {
 	var query models.DiseaseQuery
@@ -95,7 +206,7 @@ func (h *Handler) Query(w http.ResponseWriter, r *http.Request) {
 	// This is different from AI queries which might use more advanced NLP
 	result, err := h.service.ProcessAnalyticsQuery(r.Context(), query.Query)
 	if err != nil {
-		common.ErrorResponse(w, "Error processing analytics query: "+err.Error(), http.StatusInternalServerError)
+		common.ErrorResponse(w, "Error processing analytics query: "+err.Error(), common.StatusFromError(err))
 		return
 	}
 
@@ -0,0 +1,63 @@
+package capacity
+
+import (
+	"net/http"
+
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/common"
+	"github.com/ktruedat/healthisis/backend/internal/services"
+)
+
+// Handler handles requests for hospital bed capacity
+type Handler struct {
+	service *services.CapacityService
+}
+
+// New creates a new capacity handler
+func New(service *services.CapacityService) *Handler {
+	return &Handler{service: service}
+}
+
+// Profiles handles GET /capacity/profiles
+func (h *Handler) Profiles(w http.ResponseWriter, r *http.Request) {
+	profiles, err := h.service.Profiles(r.Context())
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"profiles": profiles})
+}
+
+// Beds handles GET /capacity/beds
+func (h *Handler) Beds(w http.ResponseWriter, r *http.Request) {
+	filter, err := common.ParseCapacityFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := h.service.Beds(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"series": series})
+}
+
+// Burden handles GET /capacity/burden
+func (h *Handler) Burden(w http.ResponseWriter, r *http.Request) {
+	filter, err := common.ParseCapacityFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	burden, err := h.service.Burden(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"burden": burden})
+}
@@ -101,7 +101,7 @@ func (h *Handler) GetDiseases(w http.ResponseWriter, r *http.Request) {
 
 	diseases, err := h.service.GetDiseasesByCategory(r.Context(), id)
 	if err != nil {
-		common.ErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
 		return
 	}
 
@@ -0,0 +1,286 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// ParseDiseaseFilter extracts the DiseaseFilter query parameters documented for GET /diseases.
+// List parameters may be repeated (quarters=1&quarters=2), comma-separated (quarters=1,2) or both.
+// Malformed values produce an error wrapping models.ErrInvalidFilter.
+func ParseDiseaseFilter(r *http.Request) (models.DiseaseFilter, error) {
+	q := r.URL.Query()
+	var filter models.DiseaseFilter
+	var err error
+
+	if filter.StartYear, err = optionalInt(q, "startYear"); err != nil {
+		return filter, err
+	}
+	if filter.EndYear, err = optionalInt(q, "endYear"); err != nil {
+		return filter, err
+	}
+	if filter.MinCases, err = optionalInt(q, "minCases"); err != nil {
+		return filter, err
+	}
+	if filter.MaxCases, err = optionalInt(q, "maxCases"); err != nil {
+		return filter, err
+	}
+
+	for _, v := range ListParam(q, "quarters") {
+		quarter, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, fmt.Errorf("%w: quarters: %q is not an integer", models.ErrInvalidFilter, v)
+		}
+		filter.Quarters = append(filter.Quarters, quarter)
+	}
+
+	filter.Regions = ListParam(q, "regions")
+	filter.Categories = ListParam(q, "categories")
+	filter.Names = ListParam(q, "names")
+	filter.AgeGroup = strings.TrimSpace(q.Get("ageGroup"))
+	filter.DiseaseIDs = ListParam(q, "diseaseIds")
+
+	filter.Sort = q.Get("sort")
+	filter.SortBy = q.Get("sortBy")
+	filter.SortOrder = q.Get("sortOrder")
+
+	if filter.Limit, filter.Offset, err = parsePaging(q); err != nil {
+		return filter, err
+	}
+
+	filter.Cursor = q.Get("cursor")
+	filter.Include = ListParam(q, "include")
+
+	return filter, nil
+}
+
+// ParseEnvironmentFilter extracts the EnvironmentFilter query parameters documented for
+// GET /environment/series; the year and quarter parameters are those of ParseDiseaseFilter
+func ParseEnvironmentFilter(r *http.Request) (models.EnvironmentFilter, error) {
+	q := r.URL.Query()
+	var filter models.EnvironmentFilter
+	var err error
+
+	if filter.StartYear, err = optionalInt(q, "startYear"); err != nil {
+		return filter, err
+	}
+	if filter.EndYear, err = optionalInt(q, "endYear"); err != nil {
+		return filter, err
+	}
+
+	for _, v := range ListParam(q, "quarters") {
+		quarter, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, fmt.Errorf("%w: quarters: %q is not an integer", models.ErrInvalidFilter, v)
+		}
+		filter.Quarters = append(filter.Quarters, quarter)
+	}
+
+	filter.Variables = ListParam(q, "variables")
+	filter.Stations = ListParam(q, "stations")
+	filter.Granularity = q.Get("granularity")
+
+	return filter, nil
+}
+
+// ParseEmissionFilter extracts the EmissionFilter query parameters documented for
+// GET /environment/emissions
+func ParseEmissionFilter(r *http.Request) (models.EmissionFilter, error) {
+	q := r.URL.Query()
+	filter := models.EmissionFilter{Pollutants: ListParam(q, "pollutants")}
+	var err error
+
+	if filter.StartYear, err = optionalInt(q, "startYear"); err != nil {
+		return filter, err
+	}
+	filter.EndYear, err = optionalInt(q, "endYear")
+	return filter, err
+}
+
+// ParsePopulationFilter extracts the PopulationFilter query parameters documented for
+// GET /population
+func ParsePopulationFilter(r *http.Request) (models.PopulationFilter, error) {
+	q := r.URL.Query()
+	var filter models.PopulationFilter
+	var err error
+
+	if filter.StartYear, err = optionalInt(q, "startYear"); err != nil {
+		return filter, err
+	}
+	if filter.EndYear, err = optionalInt(q, "endYear"); err != nil {
+		return filter, err
+	}
+
+	filter.Ages = ListParam(q, "ages")
+	filter.Media = ListParam(q, "media")
+	filter.Sexes = ListParam(q, "sexes")
+
+	return filter, nil
+}
+
+// ParseStratifiedFilter extracts the StratifiedFilter query parameters documented for
+// GET /demographics/series
+func ParseStratifiedFilter(r *http.Request) (models.StratifiedFilter, error) {
+	q := r.URL.Query()
+	filter := models.StratifiedFilter{Indicator: strings.TrimSpace(q.Get("indicator"))}
+	var err error
+
+	if filter.StartYear, err = optionalInt(q, "startYear"); err != nil {
+		return filter, err
+	}
+	if filter.EndYear, err = optionalInt(q, "endYear"); err != nil {
+		return filter, err
+	}
+	if width, err := optionalInt(q, "bandWidth"); err != nil {
+		return filter, err
+	} else if width != nil {
+		filter.BandWidth = *width
+	}
+	if openAge, err := optionalInt(q, "openAge"); err != nil {
+		return filter, err
+	} else if openAge != nil {
+		filter.OpenAge = *openAge
+	}
+
+	filter.Ages = ListParam(q, "ages")
+	filter.Sexes = ListParam(q, "sexes")
+	filter.Media = ListParam(q, "media")
+	filter.GroupBy = ListParam(q, "groupBy")
+
+	return filter, nil
+}
+
+// ParsePyramidFilter extracts the query parameters documented for GET /demographics/pyramid: those
+// of ParseStratifiedFilter and the year, nil when absent
+func ParsePyramidFilter(r *http.Request) (models.StratifiedFilter, *int, error) {
+	filter, err := ParseStratifiedFilter(r)
+	if err != nil {
+		return filter, nil, err
+	}
+
+	year, err := optionalInt(r.URL.Query(), "year")
+	return filter, year, err
+}
+
+// ParseLifeTableQuery extracts the query parameters documented for GET /mortality/life-table
+func ParseLifeTableQuery(r *http.Request) (models.LifeTableQuery, error) {
+	q := r.URL.Query()
+	query := models.LifeTableQuery{Sex: strings.TrimSpace(q.Get("sex")), Medium: strings.TrimSpace(q.Get("medium"))}
+	var err error
+
+	query.Year, err = optionalInt(q, "year")
+	return query, err
+}
+
+// ParseDecompositionQuery extracts the query parameters documented for GET /mortality/decomposition
+func ParseDecompositionQuery(r *http.Request) (models.DecompositionQuery, error) {
+	q := r.URL.Query()
+	query := models.DecompositionQuery{Sex: strings.TrimSpace(q.Get("sex")), Medium: strings.TrimSpace(q.Get("medium"))}
+	var err error
+
+	if query.FromYear, err = optionalInt(q, "fromYear"); err != nil {
+		return query, err
+	}
+	query.ToYear, err = optionalInt(q, "toYear")
+	return query, err
+}
+
+// ParseCapacityFilter extracts the query parameters documented for GET /capacity/beds and
+// GET /capacity/burden
+func ParseCapacityFilter(r *http.Request) (models.CapacityFilter, error) {
+	q := r.URL.Query()
+	filter := models.CapacityFilter{Profiles: ListParam(q, "profiles")}
+	var err error
+
+	if filter.StartYear, err = optionalInt(q, "startYear"); err != nil {
+		return filter, err
+	}
+	filter.EndYear, err = optionalInt(q, "endYear")
+	return filter, err
+}
+
+// ParseDisabilityFilter extracts the DisabilityFilter query parameters documented for
+// GET /disability and GET /disability/series: the strata and grouping of ParseStratifiedFilter and
+// the sorting and offset pagination of ParseDiseaseFilter
+func ParseDisabilityFilter(r *http.Request) (models.DisabilityFilter, error) {
+	q := r.URL.Query()
+	var filter models.DisabilityFilter
+	var err error
+
+	if filter.StartYear, err = optionalInt(q, "startYear"); err != nil {
+		return filter, err
+	}
+	if filter.EndYear, err = optionalInt(q, "endYear"); err != nil {
+		return filter, err
+	}
+
+	filter.Ages = ListParam(q, "ages")
+	filter.Sexes = ListParam(q, "sexes")
+	filter.Media = ListParam(q, "media")
+	filter.GroupBy = ListParam(q, "groupBy")
+
+	filter.Sort = q.Get("sort")
+	filter.SortBy = q.Get("sortBy")
+	filter.SortOrder = q.Get("sortOrder")
+
+	if filter.Limit, filter.Offset, err = parsePaging(q); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// ListParam returns the values of a list query parameter, accepting repeated and
+// comma-separated forms and dropping blank entries
+func ListParam(q url.Values, name string) []string {
+	var values []string
+	for _, raw := range q[name] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// parsePaging parses the limit and offset query parameters of offset pagination, zero when absent
+func parsePaging(q url.Values) (limit, offset int, err error) {
+	if v, err := optionalInt(q, "limit"); err != nil {
+		return 0, 0, err
+	} else if v != nil {
+		if *v < 0 {
+			return 0, 0, fmt.Errorf("%w: limit must not be negative", models.ErrInvalidFilter)
+		}
+		limit = *v
+	}
+
+	if v, err := optionalInt(q, "offset"); err != nil {
+		return 0, 0, err
+	} else if v != nil {
+		if *v < 0 {
+			return 0, 0, fmt.Errorf("%w: offset must not be negative", models.ErrInvalidFilter)
+		}
+		offset = *v
+	}
+	return limit, offset, nil
+}
+
+// optionalInt parses an integer query parameter, returning nil when it is absent
+func optionalInt(q url.Values, name string) (*int, error) {
+	raw := strings.TrimSpace(q.Get(name))
+	if raw == "" {
+		return nil, nil
+	}
+
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %q is not an integer", models.ErrInvalidFilter, name, raw)
+	}
+	return &v, nil
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// SetPageLinks writes an RFC 5988 Link header for a disease page. Offset pages link to the
+// first, previous, next and last pages; cursor pages link to the first and next pages.
+func SetPageLinks(w http.ResponseWriter, r *http.Request, filter models.DiseaseFilter, page *models.DiseasePage) {
+	var links []string
+	link := func(rel string, set map[string]string) {
+		links = append(links, fmt.Sprintf("<%s>; rel=%q", pageURL(r, set), rel))
+	}
+
+	limit := strconv.Itoa(page.Limit)
+	link("first", map[string]string{"limit": limit, "offset": "", "cursor": ""})
+
+	if filter.Cursor != "" {
+		if page.HasMore {
+			link("next", map[string]string{"limit": limit, "offset": "", "cursor": page.NextCursor})
+		}
+	} else {
+		offsetLinks(link, filter.Offset, page.Limit, page.TotalPages, page.HasMore)
+	}
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+// SetDisabilityPageLinks writes an RFC 5988 Link header for a page of the disability table,
+// linking to the first, previous, next and last pages as SetPageLinks does for offset pages
+func SetDisabilityPageLinks(w http.ResponseWriter, r *http.Request, filter models.DisabilityFilter, page *models.DisabilityPage) {
+	var links []string
+	link := func(rel string, set map[string]string) {
+		links = append(links, fmt.Sprintf("<%s>; rel=%q", pageURL(r, set), rel))
+	}
+
+	link("first", map[string]string{"limit": strconv.Itoa(page.Limit), "offset": ""})
+	offsetLinks(link, filter.Offset, page.Limit, page.TotalPages, page.HasMore)
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+// offsetLinks adds the previous, next and last links of an offset page
+func offsetLinks(link func(rel string, set map[string]string), offset, limit, totalPages int, hasMore bool) {
+	l := strconv.Itoa(limit)
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		link("prev", map[string]string{"limit": l, "offset": strconv.Itoa(prev), "cursor": ""})
+	}
+	if hasMore {
+		link("next", map[string]string{"limit": l, "offset": strconv.Itoa(offset + limit), "cursor": ""})
+	}
+	if totalPages > 0 {
+		last := (totalPages - 1) * limit
+		link("last", map[string]string{"limit": l, "offset": strconv.Itoa(last), "cursor": ""})
+	}
+}
+
+// pageURL returns the request path and query with the given parameters replaced;
+// empty values remove the parameter
+func pageURL(r *http.Request, set map[string]string) string {
+	q := r.URL.Query()
+	for k, v := range set {
+		if v == "" {
+			q.Del(k)
+		} else {
+			q.Set(k, v)
+		}
+	}
+
+	if len(q) == 0 {
+		return r.URL.Path
+	}
+	return r.URL.Path + "?" + q.Encode()
+}
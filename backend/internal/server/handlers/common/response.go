@@ -2,7 +2,10 @@ package common
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
 )
 
 // ErrorResponse writes an error response
@@ -19,9 +22,15 @@ func ErrorResponse(w http.ResponseWriter, message string, statusCode int) {
 	}
 }
 
-// JSONResponse writes a JSON response
+// SyntheticHeader is set on every response built from synthetic demo data
+const SyntheticHeader = "X-Data-Synthetic"
+
+// JSONResponse writes a JSON response, flagging synthetic data with SyntheticHeader
 func JSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
+	if s, ok := data.(interface{ IsSynthetic() bool }); ok && s.IsSynthetic() {
+		w.Header().Set(SyntheticHeader, "true")
+	}
 	w.WriteHeader(statusCode)
 
 	if data != nil {
@@ -31,3 +40,19 @@ func JSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
 		}
 	}
 }
+
+// StatusFromError maps service errors to HTTP status codes, defaulting to 500
+func StatusFromError(err error) int {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidFilter), errors.Is(err, models.ErrInvalidRecord):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrInsufficientData):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, models.ErrNotImplemented):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,6 +1,7 @@
 package dashboard
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/ktruedat/healthisis/backend/internal/models"
@@ -10,26 +11,33 @@ import (
 
 // Handler handles dashboard-related requests
 type Handler struct {
-	service *services.DiseaseService
+	service  *services.DiseaseService
+	capacity *services.CapacityService
 }
 
 // New creates a new dashboard handler
-func New(service *services.DiseaseService) *Handler {
-	return &Handler{service: service}
+func New(service *services.DiseaseService, capacity *services.CapacityService) *Handler {
+	return &Handler{service: service, capacity: capacity}
 }
 
 // Summary handles GET /dashboard/summary
 func (h *Handler) Summary(w http.ResponseWriter, r *http.Request) {
-	var filter models.DiseaseFilter
+	filter, err := common.ParseDiseaseFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// extract the diseaseID from the url query param
 	diseaseID := r.URL.Query().Get("diseaseID")
 	if diseaseID != "" {
-		filter.DiseaseIDs = []string{diseaseID}
+		filter.DiseaseIDs = append(filter.DiseaseIDs, diseaseID)
 	}
 
-	summary, err := h.service.GetDiseaseStats(r.Context(), filter)
+	window := models.ComparisonWindow(r.URL.Query().Get("comparison"))
+	summary, err := h.service.GetDiseaseStats(r.Context(), filter, window)
 	if err != nil {
-		common.ErrorResponse(w, "Error retrieving dashboard summary: "+err.Error(), http.StatusInternalServerError)
+		common.ErrorResponse(w, "Error retrieving dashboard summary: "+err.Error(), common.StatusFromError(err))
 		return
 	}
 
@@ -44,14 +52,29 @@ func (h *Handler) Summary(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Hospital capacity is national, so it is only summarised next to the overall figures; it is
+	// left out when no beds were imported
+	capacity, err := h.capacity.Summary(r.Context(), filter.EndYear)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		common.ErrorResponse(w, "Error retrieving hospital capacity: "+err.Error(), common.StatusFromError(err))
+		return
+	}
+	summary.Capacity = capacity
+
 	common.JSONResponse(w, http.StatusOK, summary)
 }
 
 // Trends handles GET /dashboard/trends
 func (h *Handler) Trends(w http.ResponseWriter, r *http.Request) {
-	trends, err := h.service.GetTimeSeries(r.Context(), models.DiseaseFilter{})
+	filter, err := common.ParseDiseaseFilter(r)
 	if err != nil {
-		common.ErrorResponse(w, "Error retrieving disease trends: "+err.Error(), http.StatusInternalServerError)
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trends, err := h.service.GetTimeSeries(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, "Error retrieving disease trends: "+err.Error(), common.StatusFromError(err))
 		return
 	}
 
@@ -60,32 +83,16 @@ func (h *Handler) Trends(w http.ResponseWriter, r *http.Request) {
 
 // Map handles GET /dashboard/map
 func (h *Handler) Map(w http.ResponseWriter, r *http.Request) {
-	// This would typically return geographic distribution of diseases
-	// For now, we'll return mock data
-	geoData := map[string]interface{}{
-		"regions": []map[string]interface{}{
-			{
-				"name":     "Chisinau",
-				"cases":    1245,
-				"lat":      47.0105,
-				"lon":      28.8638,
-				"diseases": []string{"Influenza", "COVID-19", "Tuberculosis"},
-			},
-			{
-				"name":     "Balti",
-				"cases":    532,
-				"lat":      47.7619,
-				"lon":      27.9294,
-				"diseases": []string{"Influenza", "Hepatitis A"},
-			},
-			{
-				"name":     "Tiraspol",
-				"cases":    348,
-				"lat":      46.8403,
-				"lon":      29.6433,
-				"diseases": []string{"COVID-19", "Tuberculosis"},
-			},
-		},
+	filter, err := common.ParseDiseaseFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	geoData, err := h.service.GetRegionalDistribution(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, "Error retrieving regional distribution: "+err.Error(), common.StatusFromError(err))
+		return
 	}
 
 	common.JSONResponse(w, http.StatusOK, geoData)
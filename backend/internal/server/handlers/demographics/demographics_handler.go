@@ -0,0 +1,52 @@
+package demographics
+
+import (
+	"net/http"
+
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/common"
+	"github.com/ktruedat/healthisis/backend/internal/services"
+)
+
+// Handler handles requests for indicators broken down by age, sex and medium
+type Handler struct {
+	service *services.DemographicsService
+}
+
+// New creates a new demographics handler
+func New(service *services.DemographicsService) *Handler {
+	return &Handler{service: service}
+}
+
+// Series handles GET /demographics/series
+func (h *Handler) Series(w http.ResponseWriter, r *http.Request) {
+	filter, err := common.ParseStratifiedFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := h.service.Series(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"series": series})
+}
+
+// Pyramid handles GET /demographics/pyramid
+func (h *Handler) Pyramid(w http.ResponseWriter, r *http.Request) {
+	filter, year, err := common.ParsePyramidFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pyramid, err := h.service.Pyramid(r.Context(), filter, year)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, pyramid)
+}
@@ -0,0 +1,53 @@
+package disability
+
+import (
+	"net/http"
+
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/common"
+	"github.com/ktruedat/healthisis/backend/internal/services"
+)
+
+// Handler handles requests for the disability rates of adults
+type Handler struct {
+	service *services.DisabilityService
+}
+
+// New creates a new disability handler
+func New(service *services.DisabilityService) *Handler {
+	return &Handler{service: service}
+}
+
+// List handles GET /disability
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	filter, err := common.ParseDisabilityFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.service.ListRates(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.SetDisabilityPageLinks(w, r, filter, page)
+	common.JSONResponse(w, http.StatusOK, page)
+}
+
+// Series handles GET /disability/series
+func (h *Handler) Series(w http.ResponseWriter, r *http.Request) {
+	filter, err := common.ParseDisabilityFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := h.service.Series(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"series": series})
+}
@@ -23,55 +23,50 @@ func New(service *services.DiseaseService) *Handler {
 
 // List handles GET /diseases
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
-	filter := parseFilterFromQuery(r)
-
-	// Store the original limit for later use
-	originalLimit := filter.Limit
-	if originalLimit <= 0 {
-		originalLimit = 10 // Default limit if not provided
+	filter, err := common.ParseDiseaseFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	// Increase the limit by 1 to check if there are more records
-	filter.Limit = originalLimit + 1
+	if filter.Limit <= 0 {
+		filter.Limit = 10 // Default limit if not provided
+	}
 
-	diseases, err := h.service.ListDiseases(r.Context(), filter)
+	page, err := h.service.ListDiseasePage(r.Context(), filter)
 	if err != nil {
-		common.ErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
 		return
 	}
 
-	// Check if we got more results than requested
-	hasMore := len(diseases) > originalLimit
+	common.SetPageLinks(w, r, filter, page)
+	common.JSONResponse(w, http.StatusOK, page)
+}
 
-	// If we have more, remove the extra record before returning
-	if hasMore {
-		diseases = diseases[:originalLimit]
+// Paediatric handles GET /diseases/paediatric
+func (h *Handler) Paediatric(w http.ResponseWriter, r *http.Request) {
+	filter, err := common.ParseDiseaseFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	// Create a response with both the diseases and pagination info
-	response := map[string]any{
-		"diseases":   diseases,
-		"totalCount": len(diseases), // This is just the count of current page items
-		"page":       (filter.Offset / originalLimit) + 1,
-		"limit":      originalLimit,
-		"hasMore":    hasMore,
+	breakdown, err := h.service.GetPaediatricBreakdown(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
 	}
 
-	common.JSONResponse(w, http.StatusOK, response)
+	common.JSONResponse(w, http.StatusOK, breakdown)
 }
 
 // Get handles GET /diseases/{id}
 func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "diseaseID")
 
-	disease, err := h.service.GetDiseaseByID(r.Context(), id)
+	disease, err := h.service.GetDiseaseByID(r.Context(), id, common.ListParam(r.URL.Query(), "include"))
 	if err != nil {
-		common.ErrorResponse(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	if disease == nil {
-		common.ErrorResponse(w, "Disease not found", http.StatusNotFound)
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
 		return
 	}
 
@@ -88,7 +83,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.CreateDisease(r.Context(), &disease); err != nil {
-		common.ErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
 		return
 	}
 
@@ -107,7 +102,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 
 	disease.ID = id
 	if err := h.service.UpdateDisease(r.Context(), &disease); err != nil {
-		common.ErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
 		return
 	}
 
@@ -145,55 +140,58 @@ func (h *Handler) AddData(w http.ResponseWriter, r *http.Request) {
 	common.JSONResponse(w, http.StatusCreated, data)
 }
 
-// Predict handles GET /diseases/{id}/predict
-func (h *Handler) Predict(w http.ResponseWriter, r *http.Request) {
+// Compare handles GET /diseases/{id}/compare
+func (h *Handler) Compare(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "diseaseID")
-	yearStr := r.URL.Query().Get("year")
 
-	year, err := strconv.Atoi(yearStr)
-	if err != nil {
-		common.ErrorResponse(w, "Invalid year parameter", http.StatusBadRequest)
+	years := r.URL.Query().Get("years")
+	if years == "" {
+		common.ErrorResponse(w, "years parameter is required", http.StatusBadRequest)
 		return
 	}
 
-	prediction, err := h.service.PredictDiseaseCases(r.Context(), id, year)
+	baseline := 0
+	if baselineStr := r.URL.Query().Get("baseline"); baselineStr != "" {
+		var err error
+		if baseline, err = strconv.Atoi(baselineStr); err != nil {
+			common.ErrorResponse(w, "Invalid baseline year", http.StatusBadRequest)
+			return
+		}
+	}
+
+	comparison, err := h.service.CompareDiseaseTrends(r.Context(), id, years, baseline)
 	if err != nil {
-		common.ErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
 		return
 	}
 
-	common.JSONResponse(w, http.StatusOK, prediction)
+	common.JSONResponse(w, http.StatusOK, comparison)
 }
 
-// parseFilterFromQuery extracts filter parameters from the request query
-func parseFilterFromQuery(r *http.Request) models.DiseaseFilter {
-	filter := models.DiseaseFilter{}
-
-	// Parse limit and offset
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			filter.Limit = limit
-		}
-	}
+// Predict handles GET /diseases/{id}/predict
+func (h *Handler) Predict(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "diseaseID")
+	yearStr := r.URL.Query().Get("year")
 
-	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil {
-			filter.Offset = offset
-		}
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		common.ErrorResponse(w, "Invalid year parameter", http.StatusBadRequest)
+		return
 	}
 
-	// Parse year filters
-	if startYearStr := r.URL.Query().Get("startYear"); startYearStr != "" {
-		if startYear, err := strconv.Atoi(startYearStr); err == nil {
-			filter.StartYear = &startYear
+	var level float64
+	if levelStr := r.URL.Query().Get("level"); levelStr != "" {
+		if level, err = strconv.ParseFloat(levelStr, 64); err != nil {
+			common.ErrorResponse(w, "Invalid level parameter", http.StatusBadRequest)
+			return
 		}
 	}
 
-	if endYearStr := r.URL.Query().Get("endYear"); endYearStr != "" {
-		if endYear, err := strconv.Atoi(endYearStr); err == nil {
-			filter.EndYear = &endYear
-		}
+	prediction, err := h.service.PredictDiseaseCases(r.Context(), id, year, r.URL.Query().Get("model"), level)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
 	}
 
-	return filter
+	common.JSONResponse(w, http.StatusOK, prediction)
 }
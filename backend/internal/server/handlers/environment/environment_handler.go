@@ -0,0 +1,63 @@
+package environment
+
+import (
+	"net/http"
+
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/common"
+	"github.com/ktruedat/healthisis/backend/internal/services"
+)
+
+// Handler handles environment-related requests
+type Handler struct {
+	service *services.EnvironmentService
+}
+
+// New creates a new environment handler
+func New(service *services.EnvironmentService) *Handler {
+	return &Handler{service: service}
+}
+
+// Series handles GET /environment/series
+func (h *Handler) Series(w http.ResponseWriter, r *http.Request) {
+	filter, err := common.ParseEnvironmentFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := h.service.Series(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"series": series})
+}
+
+// Emissions handles GET /environment/emissions
+func (h *Handler) Emissions(w http.ResponseWriter, r *http.Request) {
+	filter, err := common.ParseEmissionFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := h.service.Emissions(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"series": series})
+}
+
+// Stations handles GET /environment/stations
+func (h *Handler) Stations(w http.ResponseWriter, r *http.Request) {
+	stations, err := h.service.Stations(r.Context())
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"stations": stations})
+}
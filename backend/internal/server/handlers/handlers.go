@@ -1,45 +1,82 @@
 package handlers
 
 import (
-	"github.com/ktruedat/healthisis/backend/internal/database"
 	"github.com/ktruedat/healthisis/backend/internal/pkg/log"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
 	"github.com/ktruedat/healthisis/backend/internal/server/handlers/ai"
 	"github.com/ktruedat/healthisis/backend/internal/server/handlers/analytics"
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/capacity"
 	"github.com/ktruedat/healthisis/backend/internal/server/handlers/category"
 	"github.com/ktruedat/healthisis/backend/internal/server/handlers/dashboard"
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/demographics"
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/disability"
 	"github.com/ktruedat/healthisis/backend/internal/server/handlers/disease"
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/environment"
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/mortality"
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/population"
 	"github.com/ktruedat/healthisis/backend/internal/server/handlers/system"
 	"github.com/ktruedat/healthisis/backend/internal/services"
 )
 
 // Handlers holds all API handlers
 type Handlers struct {
-	Disease   *disease.Handler
-	Category  *category.Handler
-	Analytics *analytics.Handler
-	AI        *ai.Handler
-	Dashboard *dashboard.Handler
-	System    *system.Handler
-	logger    log.Logger
+	Disease      *disease.Handler
+	Category     *category.Handler
+	Analytics    *analytics.Handler
+	AI           *ai.Handler
+	Dashboard    *dashboard.Handler
+	Environment  *environment.Handler
+	Population   *population.Handler
+	Demographics *demographics.Handler
+	Mortality    *mortality.Handler
+	Capacity     *capacity.Handler
+	Disability   *disability.Handler
+	System       *system.Handler
+	logger       log.Logger
 }
 
-// New creates all handlers
-func New(db *database.DB, logger log.Logger) *Handlers {
+// New creates all handlers on top of the given storage backend. In demo mode the services
+// answer with synthetic, marked data where nothing can be computed.
+func New(store *repository.Store, demoMode bool, logger log.Logger) *Handlers {
 	logger.Info("Setting up server handlers...")
+	if demoMode {
+		logger.Warning("Demo mode is enabled; some responses will contain synthetic data")
+	}
+
 	// Initialize services
-	diseaseService := services.NewDiseaseService(db)
-	categoryService := services.NewCategoryService(db)
-	analyticsService := services.NewAnalyticsService(db)
-	aiService := services.NewAIService(db)
+	forecastService := services.NewForecastService(store.Diseases, store.Backtests)
+	correlationService := services.NewCorrelationService(store.Diseases, store.Environment)
+	environmentService := services.NewEnvironmentService(store.Environment)
+	populationService := services.NewPopulationService(store.Population, store.Diseases)
+	diseaseService := services.NewDiseaseService(
+		store.Diseases, forecastService, environmentService, populationService, demoMode,
+	)
+	demographicsService := services.NewDemographicsService(store.Stratified, store.Population)
+	mortalityService := services.NewMortalityService(store.Stratified, store.Population)
+	categoryService := services.NewCategoryService(store.Categories, store.Diseases)
+	standardizationService := services.NewStandardizationService(store.Stratified, store.Population)
+	vaccinationService := services.NewVaccinationService(store.Vaccination, store.Diseases, populationService)
+	capacityService := services.NewCapacityService(store.Capacity, store.Diseases, populationService)
+	disabilityService := services.NewDisabilityService(store.Stratified, store.Population)
+	analyticsService := services.NewAnalyticsService(
+		store.Diseases, forecastService, correlationService, standardizationService, vaccinationService, demoMode,
+	)
+	aiService := services.NewAIService(store.Diseases, demoMode)
 
 	// Initialize handlers
 	return &Handlers{
-		Disease:   disease.New(diseaseService),
-		Category:  category.New(categoryService),
-		Analytics: analytics.New(analyticsService),
-		AI:        ai.New(aiService),
-		Dashboard: dashboard.New(diseaseService),
-		System:    system.New(),
-		logger:    logger,
+		Disease:      disease.New(diseaseService),
+		Category:     category.New(categoryService),
+		Analytics:    analytics.New(analyticsService),
+		AI:           ai.New(aiService),
+		Dashboard:    dashboard.New(diseaseService, capacityService),
+		Environment:  environment.New(environmentService),
+		Population:   population.New(populationService),
+		Demographics: demographics.New(demographicsService),
+		Mortality:    mortality.New(mortalityService),
+		Capacity:     capacity.New(capacityService),
+		Disability:   disability.New(disabilityService),
+		System:       system.New(),
+		logger:       logger,
 	}
 }
@@ -0,0 +1,69 @@
+package mortality
+
+import (
+	"net/http"
+
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/common"
+	"github.com/ktruedat/healthisis/backend/internal/services"
+)
+
+// Handler handles requests for life tables and life expectancy
+type Handler struct {
+	service *services.MortalityService
+}
+
+// New creates a new mortality handler
+func New(service *services.MortalityService) *Handler {
+	return &Handler{service: service}
+}
+
+// LifeExpectancy handles GET /mortality/life-expectancy
+func (h *Handler) LifeExpectancy(w http.ResponseWriter, r *http.Request) {
+	filter, err := common.ParseStratifiedFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := h.service.LifeExpectancy(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"series": series})
+}
+
+// LifeTable handles GET /mortality/life-table
+func (h *Handler) LifeTable(w http.ResponseWriter, r *http.Request) {
+	query, err := common.ParseLifeTableQuery(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	table, err := h.service.LifeTable(r.Context(), query)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, table)
+}
+
+// Decomposition handles GET /mortality/decomposition
+func (h *Handler) Decomposition(w http.ResponseWriter, r *http.Request) {
+	query, err := common.ParseDecompositionQuery(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	decomposition, err := h.service.Decomposition(r.Context(), query)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, decomposition)
+}
@@ -0,0 +1,73 @@
+package population
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/server/handlers/common"
+	"github.com/ktruedat/healthisis/backend/internal/services"
+)
+
+// Handler handles population-related requests
+type Handler struct {
+	service *services.PopulationService
+}
+
+// New creates a new population handler
+func New(service *services.PopulationService) *Handler {
+	return &Handler{service: service}
+}
+
+// List handles GET /population
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	filter, err := common.ParsePopulationFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.service.Entries(r.Context(), filter)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"entries": entries})
+}
+
+// Denominators handles GET /population/denominators
+func (h *Handler) Denominators(w http.ResponseWriter, r *http.Request) {
+	filter, err := common.ParsePopulationFilter(r)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	denominators, err := h.service.Denominators(r.Context(), filter.StartYear, filter.EndYear)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, map[string]interface{}{"denominators": denominators})
+}
+
+// Revise handles PUT /population
+func (h *Handler) Revise(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Entries []models.PopulationEntry `json:"entries"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	revision, err := h.service.Revise(r.Context(), request.Entries)
+	if err != nil {
+		common.ErrorResponse(w, err.Error(), common.StatusFromError(err))
+		return
+	}
+
+	common.JSONResponse(w, http.StatusOK, revision)
+}
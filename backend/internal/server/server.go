@@ -10,8 +10,8 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/ktruedat/healthisis/backend/internal/config"
-	"github.com/ktruedat/healthisis/backend/internal/database"
 	"github.com/ktruedat/healthisis/backend/internal/pkg/log"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
 	"github.com/ktruedat/healthisis/backend/internal/server/handlers"
 )
 
@@ -25,13 +25,13 @@ type Server struct {
 }
 
 // New creates a new server instance
-func New(cfg *config.Config, db *database.DB, logger log.Logger) *Server {
+func New(cfg *config.Config, store *repository.Store, logger log.Logger) *Server {
 	logger.Info("Setting up server...")
 	// Set up router
 	r := chi.NewRouter()
 
 	// Set up all handlers
-	h := handlers.New(db, logger)
+	h := handlers.New(store, cfg.Demo.Enabled, logger)
 
 	// Create server
 	s := &Server{
@@ -98,12 +98,14 @@ func (s *Server) setupRoutes() {
 				"/diseases", func(r chi.Router) {
 					r.Get("/", s.handlers.Disease.List)
 					r.Post("/", s.handlers.Disease.Create)
+					r.Get("/paediatric", s.handlers.Disease.Paediatric)
 					r.Route(
 						"/{diseaseID}", func(r chi.Router) {
 							r.Get("/", s.handlers.Disease.Get)
 							r.Patch("/", s.handlers.Disease.Update)
 							r.Delete("/", s.handlers.Disease.Delete)
 							r.Post("/data", s.handlers.Disease.AddData)
+							r.Get("/compare", s.handlers.Disease.Compare)
 							r.Get("/predict", s.handlers.Disease.Predict)
 						},
 					)
@@ -134,11 +136,69 @@ func (s *Server) setupRoutes() {
 				},
 			)
 
+			// Environment
+			r.Route(
+				"/environment", func(r chi.Router) {
+					r.Get("/series", s.handlers.Environment.Series)
+					r.Get("/stations", s.handlers.Environment.Stations)
+					r.Get("/emissions", s.handlers.Environment.Emissions)
+				},
+			)
+
+			// Population
+			r.Route(
+				"/population", func(r chi.Router) {
+					r.Get("/", s.handlers.Population.List)
+					r.Put("/", s.handlers.Population.Revise)
+					r.Get("/denominators", s.handlers.Population.Denominators)
+				},
+			)
+
+			// Demographics
+			r.Route(
+				"/demographics", func(r chi.Router) {
+					r.Get("/pyramid", s.handlers.Demographics.Pyramid)
+					r.Get("/series", s.handlers.Demographics.Series)
+				},
+			)
+
+			// Mortality
+			r.Route(
+				"/mortality", func(r chi.Router) {
+					r.Get("/life-expectancy", s.handlers.Mortality.LifeExpectancy)
+					r.Get("/life-table", s.handlers.Mortality.LifeTable)
+					r.Get("/decomposition", s.handlers.Mortality.Decomposition)
+				},
+			)
+
+			// Capacity
+			r.Route(
+				"/capacity", func(r chi.Router) {
+					r.Get("/profiles", s.handlers.Capacity.Profiles)
+					r.Get("/beds", s.handlers.Capacity.Beds)
+					r.Get("/burden", s.handlers.Capacity.Burden)
+				},
+			)
+
+			// Disability
+			r.Route(
+				"/disability", func(r chi.Router) {
+					r.Get("/", s.handlers.Disability.List)
+					r.Get("/series", s.handlers.Disability.Series)
+				},
+			)
+
 			// Analytics
 			r.Route(
 				"/analytics", func(r chi.Router) {
 					r.Post("/correlation", s.handlers.Analytics.Correlation)
+					r.Post("/correlation/lagged", s.handlers.Analytics.LaggedCorrelation)
+					r.Post("/correlation/matrix", s.handlers.Analytics.CorrelationMatrix)
+					r.Post("/standardized-rates", s.handlers.Analytics.StandardizedRates)
+					r.Post("/vaccination", s.handlers.Analytics.Vaccination)
 					r.Post("/forecast", s.handlers.Analytics.Forecast)
+					r.Post("/forecast/backtest", s.handlers.Analytics.Backtest)
+					r.Get("/forecast/backtest", s.handlers.Analytics.BacktestResults)
 					r.Post("/query", s.handlers.Analytics.Query)
 				},
 			)
@@ -2,32 +2,26 @@ package services
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/demo"
 	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
 )
 
 // AIService handles AI and natural language query operations
 type AIService struct {
-	db *database.DB
+	diseases repository.DiseaseRepository
+	demoMode bool
 }
 
-// NewAIService creates a new AI service
-func NewAIService(db *database.DB) *AIService {
-	return &AIService{db: db}
+// NewAIService creates a new AI service; in demo mode it answers with synthetic data
+func NewAIService(diseases repository.DiseaseRepository, demoMode bool) *AIService {
+	return &AIService{diseases: diseases, demoMode: demoMode}
 }
 
 // ProcessQuery processes natural language queries
 func (s *AIService) ProcessQuery(ctx context.Context, query string) (*models.QueryResult, error) {
-	// Implementation to be added
-	return &models.QueryResult{
-		Answer: "The highest flu cases in 2023 occurred in Q1, with approximately 12,000 cases.",
-		Data: map[string]interface{}{
-			"disease": "Influenza",
-			"year":    2023,
-			"quarter": 1,
-			"cases":   12000,
-		},
-		Explanation: "This answer is based on disease records from Q1 2023 where influenza cases peaked.",
-	}, nil
+	err := fmt.Errorf("natural language queries: %w", models.ErrNotImplemented)
+	return orDemo(s.demoMode, err, demo.QueryResult)
 }
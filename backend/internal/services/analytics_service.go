@@ -2,114 +2,124 @@ package services
 
 import (
 	"context"
-	"strings"
+	"fmt"
+	"math"
 
-	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/demo"
 	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
 )
 
 // AnalyticsService handles analytics operations
 type AnalyticsService struct {
-	db *database.DB
+	diseases     repository.DiseaseRepository
+	forecasts    *ForecastService
+	correlations *CorrelationService
+	standardized *StandardizationService
+	vaccination  *VaccinationService
+	demoMode     bool
 }
 
-// NewAnalyticsService creates a new analytics service
-func NewAnalyticsService(db *database.DB) *AnalyticsService {
-	return &AnalyticsService{db: db}
+// NewAnalyticsService creates a new analytics service; in demo mode it answers with synthetic
+// data where nothing can be computed
+func NewAnalyticsService(
+	diseases repository.DiseaseRepository, forecasts *ForecastService, correlations *CorrelationService,
+	standardized *StandardizationService, vaccination *VaccinationService, demoMode bool,
+) *AnalyticsService {
+	return &AnalyticsService{
+		diseases:     diseases,
+		forecasts:    forecasts,
+		correlations: correlations,
+		standardized: standardized,
+		vaccination:  vaccination,
+		demoMode:     demoMode,
+	}
 }
 
-// AnalyzeCorrelation analyzes correlation between factors
+// AnalyzeCorrelation correlates two disease or environmental series within the request timeframe
 func (s *AnalyticsService) AnalyzeCorrelation(ctx context.Context, req *models.CorrelationRequest) (*models.CorrelationResult, error) {
-	// Implementation to be added
-	return &models.CorrelationResult{
-		CorrelationCoefficient: 0.75,
-		PValue:                 0.001,
-		ConfidenceInterval: models.ConfInterval{
-			Lower: 0.65,
-			Upper: 0.85,
-		},
-	}, nil
+	result, err := s.correlations.Correlate(ctx, req)
+	if err != nil {
+		return orDemo(s.demoMode, err, demo.Correlation)
+	}
+	return result, nil
 }
 
-// GetDiseaseForecast generates disease forecast
-func (s *AnalyticsService) GetDiseaseForecast(ctx context.Context, params map[string]interface{}) (interface{}, error) {
-	// Implementation to be added
-	return map[string]interface{}{
-		"forecast": []int{120, 145, 210, 180},
-		"years":    []int{2024},
-		"quarters": []int{1, 2, 3, 4},
-	}, nil
+// AnalyzeLaggedCorrelation computes the cross-correlation function of two series with factor1 leading
+func (s *AnalyticsService) AnalyzeLaggedCorrelation(ctx context.Context, req *models.CorrelationRequest) (*models.LaggedCorrelationResult, error) {
+	result, err := s.correlations.LaggedCorrelate(ctx, req)
+	if err != nil {
+		return orDemo(s.demoMode, err, demo.LaggedCorrelation)
+	}
+	return result, nil
 }
 
-// GetCorrelations generates correlations between diseases and factors
-func (s *AnalyticsService) GetCorrelations(ctx context.Context, params map[string]interface{}) (interface{}, error) {
-	// Implementation to be added
-	return map[string]interface{}{
-		"correlations": []map[string]interface{}{
-			{"factor1": "temperature", "factor2": "respiratory_diseases", "correlation": 0.75},
-			{"factor1": "humidity", "factor2": "respiratory_diseases", "correlation": 0.62},
-		},
-	}, nil
-}
+// GetDiseaseForecast forecasts the requested quarters of a year for a disease
+func (s *AnalyticsService) GetDiseaseForecast(ctx context.Context, req *models.ForecastRequest) (*models.ForecastResponse, error) {
+	quarters := req.Quarters
+	if len(quarters) == 0 {
+		quarters = []int{1, 2, 3, 4}
+	}
+	for _, q := range quarters {
+		if q < 1 || q > quartersPerYear {
+			return nil, fmt.Errorf("%w: quarter %d is not between 1 and 4", models.ErrInvalidFilter, q)
+		}
+	}
 
-// ProcessAnalyticsQuery handles analytics-oriented queries
-func (s *AnalyticsService) ProcessAnalyticsQuery(ctx context.Context, query string) (interface{}, error) {
-	// This is a simplified implementation that would ideally use more advanced analytics
-	// For now, we can parse the query for keywords and return mock data
-
-	// Convert query to lowercase for case-insensitive matching
-	query = strings.ToLower(query)
-
-	// Simple keyword-based logic for demo purposes
-	if strings.Contains(query, "correlation") || strings.Contains(query, "correlate") {
-		return map[string]interface{}{
-			"type": "correlation",
-			"data": map[string]interface{}{
-				"factor1":                 "temperature",
-				"factor2":                 "respiratory_diseases",
-				"correlation_coefficient": 0.72,
-				"significance":            "high",
-				"explanation":             "There is a strong correlation between temperature drops and increase in respiratory diseases.",
-			},
-		}, nil
+	prediction, err := s.forecasts.Predict(ctx, req.DiseaseID, req.Year, req.Model, req.Level)
+	if err != nil {
+		return orDemo(s.demoMode, err, func() *models.ForecastResponse { return demo.Forecast(req.DiseaseID, req.Year) })
 	}
 
-	if strings.Contains(query, "trend") || strings.Contains(query, "trending") {
-		return map[string]interface{}{
-			"type": "trend",
-			"data": map[string]interface{}{
-				"disease":            "Influenza",
-				"trend":              "increasing",
-				"change_percent":     12.5,
-				"period":             "last_quarter",
-				"visualization_data": []int{145, 167, 189, 210, 245},
-			},
-		}, nil
+	resp := &models.ForecastResponse{
+		DiseaseID: req.DiseaseID,
+		Year:      req.Year,
+		Model:     prediction.Model,
+		Level:     prediction.Level,
+		Quarters:  quarters,
+	}
+	for _, q := range quarters {
+		p := prediction.Quarters[q-1]
+		resp.Forecast = append(resp.Forecast, int(math.Round(p.PredictedCases)))
+		resp.ConfidenceIntervals = append(resp.ConfidenceIntervals, models.ConfInterval{Lower: p.Lower, Upper: p.Upper})
 	}
 
-	if strings.Contains(query, "forecast") || strings.Contains(query, "predict") {
-		return map[string]interface{}{
-			"type": "forecast",
-			"data": map[string]interface{}{
-				"disease":                 "COVID-19",
-				"next_quarter_prediction": 320,
-				"confidence_interval": map[string]float64{
-					"lower": 280.5,
-					"upper": 359.5,
-				},
-				"forecast_values": []int{320, 280, 250, 210},
-			},
-		}, nil
+	return resp, nil
+}
+
+// RunBacktest runs and stores rolling-origin forecast backtests
+func (s *AnalyticsService) RunBacktest(ctx context.Context, req *models.BacktestRequest) (*models.BacktestReport, error) {
+	return s.forecasts.Backtest(ctx, req)
+}
+
+// GetBacktestResults returns stored backtest results, optionally for one disease and model
+func (s *AnalyticsService) GetBacktestResults(ctx context.Context, diseaseID, model string) ([]models.BacktestResult, error) {
+	return s.forecasts.BacktestResults(ctx, diseaseID, model)
+}
+
+// GetCorrelations computes the correlation matrix of a set of diseases, categories and
+// environmental factors
+func (s *AnalyticsService) GetCorrelations(ctx context.Context, req *models.CorrelationMatrixRequest) (*models.CorrelationMatrix, error) {
+	matrix, err := s.correlations.Matrix(ctx, req)
+	if err != nil {
+		return orDemo(s.demoMode, err, demo.Correlations)
 	}
+	return matrix, nil
+}
 
-	// Default response for unrecognized queries
-	return map[string]interface{}{
-		"type": "general_stats",
-		"data": map[string]interface{}{
-			"most_prevalent_disease": "Influenza",
-			"highest_incidence":      "COVID-19",
-			"recent_trend":           "Respiratory infections showing seasonal increase",
-			"suggestion":             "Try querying about specific diseases, trends, or correlations.",
-		},
-	}, nil
+// GetStandardizedRates computes the directly age-standardized rates of a stratified indicator
+func (s *AnalyticsService) GetStandardizedRates(ctx context.Context, req *models.StandardizedRatesRequest) (*models.StandardizedRates, error) {
+	return s.standardized.Rates(ctx, req)
+}
+
+// AnalyzeVaccination reports vaccination coverage against its target and relates it to the
+// incidence of the diseases the vaccines protect against
+func (s *AnalyticsService) AnalyzeVaccination(ctx context.Context, req *models.VaccinationRequest) (*models.VaccinationAnalysis, error) {
+	return s.vaccination.Analyze(ctx, req)
+}
+
+// ProcessAnalyticsQuery handles analytics-oriented queries
+func (s *AnalyticsService) ProcessAnalyticsQuery(ctx context.Context, query string) (interface{}, error) {
+	err := fmt.Errorf("analytics queries: %w", models.ErrNotImplemented)
+	return orDemo(s.demoMode, err, func() demo.Payload { return demo.AnalyticsQuery(query) })
 }
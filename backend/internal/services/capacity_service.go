@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/pkg/stats"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+const (
+	// bedRatePer is the population base of bed rates
+	bedRatePer = 10000
+	// minCapacityYears is the fewest years a bed-case correlation is computed on
+	minCapacityYears = 5
+)
+
+// CapacityService reports hospital bed capacity against the population and the cases of the
+// disease categories each bed profile treats
+type CapacityService struct {
+	capacity   repository.CapacityRepository
+	diseases   repository.DiseaseRepository
+	population *PopulationService
+}
+
+// NewCapacityService creates a new CapacityService
+func NewCapacityService(
+	capacity repository.CapacityRepository, diseases repository.DiseaseRepository, population *PopulationService,
+) *CapacityService {
+	return &CapacityService{capacity: capacity, diseases: diseases, population: population}
+}
+
+// Profiles returns the bed profiles with the disease categories they treat
+func (s *CapacityService) Profiles(ctx context.Context) ([]models.BedProfile, error) {
+	profiles, err := s.capacity.Profiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing bed profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// Beds returns the yearly bed series of each profile matching the filter, followed by their total,
+// with the beds per 10k mid-year population
+func (s *CapacityService) Beds(ctx context.Context, filter models.CapacityFilter) ([]models.CapacitySeries, error) {
+	series, total, err := s.series(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return append(series, total), nil
+}
+
+// Burden relates the yearly beds of each profile matching the filter to the national cases of
+// the categories it treats, over the years both were reported. Without profiles in the filter,
+// every profile treating a category is reported.
+func (s *CapacityService) Burden(ctx context.Context, filter models.CapacityFilter) ([]models.CapacityBurden, error) {
+	profiles, err := s.profiles(ctx, filter.Profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filter.Profiles) == 0 {
+		for _, p := range profiles {
+			if len(p.Categories) > 0 {
+				filter.Profiles = append(filter.Profiles, p.Profile)
+			}
+		}
+		if len(filter.Profiles) == 0 {
+			return nil, fmt.Errorf("%w: no bed profile treats a disease category", models.ErrNotFound)
+		}
+	}
+	for _, p := range filter.Profiles {
+		if len(profiles[p].Categories) == 0 {
+			return nil, fmt.Errorf("%w: profiles: %q treats no disease category", models.ErrInvalidFilter, p)
+		}
+	}
+
+	series, _, err := s.series(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	burden := make([]models.CapacityBurden, 0, len(series))
+	for _, line := range series {
+		profile := profiles[line.Profile]
+		cases, err := s.yearlyCases(ctx, profile.Categories, filter)
+		if err != nil {
+			return nil, err
+		}
+		burden = append(burden, capacityBurden(profile, line.Points, cases))
+	}
+	return burden, nil
+}
+
+// Summary reports the beds of the latest year with bed counts up to endYear, or up to the latest
+// year when nil, and the latest burden on the beds of each profile treating a category
+func (s *CapacityService) Summary(ctx context.Context, endYear *int) (*models.CapacitySummary, error) {
+	filter := models.CapacityFilter{EndYear: endYear}
+	_, total, err := s.series(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.CapacitySummary{Burden: []models.ProfileLoad{}}
+	for i := len(total.Points) - 1; i >= 0; i-- {
+		if p := total.Points[i]; !p.Status.Suppressed() {
+			summary.Year, summary.Beds, summary.BedsPer10k = p.Year, p.Beds, p.BedsPer10k
+			break
+		}
+	}
+	if summary.Year == 0 {
+		return nil, fmt.Errorf("%w: no hospital beds reported", models.ErrNotFound)
+	}
+
+	// Beds of the other profiles are still summarised when none treating a category was reported
+	burden, err := s.Burden(ctx, filter)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return nil, err
+	}
+	for _, b := range burden {
+		if n := len(b.Points); n > 0 {
+			summary.Burden = append(summary.Burden, models.ProfileLoad{Profile: b.Profile, Name: b.Name, BurdenPoint: b.Points[n-1]})
+		}
+	}
+	return summary, nil
+}
+
+// profiles returns the bed profiles by profile, checking those selected exist
+func (s *CapacityService) profiles(ctx context.Context, selected []string) (map[string]models.BedProfile, error) {
+	list, err := s.Profiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make(map[string]models.BedProfile, len(list))
+	for _, p := range list {
+		profiles[p.Profile] = p
+	}
+	for _, p := range selected {
+		if _, ok := profiles[p]; !ok {
+			return nil, fmt.Errorf("%w: profiles: %q is not a bed profile", models.ErrInvalidFilter, p)
+		}
+	}
+	return profiles, nil
+}
+
+// series returns the yearly bed series of each profile matching the filter and their total
+func (s *CapacityService) series(ctx context.Context, filter models.CapacityFilter) ([]models.CapacitySeries, models.CapacitySeries, error) {
+	total := models.CapacitySeries{Profile: models.BedProfileTotal, Name: "All profiles", Points: []models.CapacityPoint{}}
+
+	profiles, err := s.profiles(ctx, filter.Profiles)
+	if err != nil {
+		return nil, total, err
+	}
+	beds, err := s.capacity.Beds(ctx, filter)
+	if err != nil {
+		return nil, total, fmt.Errorf("error listing hospital beds: %w", err)
+	}
+	if len(beds) == 0 {
+		return nil, total, fmt.Errorf("%w: no hospital beds", models.ErrNotFound)
+	}
+
+	denominators, err := s.population.Denominators(ctx, nil, nil)
+	if err != nil {
+		return nil, total, err
+	}
+	midYear := make(map[int]float64, len(denominators))
+	for _, d := range denominators {
+		midYear[d.Year] = d.MidYear
+	}
+
+	// Bed counts come ordered by profile and year
+	var series []models.CapacitySeries
+	totals := make(map[int]*models.CapacityPoint)
+	reported := make(map[int]int)
+	var years []int
+	for _, b := range beds {
+		if n := len(series); n == 0 || series[n-1].Profile != b.Profile {
+			series = append(series, models.CapacitySeries{Profile: b.Profile, Name: profiles[b.Profile].Name})
+		}
+		line := &series[len(series)-1]
+
+		year := int(b.Year)
+		p := models.CapacityPoint{Year: year, Beds: b.Beds, Status: b.Status}
+		p.Suppressed.Add(b.Status)
+		if !b.Status.Suppressed() {
+			p.BedsPer10k = bedRate(b.Beds, midYear[year])
+		}
+		line.Points = append(line.Points, p)
+
+		t, ok := totals[year]
+		if !ok {
+			t = &models.CapacityPoint{Year: year}
+			totals[year] = t
+			years = append(years, year)
+		}
+		t.Suppressed.Merge(p.Suppressed)
+		if !b.Status.Suppressed() {
+			t.Beds += b.Beds
+			reported[year]++
+		}
+	}
+
+	sort.Ints(years)
+	for _, year := range years {
+		t := totals[year]
+		t.Status = models.AggregateStatus(reported[year], uint64(t.Beds), t.Suppressed)
+		if reported[year] > 0 {
+			t.BedsPer10k = bedRate(t.Beds, midYear[year])
+		}
+		total.Points = append(total.Points, *t)
+	}
+	return series, total, nil
+}
+
+// yearCases are the national cases of a year over its reported cells
+type yearCases struct {
+	cases      uint64
+	suppressed models.SuppressedCells
+}
+
+// yearlyCases returns the national cases of the categories per year, over the years all four
+// quarters were reported
+func (s *CapacityService) yearlyCases(ctx context.Context, categories []string, filter models.CapacityFilter) (map[int]yearCases, error) {
+	points, err := s.diseases.TimeSeries(ctx, models.DiseaseFilter{
+		StartYear:  filter.StartYear,
+		EndYear:    filter.EndYear,
+		Regions:    []string{models.NationalRegion},
+		Categories: categories,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing cases: %w", err)
+	}
+
+	cases := make(map[int]yearCases)
+	quarters := make(map[int]map[int]bool)
+	for _, p := range points {
+		c := cases[p.Year]
+		c.cases += p.Cases
+		c.suppressed.Merge(p.Suppressed)
+		cases[p.Year] = c
+
+		if p.Reported() {
+			if quarters[p.Year] == nil {
+				quarters[p.Year] = make(map[int]bool)
+			}
+			quarters[p.Year][p.Quarter] = true
+		}
+	}
+	for year := range cases {
+		if len(quarters[year]) < quartersPerYear {
+			delete(cases, year)
+		}
+	}
+	return cases, nil
+}
+
+// capacityBurden relates the reported beds of a profile to the cases of the same year and
+// correlates the two over the years
+func capacityBurden(profile models.BedProfile, beds []models.CapacityPoint, cases map[int]yearCases) models.CapacityBurden {
+	burden := models.CapacityBurden{
+		Profile:    profile.Profile,
+		Name:       profile.Name,
+		Categories: profile.Categories,
+		Points:     []models.BurdenPoint{},
+	}
+
+	var xs, ys []float64
+	for _, b := range beds {
+		c, ok := cases[b.Year]
+		if !ok || b.Status.Suppressed() {
+			continue
+		}
+
+		p := models.BurdenPoint{Year: b.Year, Beds: b.Beds, BedsPer10k: b.BedsPer10k, Cases: c.cases, Suppressed: c.suppressed}
+		if b.Beds > 0 {
+			perBed := float64(c.cases) / float64(b.Beds)
+			p.CasesPerBed = &perBed
+		}
+		burden.Points = append(burden.Points, p)
+		xs = append(xs, float64(b.Beds))
+		ys = append(ys, float64(c.cases))
+	}
+
+	if len(xs) >= minCapacityYears {
+		if c := stats.Pearson(xs, ys, correlationLevel); !math.IsNaN(c.R) {
+			burden.Correlation = &models.BedCaseCorrelation{
+				Coefficient: c.R,
+				PValue:      c.P,
+				Lower:       c.Lower,
+				Upper:       c.Upper,
+				SampleSize:  c.N,
+			}
+		}
+	}
+	return burden
+}
+
+// bedRate returns beds per 10k of the population, nil without a population
+func bedRate(beds uint32, population float64) *float64 {
+	if population <= 0 {
+		return nil
+	}
+	rate := float64(beds) / population * bedRatePer
+	return &rate
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository/memory"
+)
+
+// capacityStore seeds the beds of three profiles from 2017 to 2022 for a population of 2,000,000,
+// 200 people per 10k: infectious-disease beds grow by 100 a year from 1,000, phthisiopneumology
+// keeps 500 and gynaecology 300 but for a confidential count in 2022. The flu cases of a quarter
+// grow by 10 a year from 100 and there are 50 cases of salmonellosis; the last quarter of 2022 is
+// not yet published.
+func capacityStore() *CapacityService {
+	profiles := []models.BedProfile{
+		{Profile: "Ftiziopneumologie", Name: "Phthisiopneumology", Categories: []string{"Respiratory Infections"}},
+		{Profile: "Ginecologie", Name: "Gynaecology", Categories: []string{}},
+		{Profile: "Infectioase", Name: "Infectious diseases", Categories: []string{"Intestinal Infections", "Respiratory Infections"}},
+	}
+
+	var beds []models.HospitalBeds
+	var diseases []models.Disease
+	var population []models.PopulationEntry
+	for year := uint16(2017); year <= 2022; year++ {
+		i := uint32(year - 2017)
+		gynaecology := models.HospitalBeds{Profile: "Ginecologie", Year: year, Beds: 300, Status: models.StatusObserved}
+		if year == 2022 {
+			gynaecology.Beds, gynaecology.Status = 0, models.StatusConfidential
+		}
+		beds = append(beds,
+			models.HospitalBeds{Profile: "Ftiziopneumologie", Year: year, Beds: 500, Status: models.StatusObserved},
+			gynaecology,
+			models.HospitalBeds{Profile: "Infectioase", Year: year, Beds: 1000 + 100*i, Status: models.StatusObserved},
+		)
+
+		for quarter := uint8(1); quarter <= 4 && (year < 2022 || quarter < 4); quarter++ {
+			diseases = append(diseases,
+				models.Disease{ID: fmt.Sprintf("salm_%d_%d", year, quarter), Name: "Salmoneloza",
+					Category: "Intestinal Infections", Region: models.NationalRegion, Year: year, Quarter: quarter,
+					Cases: 50, Status: models.StatusObserved},
+				models.Disease{ID: fmt.Sprintf("flu_%d_%d", year, quarter), Name: "Gripa",
+					Category: "Respiratory Infections", Region: models.NationalRegion, Year: year, Quarter: quarter,
+					Cases: 100 + 10*i, Status: models.StatusObserved},
+			)
+		}
+	}
+	for year := uint16(2017); year <= 2023; year++ {
+		population = append(population, models.PopulationEntry{Year: year, Age: models.AgeTotal,
+			Sex: models.SexMale, Medium: models.MediumUrban, Population: 2000000})
+	}
+
+	store := memory.NewStore(memory.Seed{BedProfiles: profiles, Beds: beds, Diseases: diseases, Population: population})
+	return NewCapacityService(store.Capacity, store.Diseases, NewPopulationService(store.Population, store.Diseases))
+}
+
+func TestBedsPer10k(t *testing.T) {
+	series, err := capacityStore().Beds(context.Background(), models.CapacityFilter{})
+	if err != nil {
+		t.Fatalf("Beds: %v", err)
+	}
+	if len(series) != 4 || series[3].Profile != models.BedProfileTotal {
+		t.Fatalf("series = %+v, want the three profiles and their total", series)
+	}
+	infectious, total := series[2], series[3]
+
+	if p := infectious.Points[2]; p.Year != 2019 || p.BedsPer10k == nil || !near(*p.BedsPer10k, 6, 1e-12) {
+		t.Errorf("infectious-disease beds = %+v, want 6 per 10k in 2019", p)
+	}
+	if p := series[1].Points[5]; p.Status != models.StatusConfidential || p.BedsPer10k != nil {
+		t.Errorf("gynaecology beds = %+v, want a confidential count in 2022 without a rate", p)
+	}
+
+	// The total of 2022 leaves out the confidential gynaecology beds and counts them as such
+	p := total.Points[5]
+	if p.Year != 2022 || p.Beds != 2000 || p.Status != models.StatusObserved || p.Suppressed.Confidential != 1 {
+		t.Errorf("2022 total = %+v, want 2,000 observed beds and a confidential count", p)
+	}
+	if p.BedsPer10k == nil || !near(*p.BedsPer10k, 10, 1e-12) {
+		t.Errorf("2022 total = %v per 10k, want 10", p.BedsPer10k)
+	}
+}
+
+func TestBurdenByProfile(t *testing.T) {
+	service := capacityStore()
+	burden, err := service.Burden(context.Background(), models.CapacityFilter{})
+	if err != nil {
+		t.Fatalf("Burden: %v", err)
+	}
+
+	// Gynaecology treats no category; 2022 lacks a quarter
+	if len(burden) != 2 || burden[0].Profile != "Ftiziopneumologie" || burden[1].Profile != "Infectioase" {
+		t.Fatalf("burden = %+v, want phthisiopneumology and infectious diseases", burden)
+	}
+	for _, b := range burden {
+		if n := len(b.Points); n != 5 || b.Points[0].Year != 2017 || b.Points[n-1].Year != 2021 {
+			t.Errorf("%s: points = %+v, want 2017 to 2021", b.Profile, b.Points)
+		}
+	}
+
+	// Phthisiopneumology bears the flu alone: 400 cases in 2017 on 500 beds. Their number never
+	// changes, so they do not correlate with the cases.
+	respiratory := burden[0]
+	if p := respiratory.Points[0]; p.Cases != 400 || p.CasesPerBed == nil || !near(*p.CasesPerBed, 0.8, 1e-12) {
+		t.Errorf("phthisiopneumology 2017 = %+v, want 400 cases, 0.8 a bed", p)
+	}
+	if respiratory.Correlation != nil {
+		t.Errorf("phthisiopneumology correlation = %+v, want none for a constant number of beds", *respiratory.Correlation)
+	}
+
+	// Infectious-disease beds bear the flu and salmonellosis, 600 cases growing by 40 a year against
+	// 1,000 beds growing by 100, a perfect correlation
+	infectious := burden[1]
+	if p := infectious.Points[4]; p.Cases != 760 || p.Beds != 1400 || !near(*p.CasesPerBed, 760./1400, 1e-12) {
+		t.Errorf("infectious diseases 2021 = %+v, want 760 cases on 1,400 beds", p)
+	}
+	if c := infectious.Correlation; c == nil || c.SampleSize != 5 || !near(c.Coefficient, 1, 1e-12) {
+		t.Errorf("infectious diseases correlation = %+v, want 1 over 5 years", c)
+	}
+
+	if _, err := service.Burden(context.Background(), models.CapacityFilter{Profiles: []string{"Ginecologie"}}); !errors.Is(err, models.ErrInvalidFilter) {
+		t.Errorf("gynaecology: error = %v, want ErrInvalidFilter for a profile treating no category", err)
+	}
+}
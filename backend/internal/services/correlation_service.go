@@ -0,0 +1,785 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/pkg/stats"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+const (
+	// minCorrelationPeriods is the fewest overlapping quarters a correlation is computed on
+	minCorrelationPeriods = 8
+	// minAnnualCorrelationPeriods is the fewest overlapping years a correlation with a yearly
+	// exposure is computed on
+	minAnnualCorrelationPeriods = 5
+	// correlationLevel is the coverage of the reported confidence intervals and significance bands
+	correlationLevel = 0.95
+	monthsPerQuarter = 3
+	monthsPerYear    = 12
+)
+
+// Lag limits of the lagged correlation analysis, per lag unit
+var (
+	defaultMaxLag = map[string]int{models.LagQuarter: 4, models.LagMonth: 12}
+	maxMaxLag     = map[string]int{models.LagQuarter: 12, models.LagMonth: 36}
+)
+
+// Series name prefixes that force how a correlation factor is resolved
+const (
+	diseaseSeriesPrefix     = "disease:"
+	categorySeriesPrefix    = "category:"
+	environmentSeriesPrefix = "environment:"
+	emissionSeriesPrefix    = "emission:"
+)
+
+// maxMatrixSeries caps the size of a correlation matrix
+const maxMatrixSeries = 40
+
+// CorrelationService correlates quarterly disease and environmental series, and the yearly
+// emissions of pollutants with the years of the others
+type CorrelationService struct {
+	diseases    repository.DiseaseRepository
+	environment repository.EnvironmentRepository
+}
+
+// NewCorrelationService creates a new CorrelationService
+func NewCorrelationService(diseases repository.DiseaseRepository, environment repository.EnvironmentRepository) *CorrelationService {
+	return &CorrelationService{diseases: diseases, environment: environment}
+}
+
+// quarterlyValues is a named series keyed by quarterIndex, or by year when annual
+type quarterlyValues struct {
+	Name        string
+	Values      map[int]float64
+	Annual      bool
+	Aggregation string // how quarters roll up into years, models.AggregateSum or models.AggregateMean
+}
+
+// Correlate computes the Pearson and Spearman correlations of two series over the quarters of the
+// timeframe at which both were observed. When either is a yearly emission series, the other is
+// rolled up into its complete years and the years are correlated.
+func (s *CorrelationService) Correlate(ctx context.Context, req *models.CorrelationRequest) (*models.CorrelationResult, error) {
+	first, last, err := timeframeQuarters(req.Timeframe)
+	if err != nil {
+		return nil, err
+	}
+
+	x, err := s.Series(ctx, req.Factor1, first, last)
+	if err != nil {
+		return nil, err
+	}
+	y, err := s.Series(ctx, req.Factor2, first, last)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity := models.GranularityQuarter
+	minPeriods := minCorrelationPeriods
+	if x.Annual || y.Annual {
+		x, y = annualSeries(x), annualSeries(y)
+		granularity, minPeriods = models.GranularityYear, minAnnualCorrelationPeriods
+	}
+
+	points := alignSeries(x, y)
+	if len(points) < minPeriods {
+		return nil, fmt.Errorf("%w: %s and %s overlap in %d %ss, at least %d are needed",
+			models.ErrInsufficientData, x.Name, y.Name, len(points), granularity, minPeriods)
+	}
+
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i], ys[i] = p.X, p.Y
+	}
+
+	pearson := stats.Pearson(xs, ys, correlationLevel)
+	if math.IsNaN(pearson.R) {
+		return nil, fmt.Errorf("%w: %s or %s is constant over the timeframe", models.ErrInsufficientData, x.Name, y.Name)
+	}
+	spearman := stats.Spearman(xs, ys, correlationLevel)
+
+	result := &models.CorrelationResult{
+		Factor1:           x.Name,
+		Factor2:           y.Name,
+		Pearson:           correlationStat(pearson),
+		Spearman:          correlationStat(spearman),
+		SampleSize:        len(points),
+		Level:             correlationLevel,
+		Granularity:       granularity,
+		VisualizationData: points,
+	}
+	result.CorrelationCoefficient = result.Pearson.Coefficient
+	result.PValue = result.Pearson.PValue
+	result.ConfidenceInterval = result.Pearson.ConfidenceInterval
+
+	return result, nil
+}
+
+// LaggedCorrelate computes the cross-correlation of two series for factor1 leading factor2 by
+// 0..MaxLag quarters or months. Month lags shift the monthly values of factor1 before rolling
+// them up into quarters, so factor1 must be an environmental factor with monthly data. The prewhitened
+// variant seasonally differences both series and filters them with an autoregression fitted to
+// the differenced factor1, which removes the shared seasonality and autocorrelation that produce
+// spurious peaks in the raw function.
+func (s *CorrelationService) LaggedCorrelate(ctx context.Context, req *models.CorrelationRequest) (*models.LaggedCorrelationResult, error) {
+	first, last, err := timeframeQuarters(req.Timeframe)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := req.LagUnit
+	if unit == "" {
+		unit = models.LagQuarter
+	}
+	if _, ok := maxMaxLag[unit]; !ok {
+		return nil, fmt.Errorf("%w: lag_unit must be %q or %q", models.ErrInvalidFilter, models.LagQuarter, models.LagMonth)
+	}
+	maxLag := req.MaxLag
+	if maxLag == 0 {
+		maxLag = defaultMaxLag[unit]
+	}
+	if maxLag < 0 || maxLag > maxMaxLag[unit] {
+		return nil, fmt.Errorf("%w: max_lag must be between 0 and %d %ss", models.ErrInvalidFilter, maxMaxLag[unit], unit)
+	}
+
+	y, err := s.Series(ctx, req.Factor2, first, last)
+	if err != nil {
+		return nil, err
+	}
+	if y.Annual {
+		return nil, fmt.Errorf("%w: %s is a yearly exposure without quarterly lags", models.ErrInvalidFilter, y.Name)
+	}
+
+	// lagged[k] is factor1 shifted forward by k units; it reaches back before the timeframe so the
+	// prewhitening filter has history, and aligning with factor2 confines it to the timeframe
+	var lagged []*quarterlyValues
+	if unit == models.LagMonth {
+		lagged, err = s.monthLagged(ctx, req.Factor1, first, last, maxLag)
+	} else {
+		lagged, err = s.quarterLagged(ctx, req.Factor1, first, last, maxLag)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Fit the prewhitening filter to the longest gap-free run of the differenced unlagged factor
+	run := longestRun(seasonalDifference(lagged[0]))
+	if len(run) < minCorrelationPeriods {
+		return nil, fmt.Errorf("%w: %s has no run of %d consecutive quarters after seasonal differencing",
+			models.ErrInsufficientData, lagged[0].Name, minCorrelationPeriods)
+	}
+	filter := stats.FitAR(run, 2*quartersPerYear)
+
+	result := &models.LaggedCorrelationResult{
+		Factor1:        lagged[0].Name,
+		Factor2:        y.Name,
+		LagUnit:        unit,
+		MaxLag:         maxLag,
+		Level:          correlationLevel,
+		Raw:            models.CrossCorrelation{Lags: []models.LagCorrelation{}},
+		Prewhitened:    models.CrossCorrelation{Lags: []models.LagCorrelation{}},
+		PrewhitenOrder: len(filter.Coeffs),
+	}
+
+	whiteY := prewhiten(seasonalDifference(y), filter)
+	for k, x := range lagged {
+		if c, ok := lagCorrelation(k, x, y); ok {
+			result.Raw.Lags = append(result.Raw.Lags, c)
+		}
+		if c, ok := lagCorrelation(k, prewhiten(seasonalDifference(x), filter), whiteY); ok {
+			result.Prewhitened.Lags = append(result.Prewhitened.Lags, c)
+		}
+	}
+	if len(result.Raw.Lags) == 0 {
+		return nil, fmt.Errorf("%w: %s and %s overlap in fewer than %d quarters at every lag",
+			models.ErrInsufficientData, result.Factor1, result.Factor2, minCorrelationPeriods)
+	}
+
+	result.Raw.BestLag = bestLag(result.Raw.Lags)
+	result.Prewhitened.BestLag = bestLag(result.Prewhitened.Lags)
+
+	return result, nil
+}
+
+// quarterLagged returns factor1 shifted by 0..maxLag quarters
+func (s *CorrelationService) quarterLagged(ctx context.Context, name string, first, last, maxLag int) ([]*quarterlyValues, error) {
+	x, err := s.Series(ctx, name, first-maxLag, last)
+	if err != nil {
+		return nil, err
+	}
+	if x.Annual {
+		return nil, fmt.Errorf("%w: %s is a yearly exposure without quarterly lags", models.ErrInvalidFilter, x.Name)
+	}
+
+	lagged := make([]*quarterlyValues, maxLag+1)
+	for k := range lagged {
+		shifted := &quarterlyValues{Name: x.Name, Values: make(map[int]float64, len(x.Values))}
+		for idx, v := range x.Values {
+			shifted.Values[idx+k] = v
+		}
+		lagged[k] = shifted
+	}
+	return lagged, nil
+}
+
+// monthLagged returns the quarterly rollups of the monthly values of an environmental factor
+// shifted by 0..maxLag months; quarters missing any of their three months are left out
+func (s *CorrelationService) monthLagged(ctx context.Context, name string, first, last, maxLag int) ([]*quarterlyValues, error) {
+	factor := strings.TrimPrefix(name, environmentSeriesPrefix)
+	firstMonth := first*monthsPerQuarter - maxLag
+	startYear, endYear := firstMonth/monthsPerYear, last/quartersPerYear
+
+	points, err := s.environment.Monthly(ctx, factor, &startYear, &endYear)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("%w: no monthly observations of %q", models.ErrInsufficientData, name)
+	}
+
+	months := make(map[int]float64, len(points))
+	for _, p := range points {
+		months[p.Year*monthsPerYear+p.Month-1] = p.Value
+	}
+
+	lagged := make([]*quarterlyValues, maxLag+1)
+	for k := range lagged {
+		series := &quarterlyValues{Name: environmentSeriesPrefix + factor, Values: make(map[int]float64)}
+		for idx := firstMonth / monthsPerQuarter; idx <= last; idx++ {
+			var sum float64
+			complete := true
+			for m := idx*monthsPerQuarter - k; m < (idx+1)*monthsPerQuarter-k; m++ {
+				v, ok := months[m]
+				if !ok {
+					complete = false
+					break
+				}
+				sum += v
+			}
+			if !complete {
+				continue
+			}
+			if models.VariableAggregation(factor) == models.AggregateSum {
+				series.Values[idx] = sum
+			} else {
+				series.Values[idx] = sum / monthsPerQuarter
+			}
+		}
+		lagged[k] = series
+	}
+	return lagged, nil
+}
+
+// lagCorrelation correlates two series at their common quarters; ok is false when they overlap
+// too little or one of them is constant there
+func lagCorrelation(lag int, x, y *quarterlyValues) (models.LagCorrelation, bool) {
+	points := alignSeries(x, y)
+	if len(points) < minCorrelationPeriods {
+		return models.LagCorrelation{}, false
+	}
+
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i], ys[i] = p.X, p.Y
+	}
+	return pairCorrelation(lag, xs, ys)
+}
+
+// pairCorrelation correlates paired values at a lag; ok is false when one of them is constant
+func pairCorrelation(lag int, xs, ys []float64) (models.LagCorrelation, bool) {
+	c := stats.Pearson(xs, ys, correlationLevel)
+	if math.IsNaN(c.R) {
+		return models.LagCorrelation{}, false
+	}
+
+	band := stats.NormalQuantile(0.5+correlationLevel/2) / math.Sqrt(float64(len(xs)))
+	return models.LagCorrelation{
+		Lag:         lag,
+		Coefficient: c.R,
+		SampleSize:  len(xs),
+		Band:        band,
+		Significant: math.Abs(c.R) > band,
+	}, true
+}
+
+func bestLag(lags []models.LagCorrelation) *models.LagCorrelation {
+	if len(lags) == 0 {
+		return nil
+	}
+	best := lags[0]
+	for _, c := range lags[1:] {
+		if math.Abs(c.Coefficient) > math.Abs(best.Coefficient) {
+			best = c
+		}
+	}
+	return &best
+}
+
+// seasonalDifference replaces each value by its change since the same quarter a year earlier
+func seasonalDifference(v *quarterlyValues) *quarterlyValues {
+	out := &quarterlyValues{Name: v.Name, Values: make(map[int]float64, len(v.Values))}
+	for idx, x := range v.Values {
+		if prev, ok := v.Values[idx-quartersPerYear]; ok {
+			out.Values[idx] = x - prev
+		}
+	}
+	return out
+}
+
+// prewhiten replaces each value by its one-step prediction error under the autoregression;
+// values without all of their predecessors are dropped
+func prewhiten(v *quarterlyValues, m stats.AR) *quarterlyValues {
+	out := &quarterlyValues{Name: v.Name, Values: make(map[int]float64, len(v.Values))}
+	for idx, x := range v.Values {
+		e := x - m.Mean
+		complete := true
+		for i, phi := range m.Coeffs {
+			prev, ok := v.Values[idx-1-i]
+			if !ok {
+				complete = false
+				break
+			}
+			e -= phi * (prev - m.Mean)
+		}
+		if complete {
+			out.Values[idx] = e
+		}
+	}
+	return out
+}
+
+// longestRun returns the values of the longest run of consecutive quarters in time order
+func longestRun(v *quarterlyValues) []float64 {
+	indices := make([]int, 0, len(v.Values))
+	for idx := range v.Values {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+
+	bestStart, bestLen := 0, 0
+	for start := 0; start < len(indices); {
+		end := start + 1
+		for end < len(indices) && indices[end] == indices[end-1]+1 {
+			end++
+		}
+		if end-start > bestLen {
+			bestStart, bestLen = start, end-start
+		}
+		start = end
+	}
+
+	run := make([]float64, bestLen)
+	for i := range run {
+		run[i] = v.Values[indices[bestStart+i]]
+	}
+	return run
+}
+
+// Matrix computes the pairwise correlations of the requested disease, category and environmental
+// series over the quarters each pair has in common. With a yearly emission series among them, every
+// series is rolled up into its complete years and the years are correlated.
+func (s *CorrelationService) Matrix(ctx context.Context, req *models.CorrelationMatrixRequest) (*models.CorrelationMatrix, error) {
+	method := req.Method
+	if method == "" {
+		method = models.CorrelationPearson
+	}
+	if method != models.CorrelationPearson && method != models.CorrelationSpearman {
+		return nil, fmt.Errorf("%w: method must be %q or %q", models.ErrInvalidFilter, models.CorrelationPearson, models.CorrelationSpearman)
+	}
+
+	n := len(req.Diseases) + len(req.Categories) + len(req.Factors)
+	if n < 2 || n > maxMatrixSeries {
+		return nil, fmt.Errorf("%w: a correlation matrix needs between 2 and %d series, got %d", models.ErrInvalidFilter, maxMatrixSeries, n)
+	}
+
+	// The timeframe is optional here; without one every observed quarter is used
+	first, last := 0, quarterIndex(9999, quartersPerYear)
+	if !req.Timeframe.StartDate.IsZero() || !req.Timeframe.EndDate.IsZero() {
+		var err error
+		if first, last, err = timeframeQuarters(req.Timeframe); err != nil {
+			return nil, err
+		}
+	}
+
+	series := make([]*quarterlyValues, 0, n)
+	for _, id := range req.Diseases {
+		v, err := s.diseaseSeries(ctx, id, first, last)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, v)
+	}
+	for _, category := range req.Categories {
+		v, err := s.categorySeries(ctx, category, first, last)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, v)
+	}
+	annual := false
+	for _, factor := range req.Factors {
+		var v *quarterlyValues
+		var err error
+		if strings.HasPrefix(factor, emissionSeriesPrefix) {
+			v, err = s.emissionSeries(ctx, strings.TrimPrefix(factor, emissionSeriesPrefix), first, last)
+		} else {
+			v, err = s.environmentSeries(ctx, strings.TrimPrefix(factor, environmentSeriesPrefix), first, last)
+		}
+		if err != nil {
+			return nil, err
+		}
+		annual = annual || v.Annual
+		series = append(series, v)
+	}
+
+	granularity := models.GranularityQuarter
+	minPeriods := minCorrelationPeriods
+	if annual {
+		for i, v := range series {
+			series[i] = annualSeries(v)
+		}
+		granularity, minPeriods = models.GranularityYear, minAnnualCorrelationPeriods
+	}
+
+	coefficients := make([][]*float64, n)
+	pValues := make([][]*float64, n)
+	sizes := make([][]int, n)
+	for i := range coefficients {
+		coefficients[i] = make([]*float64, n)
+		pValues[i] = make([]*float64, n)
+		sizes[i] = make([]int, n)
+	}
+
+	correlate := stats.Pearson
+	if method == models.CorrelationSpearman {
+		correlate = stats.Spearman
+	}
+
+	// Correlate each pair once and collect the off-diagonal p-values as one family
+	type pair struct{ i, j int }
+	var pairs []pair
+	var family []float64
+	for i := 0; i < n; i++ {
+		one := 1.0
+		coefficients[i][i] = &one
+		sizes[i][i] = len(series[i].Values)
+
+		for j := i + 1; j < n; j++ {
+			points := alignSeries(series[i], series[j])
+			sizes[i][j], sizes[j][i] = len(points), len(points)
+			if len(points) < minPeriods {
+				continue
+			}
+
+			xs := make([]float64, len(points))
+			ys := make([]float64, len(points))
+			for k, p := range points {
+				xs[k], ys[k] = p.X, p.Y
+			}
+			c := correlate(xs, ys, correlationLevel)
+			if math.IsNaN(c.R) {
+				continue
+			}
+
+			r, p := c.R, c.P
+			coefficients[i][j], coefficients[j][i] = &r, &r
+			pValues[i][j], pValues[j][i] = &p, &p
+			pairs = append(pairs, pair{i, j})
+			family = append(family, p)
+		}
+	}
+
+	adjustedPValues := make([][]*float64, n)
+	for i := range adjustedPValues {
+		adjustedPValues[i] = make([]*float64, n)
+	}
+	for k, q := range stats.BenjaminiHochberg(family) {
+		q := q
+		adjustedPValues[pairs[k].i][pairs[k].j], adjustedPValues[pairs[k].j][pairs[k].i] = &q, &q
+	}
+
+	matrix := &models.CorrelationMatrix{
+		Series:          make([]string, n),
+		Method:          method,
+		Granularity:     granularity,
+		Coefficients:    coefficients,
+		PValues:         pValues,
+		AdjustedPValues: adjustedPValues,
+		SampleSizes:     sizes,
+	}
+	for i, v := range series {
+		matrix.Series[i] = v.Name
+	}
+
+	if req.Cluster {
+		reorderMatrix(matrix, stats.ClusterOrder(correlationDistances(coefficients)))
+		matrix.Clustered = true
+	}
+
+	return matrix, nil
+}
+
+// correlationDistances turns coefficients into the distances 1-|r|; pairs without a coefficient
+// are treated as unrelated
+func correlationDistances(coefficients [][]*float64) [][]float64 {
+	dist := make([][]float64, len(coefficients))
+	for i, row := range coefficients {
+		dist[i] = make([]float64, len(row))
+		for j, r := range row {
+			dist[i][j] = 1
+			if r != nil {
+				dist[i][j] = 1 - math.Abs(*r)
+			}
+		}
+	}
+	return dist
+}
+
+// reorderMatrix permutes the series and every cell of the matrix into the given order
+func reorderMatrix(m *models.CorrelationMatrix, order []int) {
+	permute := func(cells [][]*float64) [][]*float64 {
+		out := make([][]*float64, len(order))
+		for i, oi := range order {
+			out[i] = make([]*float64, len(order))
+			for j, oj := range order {
+				out[i][j] = cells[oi][oj]
+			}
+		}
+		return out
+	}
+
+	series := make([]string, len(order))
+	sizes := make([][]int, len(order))
+	for i, oi := range order {
+		series[i] = m.Series[oi]
+		sizes[i] = make([]int, len(order))
+		for j, oj := range order {
+			sizes[i][j] = m.SampleSizes[oi][oj]
+		}
+	}
+
+	m.Series = series
+	m.SampleSizes = sizes
+	m.Coefficients = permute(m.Coefficients)
+	m.PValues = permute(m.PValues)
+	m.AdjustedPValues = permute(m.AdjustedPValues)
+}
+
+// Series resolves a factor name to its quarterly values between two quarter indices, or to the
+// yearly values of a pollutant's emissions. Names with an "environment:", "emission:", "category:"
+// or "disease:" prefix are looked up only there; other names are tried as an environmental factor
+// first, then as a pollutant and then as a disease ID or name.
+func (s *CorrelationService) Series(ctx context.Context, name string, first, last int) (*quarterlyValues, error) {
+	switch {
+	case strings.HasPrefix(name, emissionSeriesPrefix):
+		return s.emissionSeries(ctx, strings.TrimPrefix(name, emissionSeriesPrefix), first, last)
+	case strings.HasPrefix(name, categorySeriesPrefix):
+		return s.categorySeries(ctx, strings.TrimPrefix(name, categorySeriesPrefix), first, last)
+	case strings.HasPrefix(name, environmentSeriesPrefix):
+		return s.environmentSeries(ctx, strings.TrimPrefix(name, environmentSeriesPrefix), first, last)
+	case strings.HasPrefix(name, diseaseSeriesPrefix):
+		return s.diseaseSeries(ctx, strings.TrimPrefix(name, diseaseSeriesPrefix), first, last)
+	}
+
+	factors, err := s.environment.Factors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range factors {
+		if f == name {
+			return s.environmentSeries(ctx, name, first, last)
+		}
+	}
+	for _, p := range models.Pollutants {
+		if p == name {
+			return s.emissionSeries(ctx, name, first, last)
+		}
+	}
+
+	return s.diseaseSeries(ctx, name, first, last)
+}
+
+func (s *CorrelationService) environmentSeries(ctx context.Context, factor string, first, last int) (*quarterlyValues, error) {
+	startYear, endYear := first/quartersPerYear, last/quartersPerYear
+	points, err := s.environment.Quarterly(ctx, factor, &startYear, &endYear)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("environmental factor %q: %w", factor, models.ErrNotFound)
+	}
+
+	series := &quarterlyValues{
+		Name:        environmentSeriesPrefix + factor,
+		Values:      make(map[int]float64),
+		Aggregation: models.VariableAggregation(factor),
+	}
+	for _, p := range points {
+		if idx := quarterIndex(p.Year, p.Quarter); idx >= first && idx <= last {
+			series.Values[idx] = p.Value
+		}
+	}
+	return series, nil
+}
+
+func (s *CorrelationService) diseaseSeries(ctx context.Context, id string, first, last int) (*quarterlyValues, error) {
+	name, err := resolveDiseaseName(ctx, s.diseases, id)
+	if err != nil {
+		return nil, err
+	}
+
+	startYear, endYear := first/quartersPerYear, last/quartersPerYear
+	points, err := s.diseases.TimeSeries(ctx, models.DiseaseFilter{Names: []string{name}, StartYear: &startYear, EndYear: &endYear})
+	if err != nil {
+		return nil, err
+	}
+
+	series := &quarterlyValues{Name: diseaseSeriesPrefix + name, Values: make(map[int]float64), Aggregation: models.AggregateSum}
+	for _, p := range reportedPoints(points) {
+		if idx := quarterIndex(p.Year, p.Quarter); idx >= first && idx <= last {
+			series.Values[idx] = float64(p.Cases)
+		}
+	}
+	return series, nil
+}
+
+// categorySeries sums the cases of the diseases of a category per quarter; quarters where any
+// disease was suppressed are left out rather than undercounted
+func (s *CorrelationService) categorySeries(ctx context.Context, category string, first, last int) (*quarterlyValues, error) {
+	startYear, endYear := first/quartersPerYear, last/quartersPerYear
+	points, err := s.diseases.TimeSeries(ctx, models.DiseaseFilter{Categories: []string{category}, StartYear: &startYear, EndYear: &endYear})
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("category %q: %w", category, models.ErrNotFound)
+	}
+
+	series := &quarterlyValues{Name: categorySeriesPrefix + category, Values: make(map[int]float64), Aggregation: models.AggregateSum}
+	suppressed := make(map[int]bool)
+	for _, p := range points {
+		idx := quarterIndex(p.Year, p.Quarter)
+		if idx < first || idx > last {
+			continue
+		}
+		if p.Suppressed.Total() > 0 {
+			suppressed[idx] = true
+		}
+		series.Values[idx] += float64(p.Cases)
+	}
+	for idx := range suppressed {
+		delete(series.Values, idx)
+	}
+	return series, nil
+}
+
+// emissionSeries returns the yearly emissions of a pollutant over the years of two quarter
+// indices; years whose emission was withheld are left out
+func (s *CorrelationService) emissionSeries(ctx context.Context, pollutant string, first, last int) (*quarterlyValues, error) {
+	startYear, endYear := first/quartersPerYear, last/quartersPerYear
+	emissions, err := s.environment.Emissions(ctx, models.EmissionFilter{
+		Pollutants: []string{pollutant},
+		StartYear:  &startYear,
+		EndYear:    &endYear,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(emissions) == 0 {
+		return nil, fmt.Errorf("pollutant %q: %w", pollutant, models.ErrNotFound)
+	}
+
+	series := &quarterlyValues{
+		Name:        emissionSeriesPrefix + pollutant,
+		Values:      make(map[int]float64),
+		Annual:      true,
+		Aggregation: models.AggregateSum,
+	}
+	for _, e := range emissions {
+		if !e.Status.Suppressed() {
+			series.Values[int(e.Year)] = e.Value
+		}
+	}
+	return series, nil
+}
+
+// annualSeries rolls a quarterly series up into the years all four of its quarters were observed,
+// summing or averaging them as its aggregation prescribes; annual series are returned as they are
+func annualSeries(v *quarterlyValues) *quarterlyValues {
+	if v.Annual {
+		return v
+	}
+
+	sums := make(map[int]float64)
+	quarters := make(map[int]int)
+	for idx, x := range v.Values {
+		sums[idx/quartersPerYear] += x
+		quarters[idx/quartersPerYear]++
+	}
+
+	out := &quarterlyValues{Name: v.Name, Values: make(map[int]float64, len(sums)), Annual: true, Aggregation: v.Aggregation}
+	for year, n := range quarters {
+		if n < quartersPerYear {
+			continue
+		}
+		if v.Aggregation == models.AggregateSum {
+			out.Values[year] = sums[year]
+		} else {
+			out.Values[year] = sums[year] / quartersPerYear
+		}
+	}
+	return out
+}
+
+// alignSeries pairs the values of two series at the quarters, or the years of annual series, both
+// were observed, in time order
+func alignSeries(x, y *quarterlyValues) []models.ScatterPoint {
+	var points []models.ScatterPoint
+	for idx, xv := range x.Values {
+		yv, ok := y.Values[idx]
+		switch {
+		case !ok:
+		case x.Annual:
+			points = append(points, models.ScatterPoint{Year: idx, X: xv, Y: yv})
+		default:
+			points = append(points, models.ScatterPoint{
+				Year: idx / quartersPerYear, Quarter: idx%quartersPerYear + 1, X: xv, Y: yv,
+			})
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return quarterIndex(points[i].Year, points[i].Quarter) < quarterIndex(points[j].Year, points[j].Quarter)
+	})
+	return points
+}
+
+// timeframeQuarters returns the quarter indices of the quarters holding the start and end dates
+func timeframeQuarters(tf models.TimeframeFilter) (int, int, error) {
+	if tf.StartDate.IsZero() || tf.EndDate.IsZero() {
+		return 0, 0, fmt.Errorf("%w: timeframe start_date and end_date are required", models.ErrInvalidFilter)
+	}
+	if tf.EndDate.Before(tf.StartDate) {
+		return 0, 0, fmt.Errorf("%w: timeframe ends before it starts", models.ErrInvalidFilter)
+	}
+
+	index := func(t time.Time) int {
+		return quarterIndex(t.Year(), (int(t.Month())-1)/3+1)
+	}
+	return index(tf.StartDate), index(tf.EndDate), nil
+}
+
+func correlationStat(c stats.Correlation) *models.CorrelationStat {
+	return &models.CorrelationStat{
+		Coefficient:        c.R,
+		PValue:             c.P,
+		ConfidenceInterval: models.ConfInterval{Lower: c.Lower, Upper: c.Upper},
+	}
+}
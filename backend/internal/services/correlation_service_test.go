@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository/memory"
+)
+
+func TestLaggedCorrelateByMonth(t *testing.T) {
+	// Monthly temperatures with seasonality and noise, and quarterly cases driven by the
+	// temperatures two months earlier
+	seed := uint32(7)
+	noise := func() float64 {
+		seed = seed*1664525 + 1013904223
+		return float64(seed)/math.MaxUint32 - 0.5
+	}
+
+	var observations []models.EnvironmentObservation
+	temperature := make(map[int]float64) // by months since year 0
+	for year := 2012; year <= 2022; year++ {
+		for month := 1; month <= 12; month++ {
+			v := 10*math.Sin(2*math.Pi*float64(month)/12) + 8*noise()
+			temperature[year*12+month-1] = v
+			observations = append(observations, models.EnvironmentObservation{
+				Station: "Chisinau", Variable: models.VariableAirTemperature, Year: uint16(year), Month: uint8(month), Value: v,
+			})
+		}
+	}
+
+	var diseases []models.Disease
+	for year := 2013; year <= 2022; year++ {
+		for quarter := 1; quarter <= 4; quarter++ {
+			cases := 1000.0
+			for m := 0; m < 3; m++ {
+				cases += 20 * temperature[year*12+(quarter-1)*3+m-2]
+			}
+			diseases = append(diseases, models.Disease{
+				ID: fmt.Sprintf("flu_%d_%d", year, quarter), Name: "Gripa", Category: "Respiratory Infections",
+				Region: "Moldova", Year: uint16(year), Quarter: uint8(quarter), Cases: uint32(cases),
+			})
+		}
+	}
+
+	store := memory.NewStore(memory.Seed{Diseases: diseases, Environment: observations})
+	service := NewCorrelationService(store.Diseases, store.Environment)
+
+	result, err := service.LaggedCorrelate(context.Background(), &models.CorrelationRequest{
+		Factor1: "environment:" + models.VariableAirTemperature,
+		Factor2: "disease:Gripa",
+		Timeframe: models.TimeframeFilter{
+			StartDate: time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC),
+		},
+		MaxLag:  4,
+		LagUnit: models.LagMonth,
+	})
+	if err != nil {
+		t.Fatalf("LaggedCorrelate: %v", err)
+	}
+
+	if result.LagUnit != models.LagMonth || len(result.Raw.Lags) != 5 {
+		t.Fatalf("got %s lags %+v, want five month lags", result.LagUnit, result.Raw.Lags)
+	}
+	for _, ccf := range []models.CrossCorrelation{result.Raw, result.Prewhitened} {
+		if ccf.BestLag == nil || ccf.BestLag.Lag != 2 || ccf.BestLag.Coefficient < 0.9 {
+			t.Errorf("best lag = %+v, want a strong correlation at 2 months", ccf.BestLag)
+		}
+	}
+}
+
+func TestMatrixMethod(t *testing.T) {
+	// Two diseases in a monotone but far from linear relation
+	var diseases []models.Disease
+	for i := 0; i < 12; i++ {
+		year, quarter := uint16(2015+i/4), uint8(i%4+1)
+		for _, d := range []struct {
+			name  string
+			cases uint32
+		}{{"Gripa", uint32(10 + i)}, {"Rujeola", uint32(math.Pow(2, float64(i)))}} {
+			diseases = append(diseases, models.Disease{
+				ID: fmt.Sprintf("%s_%d_%d", d.name, year, quarter), Name: d.name, Category: "Respiratory Infections",
+				Region: "Moldova", Year: year, Quarter: quarter, Cases: d.cases,
+			})
+		}
+	}
+	store := memory.NewStore(memory.Seed{Diseases: diseases})
+	service := NewCorrelationService(store.Diseases, store.Environment)
+
+	coefficient := func(method string) float64 {
+		m, err := service.Matrix(context.Background(), &models.CorrelationMatrixRequest{
+			Diseases: []string{"Gripa", "Rujeola"},
+			Method:   method,
+		})
+		if err != nil {
+			t.Fatalf("Matrix(%s): %v", method, err)
+		}
+		if m.Method != method || m.Coefficients[0][1] == nil {
+			t.Fatalf("Matrix(%s) = %+v", method, m)
+		}
+		return *m.Coefficients[0][1]
+	}
+
+	if r := coefficient(models.CorrelationSpearman); r != 1 {
+		t.Errorf("Spearman coefficient = %g, want 1", r)
+	}
+	if r := coefficient(models.CorrelationPearson); r >= 0.9 {
+		t.Errorf("Pearson coefficient = %g, want well below 1", r)
+	}
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+)
+
+// orDemo returns the synthetic answer in demo mode and err otherwise. Only missing
+// implementations and storage failures are replaced; invalid requests, missing records and
+// insufficient data are always reported, since no synthetic answer can stand in for them.
+func orDemo[T any](demoMode bool, err error, synthetic func() T) (T, error) {
+	if demoMode && (errors.Is(err, models.ErrNotImplemented) || isBackendError(err)) {
+		return synthetic(), nil
+	}
+
+	var zero T
+	return zero, err
+}
+
+// isBackendError reports whether err is a storage failure rather than a problem with the
+// request or the data
+func isBackendError(err error) bool {
+	for _, known := range []error{models.ErrInvalidFilter, models.ErrNotFound, models.ErrInsufficientData, models.ErrNotImplemented} {
+		if errors.Is(err, known) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// defaultPyramidBandWidth is the width in years of the age bands of a pyramid
+const defaultPyramidBandWidth = 5
+
+// DemographicsService breaks indicators down by age, sex and medium
+type DemographicsService struct {
+	stratified repository.StratifiedRepository
+	population repository.PopulationRepository
+}
+
+// NewDemographicsService creates a new DemographicsService
+func NewDemographicsService(stratified repository.StratifiedRepository, population repository.PopulationRepository) *DemographicsService {
+	return &DemographicsService{stratified: stratified, population: population}
+}
+
+// Series returns the yearly series of an indicator per stratum of the grouped dimensions; the
+// indicator defaults to the population
+func (s *DemographicsService) Series(ctx context.Context, filter models.StratifiedFilter) ([]models.StratifiedSeries, error) {
+	if filter.Indicator == "" {
+		filter.Indicator = models.IndicatorPopulation
+	}
+
+	facts, err := s.facts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return repository.Stratify(facts, filter.GroupBy, filter.BandWidth, filter.OpenAge), nil
+}
+
+// Pyramid breaks an indicator down by age band and sex in one year, the latest with facts when
+// year is nil. Bands are five years wide unless the filter sets a width; its years and grouping
+// are ignored.
+func (s *DemographicsService) Pyramid(ctx context.Context, filter models.StratifiedFilter, year *int) (*models.Pyramid, error) {
+	if filter.Indicator == "" {
+		filter.Indicator = models.IndicatorPopulation
+	}
+	if filter.BandWidth == 0 {
+		filter.BandWidth = defaultPyramidBandWidth
+	}
+	filter.StartYear, filter.EndYear = year, year
+	filter.GroupBy = []string{models.DimensionAge, models.DimensionSex}
+
+	facts, err := s.facts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(facts) == 0 {
+		return nil, fmt.Errorf("%w: no %s facts for the pyramid", models.ErrNotFound, filter.Indicator)
+	}
+
+	latest := int(facts[0].Year)
+	for _, f := range facts {
+		if int(f.Year) > latest {
+			latest = int(f.Year)
+		}
+	}
+	var inYear []models.StratifiedFact
+	for _, f := range facts {
+		if int(f.Year) == latest {
+			inYear = append(inYear, f)
+		}
+	}
+
+	pyramid := &models.Pyramid{Indicator: filter.Indicator, Year: latest, Media: filter.Media, Bars: []models.PyramidBar{}}
+	if len(pyramid.Media) == 0 {
+		pyramid.Media = []string{models.MediumUrban, models.MediumRural}
+	}
+
+	// Stratify orders the series by age band, so the bars come out youngest first
+	for _, series := range repository.Stratify(inYear, filter.GroupBy, filter.BandWidth, filter.OpenAge) {
+		n := len(pyramid.Bars)
+		if n == 0 || pyramid.Bars[n-1].Age != series.Age {
+			pyramid.Bars = append(pyramid.Bars, models.PyramidBar{Age: series.Age})
+			n++
+		}
+
+		bar := &pyramid.Bars[n-1]
+		for _, p := range series.Points {
+			switch series.Sex {
+			case models.SexMale:
+				bar.Male += p.Value
+			case models.SexFemale:
+				bar.Female += p.Value
+			}
+			pyramid.Total += p.Value
+			pyramid.Suppressed.Merge(p.Suppressed)
+		}
+	}
+
+	if pyramid.Total > 0 {
+		for i := range pyramid.Bars {
+			bar := &pyramid.Bars[i]
+			bar.MalePercent = bar.Male / pyramid.Total * 100
+			bar.FemalePercent = bar.Female / pyramid.Total * 100
+		}
+	}
+
+	return pyramid, nil
+}
+
+// facts returns the facts matching the filter, reading the population indicator from the
+// population table
+func (s *DemographicsService) facts(ctx context.Context, filter models.StratifiedFilter) ([]models.StratifiedFact, error) {
+	if _, err := repository.CompileStratifiedFilter(filter); err != nil {
+		return nil, err
+	}
+	if !models.Additive(filter.Indicator) && (len(filter.GroupBy) < 3 || filter.BandWidth > 0) {
+		return nil, fmt.Errorf("%w: %s cannot be summed; group by age, sex and medium without bands",
+			models.ErrInvalidFilter, filter.Indicator)
+	}
+
+	if filter.Indicator != models.IndicatorPopulation {
+		facts, err := s.stratified.Facts(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("error listing %s facts: %w", filter.Indicator, err)
+		}
+		return facts, nil
+	}
+
+	entries, err := s.population.List(ctx, models.PopulationFilter{
+		StartYear: filter.StartYear,
+		EndYear:   filter.EndYear,
+		Ages:      filter.Ages,
+		Media:     filter.Media,
+		Sexes:     filter.Sexes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing population: %w", err)
+	}
+	return repository.PopulationFacts(entries), nil
+}
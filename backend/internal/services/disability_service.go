@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// disabilityRatePer is the adult population base of disability rates
+const disabilityRatePer = 10000
+
+// defaultDisabilityLimit is the number of rows of a page of the disability table without a limit
+const defaultDisabilityLimit = 10
+
+// DisabilityService rates the adults recognised with a primary disability against the adult
+// population of their age group, sex and medium
+type DisabilityService struct {
+	stratified repository.StratifiedRepository
+	population repository.PopulationRepository
+}
+
+// NewDisabilityService creates a new DisabilityService
+func NewDisabilityService(stratified repository.StratifiedRepository, population repository.PopulationRepository) *DisabilityService {
+	return &DisabilityService{stratified: stratified, population: population}
+}
+
+// ListRates returns a page of the disability table, one row per year and stratum of the grouped
+// dimensions, ordered by the most recent years first unless the filter sorts otherwise
+func (s *DisabilityService) ListRates(ctx context.Context, filter models.DisabilityFilter) (*models.DisabilityPage, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultDisabilityLimit
+	}
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return nil, fmt.Errorf("%w: limit and offset must not be negative", models.ErrInvalidFilter)
+	}
+	if filter.Sort == "" && filter.SortBy == "" {
+		filter.Sort = "-year"
+	}
+	spec, err := repository.DisabilitySortSpec(filter)
+	if err != nil {
+		return nil, err
+	}
+	ordering, err := repository.CompileDisabilitySort(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := s.Series(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var rates []models.DisabilityRate
+	for _, line := range series {
+		for _, p := range line.Points {
+			rates = append(rates, models.DisabilityRate{Age: line.Age, Sex: line.Sex, Medium: line.Medium, DisabilityPoint: p})
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool { return ordering.Less(&rates[i], &rates[j]) })
+
+	page := &models.DisabilityPage{
+		Rates:      []models.DisabilityRate{},
+		TotalCount: len(rates),
+		Page:       filter.Offset/filter.Limit + 1,
+		TotalPages: (len(rates) + filter.Limit - 1) / filter.Limit,
+		Limit:      filter.Limit,
+		HasMore:    filter.Offset+filter.Limit < len(rates),
+	}
+	if filter.Offset < len(rates) {
+		end := filter.Offset + filter.Limit
+		if end > len(rates) {
+			end = len(rates)
+		}
+		page.Rates = rates[filter.Offset:end]
+	}
+	return page, nil
+}
+
+// Series returns the yearly disability rate per stratum of the grouped dimensions, summing the
+// persons and adults of the others. Each rate is compared with that of the year before, read even
+// when it precedes the filter's years.
+func (s *DisabilityService) Series(ctx context.Context, filter models.DisabilityFilter) ([]models.DisabilitySeries, error) {
+	query := models.StratifiedFilter{
+		Indicator: models.IndicatorDisability,
+		StartYear: filter.StartYear,
+		EndYear:   filter.EndYear,
+		Ages:      filter.Ages,
+		Sexes:     filter.Sexes,
+		Media:     filter.Media,
+		GroupBy:   filter.GroupBy,
+	}
+	if _, err := repository.CompileStratifiedFilter(query); err != nil {
+		return nil, err
+	}
+	if filter.StartYear != nil {
+		previous := *filter.StartYear - 1
+		query.StartYear = &previous
+	}
+
+	facts, err := s.stratified.Facts(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error listing disability facts: %w", err)
+	}
+	adults, err := s.adults(ctx, query, facts)
+	if err != nil {
+		return nil, err
+	}
+
+	type stratumKey struct {
+		age, sex, medium string
+	}
+	population := make(map[stratumKey]map[int]float64)
+	for _, line := range repository.Stratify(adults, filter.GroupBy, 0, 0) {
+		k := stratumKey{line.Age, line.Sex, line.Medium}
+		population[k] = make(map[int]float64, len(line.Points))
+		for _, p := range line.Points {
+			population[k][p.Year] = p.Value
+		}
+	}
+
+	persons := repository.Stratify(facts, filter.GroupBy, 0, 0)
+	series := make([]models.DisabilitySeries, 0, len(persons))
+	for _, line := range persons {
+		ds := models.DisabilitySeries{Age: line.Age, Sex: line.Sex, Medium: line.Medium, Points: []models.DisabilityPoint{}}
+		byYear := population[stratumKey{line.Age, line.Sex, line.Medium}]
+
+		var previous *models.DisabilityPoint
+		for _, sp := range line.Points {
+			p := models.DisabilityPoint{
+				Year:       sp.Year,
+				Persons:    sp.Value,
+				Adults:     byYear[sp.Year],
+				Status:     sp.Status,
+				Suppressed: sp.Suppressed,
+			}
+			if p.Adults > 0 && !p.Status.Suppressed() {
+				rate := p.Persons / p.Adults * disabilityRatePer
+				p.RatePer10k = &rate
+			}
+			if previous != nil && previous.Year == p.Year-1 {
+				p.Change, p.ChangePercent = rateChange(previous.RatePer10k, p.RatePer10k)
+			}
+			previous = &p
+
+			if filter.StartYear == nil || p.Year >= *filter.StartYear {
+				ds.Points = append(ds.Points, p)
+			}
+		}
+		if len(ds.Points) > 0 {
+			series = append(series, ds)
+		}
+	}
+	return series, nil
+}
+
+// adults returns, for each disability fact, the mid-year population of its year, age group, sex and
+// medium as a population fact with the fact's status, so that stratifying both leaves the adults
+// of suppressed cells out of the denominators. Facts of years without population get none.
+func (s *DisabilityService) adults(ctx context.Context, query models.StratifiedFilter, facts []models.StratifiedFact) ([]models.StratifiedFact, error) {
+	var groups []string
+	seen := make(map[string]bool)
+	for _, f := range facts {
+		if _, _, ok := repository.AgeRange(f.Age); ok && !seen[f.Age] {
+			seen[f.Age] = true
+			groups = append(groups, f.Age)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return repository.AgeLess(groups[i], groups[j]) })
+
+	filter := models.PopulationFilter{StartYear: query.StartYear, Sexes: query.Sexes, Media: query.Media}
+	if query.EndYear != nil {
+		// The year after the range is needed to interpolate the last mid-year population
+		next := *query.EndYear + 1
+		filter.EndYear = &next
+	}
+	entries, err := s.population.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing population: %w", err)
+	}
+
+	type cell struct {
+		year             int
+		age, sex, medium string
+	}
+	january1 := make(map[cell]float64)
+	for _, e := range entries {
+		if group, ok := ageGroupOf(e.Age, groups); ok {
+			january1[cell{int(e.Year), group, e.Sex, e.Medium}] += float64(e.Population)
+		}
+	}
+
+	adults := make([]models.StratifiedFact, 0, len(facts))
+	for _, f := range facts {
+		k := cell{int(f.Year), f.Age, f.Sex, f.Medium}
+		n, ok := january1[k]
+		if !ok {
+			continue
+		}
+		k.year++
+		if next, ok := january1[k]; ok {
+			n = (n + next) / 2
+		}
+		adults = append(adults, models.StratifiedFact{
+			Indicator: models.IndicatorPopulation,
+			Year:      f.Year,
+			Age:       f.Age,
+			Sex:       f.Sex,
+			Medium:    f.Medium,
+			Value:     n,
+			Status:    f.Status,
+		})
+	}
+	return adults, nil
+}
+
+// rateChange returns the difference between two yearly rates and its percentage of the earlier
+// one, nil when either rate is missing or the percentage when the earlier rate is zero
+func rateChange(previous, current *float64) (*float64, *float64) {
+	if previous == nil || current == nil {
+		return nil, nil
+	}
+	change := *current - *previous
+	if *previous == 0 {
+		return &change, nil
+	}
+	percent := change / *previous * 100
+	return &change, &percent
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository/memory"
+)
+
+// disabilityStore seeds the urban adults aged 18-64 recognised with a disability from 2016 to
+// 2022: 10 men in 2016 and 2 more every year among 10,000, and 5 women among 5,000 but for a
+// confidential count in 2021. The population is the same every year, so it is also the mid-year
+// population.
+func disabilityStore() *DisabilityService {
+	var facts []models.StratifiedFact
+	var entries []models.PopulationEntry
+	for year := uint16(2016); year <= 2022; year++ {
+		women := models.StratifiedFact{Indicator: models.IndicatorDisability, Year: year, Age: "18-64",
+			Sex: models.SexFemale, Medium: models.MediumUrban, Value: 5, Status: models.StatusObserved}
+		if year == 2021 {
+			women.Value, women.Status = 0, models.StatusConfidential
+		}
+		facts = append(facts, women, models.StratifiedFact{Indicator: models.IndicatorDisability, Year: year, Age: "18-64",
+			Sex: models.SexMale, Medium: models.MediumUrban, Value: float64(10 + 2*(year-2016)), Status: models.StatusObserved})
+	}
+	for year := uint16(2016); year <= 2023; year++ {
+		entries = append(entries,
+			models.PopulationEntry{Year: year, Age: "30", Sex: models.SexMale, Medium: models.MediumUrban, Population: 10000},
+			models.PopulationEntry{Year: year, Age: "30", Sex: models.SexFemale, Medium: models.MediumUrban, Population: 5000},
+		)
+	}
+
+	store := memory.NewStore(memory.Seed{Stratified: facts, Population: entries})
+	return NewDisabilityService(store.Stratified, store.Population)
+}
+
+func TestDisabilitySeries(t *testing.T) {
+	series, err := disabilityStore().Series(context.Background(), models.DisabilityFilter{StartYear: intPtr(2020)})
+	if err != nil {
+		t.Fatalf("Series: %v", err)
+	}
+	if len(series) != 1 {
+		t.Fatalf("%d series, want the sum over sexes", len(series))
+	}
+	points := series[0].Points
+	if len(points) != 3 || points[0].Year != 2020 {
+		t.Fatalf("points = %+v, want 2020 to 2022", points)
+	}
+
+	// 2020 is compared with the 21 persons among 15,000 adults of 2019, before the filter's years
+	first := points[0]
+	if first.Persons != 23 || first.Adults != 15000 || first.RatePer10k == nil || !near(*first.RatePer10k, 23./1.5, 1e-9) {
+		t.Errorf("2020 = %+v, want 23 persons among 15,000 adults", first)
+	}
+	if first.Change == nil || !near(*first.Change, 2/1.5, 1e-9) || first.ChangePercent == nil || !near(*first.ChangePercent, 200./21, 1e-9) {
+		t.Errorf("2020 change = %v, %v%%; want %g, %g%%", first.Change, first.ChangePercent, 2/1.5, 200./21)
+	}
+
+	// The women of 2021 are confidential, so their 5,000 adults are left out with them
+	suppressed := points[1]
+	if suppressed.Persons != 20 || suppressed.Adults != 10000 || suppressed.Suppressed.Confidential != 1 {
+		t.Errorf("2021 = %+v, want the 20 men among 10,000 adults and a confidential cell", suppressed)
+	}
+	if suppressed.RatePer10k == nil || !near(*suppressed.RatePer10k, 20, 1e-9) {
+		t.Errorf("2021 rate = %v, want 20 per 10k", suppressed.RatePer10k)
+	}
+}
+
+func TestDisabilityRatesPages(t *testing.T) {
+	service := disabilityStore()
+
+	// Seven years of men and women, ten rows to a page by default, the latest year first
+	page, err := service.ListRates(context.Background(), models.DisabilityFilter{GroupBy: []string{models.DimensionSex}})
+	if err != nil {
+		t.Fatalf("ListRates: %v", err)
+	}
+	if page.Limit != defaultDisabilityLimit || page.TotalCount != 14 || page.TotalPages != 2 || page.Page != 1 || !page.HasMore {
+		t.Errorf("page %d of %d, limit %d, %d rows, more %v; want 1 of 2, %d, 14, true",
+			page.Page, page.TotalPages, page.Limit, page.TotalCount, page.HasMore, defaultDisabilityLimit)
+	}
+	if len(page.Rates) != defaultDisabilityLimit || page.Rates[0].Year != 2022 || page.Rates[len(page.Rates)-1].Year != 2018 {
+		t.Errorf("rates = %+v, want ten rows from 2022 to 2018", page.Rates)
+	}
+
+	last, err := service.ListRates(context.Background(), models.DisabilityFilter{GroupBy: []string{models.DimensionSex}, Offset: 10})
+	if err != nil {
+		t.Fatalf("ListRates: %v", err)
+	}
+	if last.Page != 2 || last.HasMore || len(last.Rates) != 4 || last.Rates[0].Year != 2017 {
+		t.Errorf("page %d, more %v, rates %+v; want the last four rows from 2017", last.Page, last.HasMore, last.Rates)
+	}
+}
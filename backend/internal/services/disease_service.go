@@ -2,23 +2,37 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/ktruedat/healthisis/backend/internal/database"
+	"github.com/ktruedat/healthisis/backend/internal/demo"
 	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
 )
 
 // DiseaseService handles disease-related business logic
 type DiseaseService struct {
-	db *database.DB
+	repo        repository.DiseaseRepository
+	forecasts   *ForecastService
+	environment *EnvironmentService
+	population  *PopulationService
+	demoMode    bool
 }
 
-// NewDiseaseService creates a new DiseaseService
-func NewDiseaseService(db *database.DB) *DiseaseService {
-	return &DiseaseService{db: db}
+// NewDiseaseService creates a new DiseaseService; in demo mode it answers with synthetic data
+// where nothing can be computed
+func NewDiseaseService(
+	repo repository.DiseaseRepository, forecasts *ForecastService, environment *EnvironmentService,
+	population *PopulationService, demoMode bool,
+) *DiseaseService {
+	return &DiseaseService{
+		repo: repo, forecasts: forecasts, environment: environment, population: population, demoMode: demoMode,
+	}
 }
 
 // ListDiseases retrieves diseases based on filter criteria
@@ -28,137 +42,116 @@ func (s *DiseaseService) ListDiseases(ctx context.Context, filter models.Disease
 		filter.Limit = 100
 	}
 
-	// Build query parts
-	query := `
-		SELECT 
-			id, name, category, year, quarter, region, cases, deaths, 
-			recoveries, population, incidence_rate, mortality_rate 
-		FROM diseases
-		WHERE 1=1
-	`
-	var args []interface{}
-
-	// Apply filters
-	if filter.StartYear != nil {
-		query += " AND year >= ?"
-		args = append(args, uint16(*filter.StartYear)) // Convert to uint16
+	diseases, err := s.repo.List(ctx, defaultSort(filter))
+	if err != nil {
+		return nil, err
 	}
 
-	if filter.EndYear != nil {
-		query += " AND year <= ?"
-		args = append(args, uint16(*filter.EndYear)) // Convert to uint16
+	for i := range diseases {
+		fillDerivedCounts(&diseases[i])
 	}
 
-	if len(filter.Quarters) > 0 {
-		query += " AND quarter IN ("
-		for i, q := range filter.Quarters {
-			if i > 0 {
-				query += ","
-			}
-			query += "?"
-			args = append(args, q)
-		}
-		query += ")"
-	}
+	return diseases, nil
+}
 
-	// Add sorting, limit and offset
-	sortBy := "year"
-	if filter.SortBy != "" {
-		sortBy = filter.SortBy
+// ListDiseasePage retrieves one page of diseases together with the total number of matches
+// and, when more records follow, a cursor for the next page
+func (s *DiseaseService) ListDiseasePage(ctx context.Context, filter models.DiseaseFilter) (*models.DiseasePage, error) {
+	if filter.Limit == 0 {
+		filter.Limit = 100
 	}
+	filter = defaultSort(filter)
 
-	sortOrder := "DESC"
-	if filter.SortOrder != "" {
-		sortOrder = filter.SortOrder
+	spec, err := repository.SortSpec(filter)
+	if err != nil {
+		return nil, err
 	}
-
-	query += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortOrder)
-	query += fmt.Sprintf(" LIMIT %d OFFSET %d", filter.Limit, filter.Offset)
-
-	// Execute query
-	rows, err := s.db.GetConn().Query(ctx, query, args...)
+	ordering, err := repository.CompileSort(spec)
 	if err != nil {
-		return nil, fmt.Errorf("error querying diseases: %w", err)
-	}
-	defer rows.Close()
-
-	// Parse results
-	var diseases []models.Disease
-	for rows.Next() {
-		var d models.Disease
-		if err := rows.Scan(
-			&d.ID, &d.Name, &d.Category, &d.Year, &d.Quarter, &d.Region,
-			&d.Cases, &d.Deaths, &d.Recoveries, &d.Population,
-			&d.IncidenceRate, &d.MortalityRate,
-		); err != nil {
-			return nil, fmt.Errorf("error scanning disease row: %w", err)
-		}
+		return nil, err
+	}
 
-		// Calculate deaths and recoveries if not provided directly
-		if d.Deaths == 0 && d.MortalityRate > 0 {
-			d.Deaths = uint32(float64(d.Population) * d.MortalityRate / 100) // Convert to uint32
-		}
+	// Fetch one extra record to find out whether another page follows
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	diseases, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
 
-		if d.Cases == 0 && d.IncidenceRate > 0 {
-			d.Cases = uint32(float64(d.Population) * d.IncidenceRate / 100) // Convert to uint32
-		}
+	total, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
 
-		if d.Recoveries == 0 {
-			// Simple assumption: recoveries = cases - deaths
-			d.Recoveries = d.Cases - d.Deaths
-			if d.Recoveries < 0 {
-				d.Recoveries = 0 // Prevent negative values - though this should never happen with unsigned types
-			}
-		}
+	page := &models.DiseasePage{
+		Diseases:   make([]models.Disease, 0, len(diseases)),
+		TotalCount: total,
+		TotalPages: (total + limit - 1) / limit,
+		Limit:      limit,
+		HasMore:    len(diseases) > limit,
+	}
+	if page.HasMore {
+		diseases = diseases[:limit]
+		// The cursor is taken from the stored values, before derived counts are filled in
+		page.NextCursor = repository.EncodeCursor(ordering, &diseases[limit-1])
+	}
+	if filter.Cursor == "" {
+		page.Page = filter.Offset/limit + 1
+	}
 
-		diseases = append(diseases, d)
+	for i := range diseases {
+		fillDerivedCounts(&diseases[i])
 	}
+	page.Diseases = append(page.Diseases, diseases...)
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating disease rows: %w", err)
+	if err := s.include(ctx, filter.Include, page.Diseases); err != nil {
+		return nil, err
 	}
 
-	return diseases, nil
+	return page, nil
 }
 
-// GetDiseaseByID retrieves a specific disease by ID
-func (s *DiseaseService) GetDiseaseByID(ctx context.Context, id string) (*models.Disease, error) {
-	query := `
-		SELECT 
-			id, name, category, year, quarter, region, cases, deaths, 
-			recoveries, population, incidence_rate, mortality_rate 
-		FROM diseases
-		WHERE id = ?
-	`
-
-	row := s.db.GetConn().QueryRow(ctx, query, id)
-	var d models.Disease
-	err := row.Scan(
-		&d.ID, &d.Name, &d.Category, &d.Year, &d.Quarter, &d.Region,
-		&d.Cases, &d.Deaths, &d.Recoveries, &d.Population,
-		&d.IncidenceRate, &d.MortalityRate,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error scanning disease: %w", err)
+// defaultSort orders by the most recent years first; a bare sortBy keeps its historical descending order
+func defaultSort(filter models.DiseaseFilter) models.DiseaseFilter {
+	if filter.Sort == "" && filter.SortBy == "" {
+		filter.Sort = "-year"
 	}
+	if filter.SortBy != "" && filter.SortOrder == "" {
+		filter.SortOrder = "desc"
+	}
+	return filter
+}
 
-	// Calculate deaths and recoveries if needed
-	if d.Deaths == 0 && d.MortalityRate > 0 {
-		d.Deaths = uint32(float64(d.Population) * d.MortalityRate / 100) // Convert to uint32
+// GetDiseaseByID retrieves a specific disease by ID, joining in the requested related data
+func (s *DiseaseService) GetDiseaseByID(ctx context.Context, id string, include []string) (*models.Disease, error) {
+	d, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	if d.Cases == 0 && d.IncidenceRate > 0 {
-		d.Cases = uint32(float64(d.Population) * d.IncidenceRate / 100) // Convert to uint32
+	fillDerivedCounts(d)
+
+	records := []models.Disease{*d}
+	if err := s.include(ctx, include, records); err != nil {
+		return nil, err
 	}
+	return &records[0], nil
+}
 
-	if d.Recoveries == 0 {
-		d.Recoveries = d.Cases - d.Deaths
-		if d.Recoveries < 0 {
-			d.Recoveries = 0
+// include joins the requested related data into the records
+func (s *DiseaseService) include(ctx context.Context, include []string, diseases []models.Disease) error {
+	for _, inc := range include {
+		switch inc {
+		case models.IncludeEnvironment:
+			if err := s.environment.Attach(ctx, diseases); err != nil {
+				return fmt.Errorf("error joining environment data: %w", err)
+			}
+		default:
+			return fmt.Errorf("%w: unknown include %q", models.ErrInvalidFilter, inc)
 		}
 	}
-
-	return &d, nil
+	return nil
 }
 
 // CreateDisease adds a new disease record
@@ -167,238 +160,368 @@ func (s *DiseaseService) CreateDisease(ctx context.Context, disease *models.Dise
 	if disease.ID == "" {
 		disease.ID = fmt.Sprintf("disease_%d", time.Now().UnixNano())
 	}
-
-	query := `
-		INSERT INTO diseases (
-			id, name, category, year, quarter, region, cases, deaths, 
-			recoveries, population, incidence_rate, mortality_rate
-		) VALUES (
-			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
-		)
-	`
-
-	err := s.db.GetConn().Exec(ctx, query,
-		disease.ID, disease.Name, disease.Category, disease.Year, disease.Quarter, disease.Region,
-		disease.Cases, disease.Deaths, disease.Recoveries, disease.Population,
-		disease.IncidenceRate, disease.MortalityRate,
-	)
-	if err != nil {
-		return fmt.Errorf("error creating disease: %w", err)
+	if err := checkStatus(disease); err != nil {
+		return err
+	}
+	if err := s.withDenominator(ctx, disease); err != nil {
+		return err
 	}
 
-	return nil
+	return s.repo.Create(ctx, disease)
 }
 
 // UpdateDisease updates an existing disease record
 func (s *DiseaseService) UpdateDisease(ctx context.Context, disease *models.Disease) error {
-	query := `
-		ALTER TABLE diseases UPDATE
-			name = ?,
-			category = ?,
-			region = ?,
-			cases = ?,
-			deaths = ?,
-			recoveries = ?,
-			population = ?,
-			incidence_rate = ?,
-			prevalence_rate = ?,
-			mortality_rate = ?
-		WHERE id = ?
-	`
-
-	err := s.db.GetConn().Exec(ctx, query,
-		disease.Name, disease.Category, disease.Region,
-		disease.Cases, disease.Deaths, disease.Recoveries, disease.Population,
-		disease.IncidenceRate, disease.PrevalenceRate, disease.MortalityRate,
-		disease.ID,
-	)
+	if err := checkStatus(disease); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, disease)
+}
 
+// withDenominator gives a national record without a population the mid-year population of its
+// year and age group and computes its rates against it; records of other regions have no
+// denominators
+func (s *DiseaseService) withDenominator(ctx context.Context, d *models.Disease) error {
+	if d.Population > 0 || d.Region != models.NationalRegion || d.Status.Suppressed() {
+		return nil
+	}
+
+	population, ok, err := s.population.Denominator(ctx, int(d.Year), d.AgeGroup)
 	if err != nil {
-		return fmt.Errorf("error updating disease: %w", err)
+		return fmt.Errorf("error looking up the population of %d: %w", d.Year, err)
+	}
+	if ok {
+		repository.Rebase(d, population)
 	}
+	return nil
+}
 
+// checkStatus defaults an empty status to observed and an empty age group to all ages, and
+// rejects unknown statuses and age groups and suppressed records that carry counts
+func checkStatus(d *models.Disease) error {
+	if d.Status == "" {
+		d.Status = models.StatusObserved
+	}
+	if d.AgeGroup == "" {
+		d.AgeGroup = models.AgeGroupAll
+	}
+	if d.AgeGroup != models.AgeGroupAll && d.AgeGroup != models.AgeGroupChildren {
+		return fmt.Errorf("%w: age group %q is not %q or %q", models.ErrInvalidRecord,
+			d.AgeGroup, models.AgeGroupAll, models.AgeGroupChildren)
+	}
+	if !d.Status.Valid() {
+		return fmt.Errorf("%w: unknown status %q", models.ErrInvalidRecord, d.Status)
+	}
+	if d.Status.Suppressed() && (d.Cases > 0 || d.Deaths > 0 || d.Recoveries > 0) {
+		return fmt.Errorf("%w: a %s record cannot have counts", models.ErrInvalidRecord, d.Status)
+	}
 	return nil
 }
 
 // DeleteDisease removes a disease record
 func (s *DiseaseService) DeleteDisease(ctx context.Context, id string) error {
-	query := `ALTER TABLE diseases DELETE WHERE id = ?`
+	return s.repo.Delete(ctx, id)
+}
+
+// Trend thresholds: a change is reported as a direction only when the two-sided Poisson test
+// rejects equal rates at the 5% level and the relative change is large enough to matter
+const (
+	trendZThreshold       = 1.96
+	trendMinChangePercent = 5.0
+)
 
-	err := s.db.GetConn().Exec(ctx, query, id)
+// GetDiseaseStats calculates statistics for diseases and the trend of the latest period
+// against the previous period selected by window
+func (s *DiseaseService) GetDiseaseStats(ctx context.Context, filter models.DiseaseFilter, window models.ComparisonWindow) (*models.DiseaseStats, error) {
+	if window == "" {
+		window = models.ComparePriorYear
+	}
+	switch window {
+	case models.ComparePriorYear, models.ComparePriorQuarter, models.CompareSameQuarterLastYear:
+	default:
+		return nil, fmt.Errorf("%w: unknown comparison %q; allowed: %s, %s, %s", models.ErrInvalidFilter,
+			window, models.ComparePriorYear, models.ComparePriorQuarter, models.CompareSameQuarterLastYear)
+	}
+
+	totals, err := s.repo.Totals(ctx, filter)
 	if err != nil {
-		return fmt.Errorf("error deleting disease: %w", err)
+		return nil, err
 	}
 
-	return nil
+	stats := &models.DiseaseStats{
+		TotalCases:      int(totals.Cases),
+		TotalDeaths:     int(totals.Deaths),
+		TotalRecoveries: int(totals.Recoveries),
+		AverageRate:     totals.AvgIncidence,
+		TrendDirection:  "insufficient_data",
+		Comparison:      &models.PeriodComparison{Window: window},
+		Suppressed:      totals.Suppressed,
+	}
+
+	cur, prev, err := s.comparisonPeriods(ctx, filter, window)
+	if err != nil {
+		return nil, err
+	}
+	if cur == nil {
+		return stats, nil
+	}
+
+	curTotals, err := s.repo.Totals(ctx, periodFilter(filter, cur))
+	if err != nil {
+		return nil, err
+	}
+	prevTotals, err := s.repo.Totals(ctx, periodFilter(filter, prev))
+	if err != nil {
+		return nil, err
+	}
+
+	c := stats.Comparison
+	c.Current, c.Previous = cur, prev
+	c.CurrentCases, c.PreviousCases = int(curTotals.Cases), int(prevTotals.Cases)
+
+	// Without any reported record in the previous period there is nothing to compare against
+	if prevTotals.MatchedRecords == uint64(prevTotals.Suppressed.Total()) {
+		return stats, nil
+	}
+
+	// Conditional test of two Poisson counts over equal exposure
+	diff := float64(c.CurrentCases) - float64(c.PreviousCases)
+	if sum := float64(c.CurrentCases + c.PreviousCases); sum > 0 {
+		c.ZScore = diff / math.Sqrt(sum)
+	}
+	c.Significant = math.Abs(c.ZScore) >= trendZThreshold
+
+	if c.PreviousCases > 0 {
+		stats.ChangePercent = diff / float64(c.PreviousCases) * 100
+	}
+
+	switch {
+	case !c.Significant || (c.PreviousCases > 0 && math.Abs(stats.ChangePercent) < trendMinChangePercent):
+		stats.TrendDirection = "stable"
+	case diff > 0:
+		stats.TrendDirection = "increasing"
+	default:
+		stats.TrendDirection = "decreasing"
+	}
+
+	return stats, nil
 }
 
-// GetDiseaseStats calculates statistics for diseases
-func (s *DiseaseService) GetDiseaseStats(ctx context.Context, filter models.DiseaseFilter) (*models.DiseaseStats, error) {
-	// Make a basic query to get total cases, deaths, recoveries
-	query := `
-		SELECT 
-			SUM(cases) as total_cases,
-			SUM(deaths) as total_deaths,
-			SUM(recoveries) as total_recoveries,
-			AVG(incidence_rate) as avg_rate
-		FROM diseases
-		WHERE 1=1
-	`
-
-	var args []interface{}
-
-	// Apply filters
-	if filter.StartYear != nil {
-		query += " AND year >= ?"
-		args = append(args, uint16(*filter.StartYear))
-	}
-
-	if filter.EndYear != nil {
-		query += " AND year <= ?"
-		args = append(args, uint16(*filter.EndYear))
-	}
-
-	// Apply disease ID filter if provided
-	if len(filter.DiseaseIDs) > 0 {
-		// Use LIKE with wildcard to match disease ID prefix
-		// This helps with time series data where IDs might have year/quarter suffixes
-		query += " AND ("
-		for i, id := range filter.DiseaseIDs {
-			if i > 0 {
-				query += " OR "
-			}
-			query += "id LIKE ?"
-			args = append(args, id+"%") // Add % wildcard for prefix matching
+// comparisonPeriods resolves the latest reported period matching the filter and the period it is
+// compared with. It returns nil periods when nothing reported matches.
+func (s *DiseaseService) comparisonPeriods(ctx context.Context, filter models.DiseaseFilter, window models.ComparisonWindow) (*models.Period, *models.Period, error) {
+	all, err := s.repo.TimeSeries(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var points []models.DiseaseTimePoint
+	for _, p := range all {
+		if p.Reported() {
+			points = append(points, p)
 		}
-		query += ")"
-	}
-
-	var totalCases, totalDeaths, totalRecoveries uint64
-	var avgRate float64
-
-	row := s.db.GetConn().QueryRow(ctx, query, args...)
-	if err := row.Scan(&totalCases, &totalDeaths, &totalRecoveries, &avgRate); err != nil {
-		// If there's an error, return a stub for now
-		return &models.DiseaseStats{
-			TotalCases:      1250000,
-			TotalDeaths:     45000,
-			TotalRecoveries: 1150000,
-			AverageRate:     152.7,
-			TrendDirection:  "increasing",
-			ChangePercent:   4.3,
-		}, nil
-	}
-
-	return &models.DiseaseStats{
-		TotalCases:      int(totalCases),
-		TotalDeaths:     int(totalDeaths),
-		TotalRecoveries: int(totalRecoveries),
-		AverageRate:     avgRate,
-		TrendDirection:  "increasing", // Would be calculated based on previous periods
-		ChangePercent:   3.5,          // Would be calculated based on previous periods
-	}, nil
+	}
+	if len(points) == 0 {
+		return nil, nil, nil
+	}
+
+	// Points are ordered by year and quarter, so the last one is the latest period
+	latest := points[len(points)-1]
+	year, quarter := latest.Year, latest.Quarter
+
+	switch window {
+	case models.ComparePriorQuarter:
+		if quarter == 1 {
+			return &models.Period{Year: year, Quarters: []int{1}}, &models.Period{Year: year - 1, Quarters: []int{4}}, nil
+		}
+		return &models.Period{Year: year, Quarters: []int{quarter}}, &models.Period{Year: year, Quarters: []int{quarter - 1}}, nil
+	case models.CompareSameQuarterLastYear:
+		return &models.Period{Year: year, Quarters: []int{quarter}}, &models.Period{Year: year - 1, Quarters: []int{quarter}}, nil
+	}
+
+	// A partially reported latest year is compared with the same quarters of the year before
+	var quarters []int
+	for _, p := range points {
+		if p.Year == year && (len(quarters) == 0 || quarters[len(quarters)-1] != p.Quarter) {
+			quarters = append(quarters, p.Quarter)
+		}
+	}
+	return &models.Period{Year: year, Quarters: quarters}, &models.Period{Year: year - 1, Quarters: quarters}, nil
+}
+
+// periodFilter narrows the filter to a single period, keeping its other criteria
+func periodFilter(filter models.DiseaseFilter, p *models.Period) models.DiseaseFilter {
+	year := p.Year
+	filter.StartYear, filter.EndYear = &year, &year
+	filter.Quarters = p.Quarters
+	return filter
 }
 
 // GetTimeSeries retrieves time series data for diseases
 func (s *DiseaseService) GetTimeSeries(ctx context.Context, filter models.DiseaseFilter) (*models.TimeSeries, error) {
-	query := `
-		WITH 
-			sumCases AS (
-				SELECT 
-					year,
-					quarter,
-					name,
-					SUM(cases) as total_cases,
-					SUM(recoveries) as total_recoveries,
-					AVG(incidence_rate) as incidence_rate,
-					AVG(mortality_rate) as mortality_rate
-				FROM diseases
-				WHERE 1=1
-	`
-
-	var args []interface{}
-
-	// Apply filters
-	if filter.StartYear != nil {
-		query += " AND year >= ?"
-		args = append(args, uint16(*filter.StartYear))
-	}
-
-	if filter.EndYear != nil {
-		query += " AND year <= ?"
-		args = append(args, uint16(*filter.EndYear))
-	}
-
-	query += ` 
-				GROUP BY year, quarter, name
-			)
-		SELECT
-			year,
-			quarter,
-			name,
-			total_cases as cases,
-			incidence_rate,
-			mortality_rate,
-			CASE 
-				WHEN total_cases > 0 THEN (total_recoveries / total_cases) * 100
-				ELSE 0
-			END as recovery_rate
-		FROM sumCases
-		ORDER BY year, quarter, name
-	`
-
-	rows, err := s.db.GetConn().Query(ctx, query, args...)
+	points, err := s.repo.TimeSeries(ctx, filter)
 	if err != nil {
-		fmt.Println("Error querying time series:", err)
-		// Return stub data if query fails
-		points := []models.DiseaseTimePoint{
-			{Year: 2020, Quarter: 1, Name: "Influenza", Cases: 15000},
-			{Year: 2020, Quarter: 2, Name: "Influenza", Cases: 12000},
-			{Year: 2020, Quarter: 3, Name: "Influenza", Cases: 9000},
-			{Year: 2020, Quarter: 4, Name: "Influenza", Cases: 18000},
-			{Year: 2021, Quarter: 1, Name: "Influenza", Cases: 24000},
-			{Year: 2021, Quarter: 2, Name: "Influenza", Cases: 18000},
-			{Year: 2021, Quarter: 3, Name: "Influenza", Cases: 14000},
-			{Year: 2021, Quarter: 4, Name: "Influenza", Cases: 19000},
-		}
-		return &models.TimeSeries{Points: points}, nil
+		return orDemo(s.demoMode, err, demo.TimeSeries)
 	}
-	defer rows.Close()
 
-	// Process results
-	var points []models.DiseaseTimePoint
+	series := &models.TimeSeries{Points: points}
+	if points == nil {
+		series.Points = []models.DiseaseTimePoint{}
+	}
+	for _, p := range points {
+		series.Suppressed.Merge(p.Suppressed)
+	}
+	return series, nil
+}
+
+// GetPaediatricBreakdown splits the cases matching the filter between children and adults per
+// disease and quarter, and in total over the diseases. Diseases and quarters missing from either
+// age group are left out, and a quarter withheld in either group counts as suppressed, so adults
+// are only reported where both counts are known. The filter must not select an age group.
+func (s *DiseaseService) GetPaediatricBreakdown(ctx context.Context, filter models.DiseaseFilter) (*models.PaediatricBreakdown, error) {
+	if filter.AgeGroup != "" {
+		return nil, fmt.Errorf("%w: ageGroup cannot be set; both age groups are compared", models.ErrInvalidFilter)
+	}
+
+	all, err := s.repo.TimeSeries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	filter.AgeGroup = models.AgeGroupChildren
+	children, err := s.repo.TimeSeries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		name          string
+		year, quarter int
+	}
+	childPoints := make(map[key]models.DiseaseTimePoint, len(children))
+	for _, p := range children {
+		childPoints[key{p.Name, p.Year, p.Quarter}] = p
+	}
+
+	// The points of all ages come ordered by year, quarter and name, so the quarters of the total
+	// are appended in order
+	breakdown := &models.PaediatricBreakdown{
+		Diseases: []models.AgeSegmentSeries{},
+		Total:    models.AgeSegmentSeries{Points: []models.AgeSegmentPoint{}},
+	}
+	byName := make(map[string]int)
+	var reported []int
+	for _, a := range all {
+		c, ok := childPoints[key{a.Name, a.Year, a.Quarter}]
+		if !ok {
+			continue
+		}
 
-	for rows.Next() {
-		var year uint16
-		var quarter uint8
-		var name string
-		var cases uint64
-		var incidenceRate, mortalityRate, recoveryRate float64
+		p := models.AgeSegmentPoint{Year: a.Year, Quarter: a.Quarter}
+		p.Suppressed.Merge(a.Suppressed)
+		p.Suppressed.Merge(c.Suppressed)
+		both := 0
+		if a.Reported() && c.Reported() {
+			both = 1
+			p.AllAges, p.Children = a.Cases, c.Cases
+			// Both cells are rounded on their own; children never outnumber all ages
+			if a.Cases > c.Cases {
+				p.Adults = a.Cases - c.Cases
+			}
+		}
+		segmentStatus(&p, both)
 
-		if err := rows.Scan(&year, &quarter, &name, &cases, &incidenceRate, &mortalityRate, &recoveryRate); err != nil {
-			return nil, fmt.Errorf("error scanning time series row: %w", err)
+		i, ok := byName[a.Name]
+		if !ok {
+			i = len(breakdown.Diseases)
+			byName[a.Name] = i
+			breakdown.Diseases = append(breakdown.Diseases, models.AgeSegmentSeries{Name: a.Name})
 		}
+		breakdown.Diseases[i].Points = append(breakdown.Diseases[i].Points, p)
+		breakdown.Suppressed.Merge(p.Suppressed)
 
-		point := models.DiseaseTimePoint{
-			Year:          int(year),
-			Quarter:       int(quarter),
-			Name:          name,
-			Cases:         cases,
-			IncidenceRate: incidenceRate,
-			MortalityRate: mortalityRate,
-			RecoveryRate:  recoveryRate,
+		total := &breakdown.Total
+		if n := len(total.Points); n == 0 || total.Points[n-1].Year != p.Year || total.Points[n-1].Quarter != p.Quarter {
+			total.Points = append(total.Points, models.AgeSegmentPoint{Year: p.Year, Quarter: p.Quarter})
+			reported = append(reported, 0)
+		}
+		t := &total.Points[len(total.Points)-1]
+		t.AllAges += p.AllAges
+		t.Children += p.Children
+		t.Adults += p.Adults
+		t.Suppressed.Merge(p.Suppressed)
+		if !p.Status.Suppressed() {
+			reported[len(reported)-1]++
 		}
-		points = append(points, point)
 	}
+	for i := range breakdown.Total.Points {
+		segmentStatus(&breakdown.Total.Points[i], reported[i])
+	}
+
+	sort.Slice(breakdown.Diseases, func(i, j int) bool { return breakdown.Diseases[i].Name < breakdown.Diseases[j].Name })
+	return breakdown, nil
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating time series rows: %w", err)
+// segmentStatus sets the status of a split from its counts and the number of diseases behind it
+// reported in both age groups, with the paediatric share where there are cases
+func segmentStatus(p *models.AgeSegmentPoint, reported int) {
+	p.Status = models.AggregateStatus(reported, p.AllAges, p.Suppressed)
+	if p.AllAges > 0 {
+		share := float64(p.Children) / float64(p.AllAges) * 100
+		p.PaediatricShare = &share
 	}
+}
 
-	return &models.TimeSeries{Points: points}, nil
+// regionCoordinates locates the regions that can be placed on the map
+var regionCoordinates = map[string][2]float64{
+	models.NationalRegion: {47.4116, 28.3699},
+	"Chisinau":            {47.0105, 28.8638},
+	"Balti":               {47.7619, 27.9294},
+	"Tiraspol":            {46.8403, 29.6433},
+	"Cahul":               {45.9075, 28.1944},
+	"Comrat":              {46.3003, 28.6572},
+	"Orhei":               {47.3849, 28.8245},
+	"Soroca":              {48.1558, 28.2975},
+	"Ungheni":             {47.2108, 27.8006},
 }
 
-// AddDiseaseData adds new time-specific data for a disease
+// GetRegionalDistribution sums the cases matching the filter per region
+func (s *DiseaseService) GetRegionalDistribution(ctx context.Context, filter models.DiseaseFilter) (*models.RegionalDistribution, error) {
+	filter.Limit, filter.Offset, filter.Cursor = 0, 0, ""
+	filter.Sort, filter.SortBy, filter.SortOrder = "region,name", "", ""
+
+	diseases, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return orDemo(s.demoMode, err, demo.RegionalDistribution)
+	}
+
+	dist := &models.RegionalDistribution{Regions: []models.RegionCases{}}
+	for _, d := range diseases {
+		n := len(dist.Regions)
+		if n == 0 || dist.Regions[n-1].Name != d.Region {
+			region := models.RegionCases{Name: d.Region, Diseases: []string{}}
+			if c, ok := regionCoordinates[d.Region]; ok {
+				lat, lon := c[0], c[1]
+				region.Lat, region.Lon = &lat, &lon
+			}
+			dist.Regions = append(dist.Regions, region)
+			n++
+		}
+
+		region := &dist.Regions[n-1]
+		region.Cases += uint64(d.Cases)
+		if k := len(region.Diseases); k == 0 || region.Diseases[k-1] != d.Name {
+			region.Diseases = append(region.Diseases, d.Name)
+		}
+	}
+
+	return dist, nil
+}
+
+// defaultCaseFatality is the percentage of cases assumed fatal when data points carry no deaths
+const defaultCaseFatality = 0.1
+
+// AddDiseaseData adds new time-specific data for a disease. The record is national; CreateDisease
+// gives it the mid-year population of its year and computes its rates against it.
 func (s *DiseaseService) AddDiseaseData(ctx context.Context, data *models.DiseaseData) error {
 	// Create a new disease record based on the data
 	disease := &models.Disease{
@@ -406,16 +529,14 @@ func (s *DiseaseService) AddDiseaseData(ctx context.Context, data *models.Diseas
 		Name:           data.DiseaseID, // This would typically be looked up from an existing disease
 		Year:           uint16(data.Year),
 		Quarter:        uint8(data.Quarter),
-		Region:         "Republic of Moldova", // Default value
+		Region:         models.NationalRegion,
 		Cases:          uint32(data.Cases),
 		IncidenceRate:  data.Incidence,
 		PrevalenceRate: data.Prevalence,
-		MortalityRate:  0.1,     // Default value that should be calculated properly
-		Population:     3000000, // Default value that should be looked up
 	}
 
-	// Calculate deaths based on mortality rate
-	disease.Deaths = uint32(float64(disease.Cases) * disease.MortalityRate / 100)
+	// Estimate deaths from the default case fatality
+	disease.Deaths = uint32(float64(disease.Cases) * defaultCaseFatality / 100)
 
 	// Calculate recoveries (cases - deaths)
 	disease.Recoveries = disease.Cases - disease.Deaths
@@ -424,100 +545,223 @@ func (s *DiseaseService) AddDiseaseData(ctx context.Context, data *models.Diseas
 	return s.CreateDisease(ctx, disease)
 }
 
-// CompareDiseaseTrends compares disease data across multiple years
-func (s *DiseaseService) CompareDiseaseTrends(ctx context.Context, id string, yearsStr string) (*models.DiseaseComparison, error) {
-	// Parse the years from the comma-separated string
-	var years []int
-	for _, yearStr := range strings.Split(yearsStr, ",") {
-		year, err := strconv.Atoi(strings.TrimSpace(yearStr))
-		if err != nil {
-			return nil, fmt.Errorf("invalid year format: %w", err)
-		}
-		years = append(years, year)
+// fillDerivedCounts estimates deaths, cases and recoveries that were not recorded directly
+func fillDerivedCounts(d *models.Disease) {
+	if d.Status.Suppressed() {
+		return
 	}
 
-	// Get the disease name from ID (in a real implementation)
-	diseaseName := strings.Split(id, "_")[0]
+	if d.Deaths == 0 && d.MortalityRate > 0 {
+		d.Deaths = uint32(float64(d.Population) * d.MortalityRate / repository.RatePer)
+	}
 
-	// Query to get yearly totals for the specified disease
-	query := `
-		SELECT 
-			year,
-			SUM(cases) as total_cases
-		FROM diseases
-		WHERE id LIKE ? AND year IN (?)
-		GROUP BY year
-		ORDER BY year
-	`
+	if d.Cases == 0 && d.IncidenceRate > 0 {
+		d.Cases = uint32(float64(d.Population) * d.IncidenceRate / repository.RatePer)
+	}
 
-	// Format the IN clause with the correct number of placeholders
-	args := []interface{}{
-		diseaseName + "%", // LIKE pattern to match disease ID prefix
+	// Simple assumption: recoveries = cases - deaths, clamped at zero for unsigned counts
+	if d.Recoveries == 0 && d.Cases > d.Deaths {
+		d.Recoveries = d.Cases - d.Deaths
 	}
-	for _, year := range years {
-		args = append(args, uint16(year))
+}
+
+// CompareDiseaseTrends compares the yearly and quarterly cases of a disease across years against
+// a baseline year; a zero baseline selects the first requested year
+func (s *DiseaseService) CompareDiseaseTrends(ctx context.Context, id string, yearsStr string, baseline int) (*models.DiseaseComparison, error) {
+	years, err := parseYears(yearsStr)
+	if err != nil {
+		return nil, err
 	}
 
-	inClause := strings.Repeat("?,", len(years))
-	inClause = inClause[:len(inClause)-1] // Remove trailing comma
-	query = strings.Replace(query, "IN (?)", "IN ("+inClause+")", 1)
+	if baseline == 0 {
+		baseline = years[0]
+	}
+	baselineRequested := false
+	for _, y := range years {
+		baselineRequested = baselineRequested || y == baseline
+	}
+	if !baselineRequested {
+		return nil, fmt.Errorf("%w: baseline year %d is not among the compared years", models.ErrInvalidFilter, baseline)
+	}
 
-	// For now, return stub data
-	// In a real implementation, this would execute the query and process results
+	name, err := resolveDiseaseName(ctx, s.repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	first, last := years[0], years[0]
+	for _, y := range years {
+		if y < first {
+			first = y
+		}
+		if y > last {
+			last = y
+		}
+	}
+
+	records, err := s.repo.List(ctx, models.DiseaseFilter{
+		Names:     []string{name},
+		StartYear: &first,
+		EndYear:   &last,
+		Sort:      "year,quarter",
+	})
+	if err != nil {
+		return orDemo(s.demoMode, err, func() *models.DiseaseComparison { return demo.Comparison(name, years) })
+	}
+
+	// Regions of the same quarter add up, both in cases and in population
+	type quarterTotals struct {
+		cases, population uint64
+	}
+	byYear := make(map[int]map[int]*quarterTotals)
+	for _, d := range records {
+		// A suppressed quarter is missing, not a quarter without cases
+		if d.Status.Suppressed() {
+			continue
+		}
+		quarters, ok := byYear[int(d.Year)]
+		if !ok {
+			quarters = make(map[int]*quarterTotals)
+			byYear[int(d.Year)] = quarters
+		}
+		t, ok := quarters[int(d.Quarter)]
+		if !ok {
+			t = &quarterTotals{}
+			quarters[int(d.Quarter)] = t
+		}
+		t.cases += uint64(d.Cases)
+		t.population += uint64(d.Population)
+	}
 
 	comparison := &models.DiseaseComparison{
-		Disease: diseaseName,
-		Data:    make([]models.DiseaseComparisonPoint, 0, len(years)),
+		Disease:      name,
+		BaselineYear: baseline,
+		Data:         make([]models.DiseaseComparisonPoint, 0, len(years)),
+		MissingYears: []int{},
+	}
+
+	var base *models.DiseaseComparisonPoint
+	for _, year := range years {
+		quarters, ok := byYear[year]
+		if !ok {
+			comparison.MissingYears = append(comparison.MissingYears, year)
+			continue
+		}
+
+		point := models.DiseaseComparisonPoint{Year: year, Quarters: []models.QuarterlyValue{}, MissingQuarters: []int{}}
+
+		// The population is a stock, so the yearly denominator is the mean over the reported quarters
+		var populationSum uint64
+		var populationQuarters int
+		for q := 1; q <= 4; q++ {
+			t, ok := quarters[q]
+			if !ok {
+				point.MissingQuarters = append(point.MissingQuarters, q)
+				continue
+			}
+
+			point.Cases += int(t.cases)
+			point.Quarters = append(point.Quarters, models.QuarterlyValue{
+				Quarter:          q,
+				Cases:            int(t.cases),
+				IncidencePer100k: per100k(t.cases, t.population),
+			})
+			if t.population > 0 {
+				populationSum += t.population
+				populationQuarters++
+			}
+		}
+		if populationQuarters > 0 {
+			point.IncidencePer100k = per100k(uint64(point.Cases), populationSum/uint64(populationQuarters))
+		}
+
+		comparison.Data = append(comparison.Data, point)
+		if year == baseline {
+			base = &comparison.Data[len(comparison.Data)-1]
+		}
 	}
 
-	// Add a data point for each year
-	for i, year := range years {
-		// Generate some fake data that varies by year
-		baseValue := 10000 + (year%10)*1000
+	if base == nil {
+		return nil, fmt.Errorf("baseline year %d has no %s records: %w", baseline, name, models.ErrInsufficientData)
+	}
 
-		comparison.Data = append(comparison.Data, models.DiseaseComparisonPoint{
-			Year:  year,
-			Cases: baseValue + i*1500,
-		})
+	baseCases := base.Cases
+	for i := range comparison.Data {
+		p := &comparison.Data[i]
+		delta := p.Cases - baseCases
+		p.DeltaCases = &delta
+		if baseCases > 0 {
+			pct := float64(delta) / float64(baseCases) * 100
+			p.DeltaPercent = &pct
+		}
 	}
 
 	return comparison, nil
 }
 
-// PredictDiseaseCases generates predictions for future disease cases
-func (s *DiseaseService) PredictDiseaseCases(ctx context.Context, id string, year int) (*models.DiseasePrediction, error) {
-	// For now, return stub prediction data
-	// In a real implementation, this would use historical data to generate predictions
-
-	// Get historical data for this disease to base predictions on
-	_ = `
-		SELECT 
-			year, 
-			quarter, 
-			AVG(cases) as avg_cases
-		FROM diseases
-		WHERE id LIKE ?
-		GROUP BY year, quarter
-		ORDER BY year DESC, quarter DESC
-		LIMIT 8
-	`
-
-	_ = strings.Split(id, "_")[0]
-
-	// In a real implementation, we'd execute this query and use the results
-	// to train a forecasting model (ARIMA, Prophet, etc.)
-
-	// For now, generate some reasonable-looking predictions
-	baseValue := 5000 + (year%5)*1500
-	seasonality := []float64{1.2, 0.8, 0.7, 1.3} // Q1, Q2, Q3, Q4 factors
-
-	prediction := &models.DiseasePrediction{
-		Year:             year,
-		Q1PredictedCases: int(float64(baseValue) * seasonality[0]),
-		Q2PredictedCases: int(float64(baseValue) * seasonality[1]),
-		Q3PredictedCases: int(float64(baseValue) * seasonality[2]),
-		Q4PredictedCases: int(float64(baseValue) * seasonality[3]),
+// parseYears parses a comma-separated list of years, dropping duplicates but keeping the order
+func parseYears(yearsStr string) ([]int, error) {
+	var years []int
+	seen := make(map[int]bool)
+	for _, yearStr := range strings.Split(yearsStr, ",") {
+		yearStr = strings.TrimSpace(yearStr)
+		if yearStr == "" {
+			continue
+		}
+		year, err := strconv.Atoi(yearStr)
+		if err != nil || year < 0 || year > 65535 {
+			return nil, fmt.Errorf("%w: invalid year %q", models.ErrInvalidFilter, yearStr)
+		}
+		if !seen[year] {
+			seen[year] = true
+			years = append(years, year)
+		}
+	}
+
+	if len(years) == 0 {
+		return nil, fmt.Errorf("%w: at least one year is required", models.ErrInvalidFilter)
 	}
+	return years, nil
+}
 
+// per100k returns cases per 100,000 population, or nil without a population
+func per100k(cases, population uint64) *float64 {
+	if population == 0 {
+		return nil
+	}
+	rate := float64(cases) / float64(population) * 100000
+	return &rate
+}
+
+// resolveDiseaseName maps a record ID such as "Gripa_2020_1", or a disease key such as "Gripa"
+// or "Hepatita_virala_B" with underscores for spaces, to the disease name it refers to
+func resolveDiseaseName(ctx context.Context, repo repository.DiseaseRepository, id string) (string, error) {
+	d, err := repo.GetByID(ctx, id)
+	if err == nil {
+		return d.Name, nil
+	}
+	if !errors.Is(err, models.ErrNotFound) {
+		return "", err
+	}
+
+	name := strings.ReplaceAll(id, "_", " ")
+	n, err := repo.Count(ctx, models.DiseaseFilter{Names: []string{name}})
+	if err != nil {
+		return "", err
+	}
+	if n == 0 {
+		return "", fmt.Errorf("disease %q: %w", id, models.ErrNotFound)
+	}
+
+	return name, nil
+}
+
+// PredictDiseaseCases forecasts the quarterly cases of a disease for a future year with the named
+// model; an empty model or zero level selects the defaults
+func (s *DiseaseService) PredictDiseaseCases(ctx context.Context, id string, year int, model string, level float64) (*models.DiseasePrediction, error) {
+	prediction, err := s.forecasts.Predict(ctx, id, year, model, level)
+	if err != nil {
+		return orDemo(s.demoMode, err, func() *models.DiseasePrediction { return demo.Prediction(year) })
+	}
 	return prediction, nil
 }
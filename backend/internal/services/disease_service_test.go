@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository/memory"
+)
+
+func TestListDiseasePageFollowsCursors(t *testing.T) {
+	var records []models.Disease
+	for year := uint16(2018); year <= 2022; year++ {
+		for quarter := uint8(1); quarter <= 4; quarter++ {
+			records = append(records, models.Disease{
+				ID:       fmt.Sprintf("flu_%d_%d", year, quarter),
+				Name:     "Gripa",
+				Category: "Respiratory Infections",
+				Region:   "Moldova",
+				Year:     year,
+				Quarter:  quarter,
+				Cases:    uint32(year%7)*100 + uint32(quarter),
+			})
+		}
+	}
+	store := memory.NewStore(memory.Seed{Diseases: records})
+	service := NewDiseaseService(store.Diseases, nil, nil, nil, false)
+
+	for _, sort := range []string{"", "-cases", "quarter,-year"} {
+		filter := models.DiseaseFilter{Sort: sort, Limit: 6, StartYear: intPtr(2019)}
+		seen := make(map[string]bool)
+		pages := 0
+		for {
+			page, err := service.ListDiseasePage(context.Background(), filter)
+			if err != nil {
+				t.Fatalf("sort %q: %v", sort, err)
+			}
+			pages++
+			if page.TotalCount != 16 || page.TotalPages != 3 {
+				t.Errorf("sort %q: totalCount %d, totalPages %d; want 16 and 3", sort, page.TotalCount, page.TotalPages)
+			}
+			for _, d := range page.Diseases {
+				if seen[d.ID] {
+					t.Errorf("sort %q: %s listed twice", sort, d.ID)
+				}
+				seen[d.ID] = true
+			}
+			if !page.HasMore {
+				break
+			}
+			filter.Cursor = page.NextCursor
+		}
+		if len(seen) != 16 || pages != 3 {
+			t.Errorf("sort %q: listed %d records over %d pages, want 16 over 3", sort, len(seen), pages)
+		}
+	}
+}
+
+func intPtr(v int) *int { return &v }
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// EnvironmentService serves environment observations and joins them onto disease records
+type EnvironmentService struct {
+	repo repository.EnvironmentRepository
+}
+
+// NewEnvironmentService creates a new EnvironmentService
+func NewEnvironmentService(repo repository.EnvironmentRepository) *EnvironmentService {
+	return &EnvironmentService{repo: repo}
+}
+
+// Series returns one series per station and variable matching the filter, monthly or rolled up
+// into quarters
+func (s *EnvironmentService) Series(ctx context.Context, filter models.EnvironmentFilter) ([]models.EnvironmentSeries, error) {
+	granularity := filter.Granularity
+	if granularity == "" {
+		granularity = models.GranularityQuarter
+	}
+
+	observations, err := s.repo.Observations(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ station, variable string }
+	groups := make(map[key][]models.EnvironmentObservation)
+	var keys []key
+	for _, o := range observations {
+		k := key{o.Station, o.Variable}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], o)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].station != keys[j].station {
+			return keys[i].station < keys[j].station
+		}
+		return keys[i].variable < keys[j].variable
+	})
+
+	series := make([]models.EnvironmentSeries, 0, len(keys))
+	for _, k := range keys {
+		points := repository.QuarterlyRollup(k.variable, groups[k])
+		if granularity == models.GranularityMonth {
+			points = repository.MonthlyMeans(k.variable, groups[k])
+		}
+
+		series = append(series, models.EnvironmentSeries{
+			Station:     k.station,
+			Variable:    k.variable,
+			Unit:        models.EnvironmentVariables[k.variable].Unit,
+			Aggregation: models.VariableAggregation(k.variable),
+			Granularity: granularity,
+			Points:      points,
+		})
+	}
+
+	return series, nil
+}
+
+// Stations returns the stations that have observations
+func (s *EnvironmentService) Stations(ctx context.Context) ([]models.Station, error) {
+	stations, err := s.repo.Stations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stations == nil {
+		stations = []models.Station{}
+	}
+	return stations, nil
+}
+
+// Emissions returns the yearly series of each pollutant matching the filter
+func (s *EnvironmentService) Emissions(ctx context.Context, filter models.EmissionFilter) ([]models.EmissionSeries, error) {
+	emissions, err := s.repo.Emissions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Emissions come ordered by pollutant and year
+	series := []models.EmissionSeries{}
+	for _, e := range emissions {
+		if n := len(series); n == 0 || series[n-1].Pollutant != e.Pollutant {
+			series = append(series, models.EmissionSeries{Pollutant: e.Pollutant, Unit: models.EmissionUnit})
+		}
+		line := &series[len(series)-1]
+		line.Points = append(line.Points, models.EmissionPoint{Year: int(e.Year), Value: e.Value, Status: e.Status})
+	}
+	return series, nil
+}
+
+// Attach sets the EnvironmentData of each record to the quarterly rollups of every variable for
+// the record's quarter, averaged over the stations. Variables without a complete quarter are left out.
+func (s *EnvironmentService) Attach(ctx context.Context, diseases []models.Disease) error {
+	if len(diseases) == 0 {
+		return nil
+	}
+
+	startYear, endYear := int(diseases[0].Year), int(diseases[0].Year)
+	for _, d := range diseases {
+		if int(d.Year) < startYear {
+			startYear = int(d.Year)
+		}
+		if int(d.Year) > endYear {
+			endYear = int(d.Year)
+		}
+	}
+
+	factors, err := s.repo.Factors(ctx)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[int]map[string]any)
+	for _, factor := range factors {
+		points, err := s.repo.Quarterly(ctx, factor, &startYear, &endYear)
+		if err != nil {
+			return err
+		}
+		for _, p := range points {
+			idx := quarterIndex(p.Year, p.Quarter)
+			if values[idx] == nil {
+				values[idx] = make(map[string]any)
+			}
+			values[idx][factor] = p.Value
+		}
+	}
+
+	for i := range diseases {
+		d := &diseases[i]
+		d.EnvironmentData = make(map[string]any)
+		for factor, v := range values[quarterIndex(int(d.Year), int(d.Quarter))] {
+			d.EnvironmentData[factor] = v
+		}
+	}
+
+	return nil
+}
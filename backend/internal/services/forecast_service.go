@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ktruedat/healthisis/backend/internal/forecast"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// quartersPerYear is the seasonal period of the quarterly disease series
+const quartersPerYear = 4
+
+// maxForecastYears bounds how far past the last observation a forecast may reach
+const maxForecastYears = 10
+
+// Backtest defaults: origins from 2015 Q1 onwards, scored up to a year ahead
+const (
+	defaultBacktestStartYear = 2015
+	defaultBacktestHorizon   = 4
+	maxBacktestHorizon       = 12
+)
+
+// ForecastService fits forecasting models to the quarterly case series of diseases and
+// measures their accuracy with rolling-origin backtests
+type ForecastService struct {
+	diseases  repository.DiseaseRepository
+	backtests repository.BacktestRepository
+}
+
+// NewForecastService creates a new ForecastService
+func NewForecastService(diseases repository.DiseaseRepository, backtests repository.BacktestRepository) *ForecastService {
+	return &ForecastService{diseases: diseases, backtests: backtests}
+}
+
+// QuarterlySeries is the gap-free quarterly case series of one disease
+type QuarterlySeries struct {
+	Disease string
+	Start   models.YearQuarter
+	End     models.YearQuarter
+	Cases   []float64
+}
+
+// Series returns the longest gap-free run of quarterly case totals of a disease that ends at
+// its latest recorded quarter; regions are summed
+func (s *ForecastService) Series(ctx context.Context, id string) (*QuarterlySeries, error) {
+	name, err := resolveDiseaseName(ctx, s.diseases, id)
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := s.diseases.TimeSeries(ctx, models.DiseaseFilter{Names: []string{name}})
+	if err != nil {
+		return nil, err
+	}
+	points = reportedPoints(points)
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no %s records: %w", name, models.ErrInsufficientData)
+	}
+
+	return contiguousSeries(name, points), nil
+}
+
+// contiguousSeries builds the series of the gap-free run of points ending at the latest one;
+// points must belong to a single disease and be ordered by year and quarter
+func contiguousSeries(name string, points []models.DiseaseTimePoint) *QuarterlySeries {
+	// Walk back from the latest quarter while each point directly follows the previous one
+	first := len(points) - 1
+	for first > 0 && quarterIndex(points[first-1].Year, points[first-1].Quarter)+1 == quarterIndex(points[first].Year, points[first].Quarter) {
+		first--
+	}
+
+	series := &QuarterlySeries{
+		Disease: name,
+		Start:   models.YearQuarter{Year: points[first].Year, Quarter: points[first].Quarter},
+		End:     models.YearQuarter{Year: points[len(points)-1].Year, Quarter: points[len(points)-1].Quarter},
+	}
+	for _, p := range points[first:] {
+		series.Cases = append(series.Cases, float64(p.Cases))
+	}
+
+	return series
+}
+
+// at returns the year and quarter of the i-th observation of the series
+func (q *QuarterlySeries) at(i int) models.YearQuarter {
+	idx := quarterIndex(q.Start.Year, q.Start.Quarter) + i
+	return models.YearQuarter{Year: idx / quartersPerYear, Quarter: idx%quartersPerYear + 1}
+}
+
+// Predict forecasts the four quarters of a year after the last observation of a disease
+func (s *ForecastService) Predict(ctx context.Context, id string, year int, model string, level float64) (*models.DiseasePrediction, error) {
+	if model == "" {
+		model = forecast.DefaultModel
+	}
+	if level == 0 {
+		level = forecast.DefaultLevel
+	}
+	m, err := forecast.New(model)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidFilter, err)
+	}
+	if level <= 0 || level >= 1 {
+		return nil, fmt.Errorf("%w: level must be between 0 and 1, got %g", models.ErrInvalidFilter, level)
+	}
+
+	series, err := s.Series(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if year <= series.End.Year {
+		return nil, fmt.Errorf("%w: year %d is not after the last observed year %d", models.ErrInvalidFilter, year, series.End.Year)
+	}
+	if year-series.End.Year > maxForecastYears {
+		return nil, fmt.Errorf("%w: year %d is more than %d years after the last observation", models.ErrInvalidFilter, year, maxForecastYears)
+	}
+
+	// The horizon runs from the quarter after the last observation to the fourth quarter of year
+	horizon := quarterIndex(year, 4) - quarterIndex(series.End.Year, series.End.Quarter)
+	f, err := m.Forecast(series.Cases, quartersPerYear, horizon, level)
+	if err != nil {
+		if errors.Is(err, forecast.ErrSeriesTooShort) || errors.Is(err, forecast.ErrNonPositive) {
+			return nil, fmt.Errorf("%s series %d-Q%d to %d-Q%d: %v: %w", series.Disease,
+				series.Start.Year, series.Start.Quarter, series.End.Year, series.End.Quarter, err, models.ErrInsufficientData)
+		}
+		return nil, fmt.Errorf("error fitting %s: %w", model, err)
+	}
+
+	prediction := &models.DiseasePrediction{
+		Disease:       series.Disease,
+		Year:          year,
+		Model:         f.Model,
+		Level:         f.Level,
+		Params:        f.Params,
+		TrainingStart: series.Start,
+		TrainingEnd:   series.End,
+	}
+
+	// Counts cannot be negative, so means and bounds are clamped at zero
+	for q := 1; q <= quartersPerYear; q++ {
+		h := horizon - quartersPerYear + q - 1
+		prediction.Quarters = append(prediction.Quarters, models.QuarterPrediction{
+			Quarter:        q,
+			PredictedCases: math.Max(f.Mean[h], 0),
+			Lower:          math.Max(f.Lower[h], 0),
+			Upper:          math.Max(f.Upper[h], 0),
+		})
+	}
+
+	// Only a backtest scored at the horizon and level of the forecast describes its accuracy
+	backtests, err := s.backtests.List(ctx, series.Disease, f.Model)
+	if err != nil {
+		return nil, err
+	}
+	for i := range backtests {
+		if backtests[i].Horizon == horizon && backtests[i].Level == f.Level {
+			prediction.Accuracy = &backtests[i]
+			break
+		}
+	}
+
+	rounded := func(q int) int { return int(math.Round(prediction.Quarters[q-1].PredictedCases)) }
+	prediction.Q1PredictedCases = rounded(1)
+	prediction.Q2PredictedCases = rounded(2)
+	prediction.Q3PredictedCases = rounded(3)
+	prediction.Q4PredictedCases = rounded(4)
+
+	return prediction, nil
+}
+
+// Backtest runs rolling-origin backtests of the requested models on the requested diseases and
+// stores the results. Pairs whose series cannot support a backtest are reported as skipped.
+func (s *ForecastService) Backtest(ctx context.Context, req *models.BacktestRequest) (*models.BacktestReport, error) {
+	horizon := req.Horizon
+	if horizon == 0 {
+		horizon = defaultBacktestHorizon
+	}
+	if horizon < 1 || horizon > maxBacktestHorizon {
+		return nil, fmt.Errorf("%w: horizon must be between 1 and %d", models.ErrInvalidFilter, maxBacktestHorizon)
+	}
+
+	level := req.Level
+	if level == 0 {
+		level = forecast.DefaultLevel
+	}
+	if level <= 0 || level >= 1 {
+		return nil, fmt.Errorf("%w: level must be between 0 and 1, got %g", models.ErrInvalidFilter, level)
+	}
+
+	start := models.YearQuarter{Year: req.StartYear, Quarter: req.StartQuarter}
+	if start.Year == 0 {
+		start.Year = defaultBacktestStartYear
+	}
+	if start.Quarter == 0 {
+		start.Quarter = 1
+	}
+	if start.Quarter < 1 || start.Quarter > quartersPerYear {
+		return nil, fmt.Errorf("%w: start_quarter must be between 1 and 4", models.ErrInvalidFilter)
+	}
+
+	// A series a model cannot fit fails the run when the model was requested, and is reported as
+	// skipped when it was only run by default
+	names := req.Models
+	requested := make(map[string]bool, len(names))
+	for _, name := range names {
+		requested[name] = true
+	}
+	if len(names) == 0 {
+		names = forecast.Names()
+	}
+	var fitters []forecast.Model
+	for _, name := range names {
+		m, err := forecast.New(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrInvalidFilter, err)
+		}
+		fitters = append(fitters, m)
+	}
+
+	series, err := s.backtestSeries(ctx, req.DiseaseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.BacktestReport{Results: []models.BacktestResult{}, Skipped: []models.BacktestSkip{}}
+	runAt := time.Now().UTC().Truncate(time.Second)
+
+	for _, q := range series {
+		firstOrigin := quarterIndex(start.Year, start.Quarter) - quarterIndex(q.Start.Year, q.Start.Quarter)
+		for _, m := range fitters {
+			acc, err := forecast.Backtest(m, q.Cases, quartersPerYear, firstOrigin, horizon, level)
+			if errors.Is(err, forecast.ErrNonPositive) && requested[m.Name()] {
+				return nil, fmt.Errorf("%s on %s: %v: %w", m.Name(), q.Disease, err, models.ErrInsufficientData)
+			}
+			if errors.Is(err, forecast.ErrSeriesTooShort) || errors.Is(err, forecast.ErrNonPositive) {
+				report.Skipped = append(report.Skipped, models.BacktestSkip{Disease: q.Disease, Model: m.Name(), Reason: err.Error()})
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error backtesting %s on %s: %w", m.Name(), q.Disease, err)
+			}
+
+			report.Results = append(report.Results, models.BacktestResult{
+				Disease:        q.Disease,
+				Model:          m.Name(),
+				Horizon:        horizon,
+				Level:          level,
+				FirstOrigin:    q.at(acc.FirstOrigin),
+				LastOrigin:     q.at(acc.LastOrigin),
+				Origins:        acc.Origins,
+				SkippedOrigins: acc.Skipped,
+				Forecasts:      acc.Forecasts,
+				MAE:            acc.MAE,
+				MAPE:           acc.MAPE,
+				SMAPE:          acc.SMAPE,
+				MASE:           acc.MASE,
+				Coverage:       acc.Coverage,
+				RunAt:          runAt,
+			})
+		}
+	}
+
+	if len(report.Results) > 0 {
+		if err := s.backtests.Save(ctx, report.Results); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// BacktestResults returns the stored backtest results, optionally for one disease and model
+func (s *ForecastService) BacktestResults(ctx context.Context, id, model string) ([]models.BacktestResult, error) {
+	disease := ""
+	if id != "" {
+		name, err := resolveDiseaseName(ctx, s.diseases, id)
+		if err != nil {
+			return nil, err
+		}
+		disease = name
+	}
+
+	results, err := s.backtests.List(ctx, disease, model)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []models.BacktestResult{}
+	}
+	return results, nil
+}
+
+// backtestSeries returns the series of the given diseases, or of every disease when ids is empty
+func (s *ForecastService) backtestSeries(ctx context.Context, ids []string) ([]*QuarterlySeries, error) {
+	if len(ids) > 0 {
+		var series []*QuarterlySeries
+		for _, id := range ids {
+			q, err := s.Series(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			series = append(series, q)
+		}
+		return series, nil
+	}
+
+	points, err := s.diseases.TimeSeries(ctx, models.DiseaseFilter{})
+	if err != nil {
+		return nil, err
+	}
+	points = reportedPoints(points)
+
+	byName := make(map[string][]models.DiseaseTimePoint)
+	var names []string
+	for _, p := range points {
+		if _, ok := byName[p.Name]; !ok {
+			names = append(names, p.Name)
+		}
+		byName[p.Name] = append(byName[p.Name], p)
+	}
+	sort.Strings(names)
+
+	series := make([]*QuarterlySeries, 0, len(names))
+	for _, name := range names {
+		series = append(series, contiguousSeries(name, byName[name]))
+	}
+	return series, nil
+}
+
+// reportedPoints drops the points no cell reported a value for, leaving gaps in their place
+func reportedPoints(points []models.DiseaseTimePoint) []models.DiseaseTimePoint {
+	reported := points[:0:0]
+	for _, p := range points {
+		if p.Reported() {
+			reported = append(reported, p)
+		}
+	}
+	return reported
+}
+
+// quarterIndex numbers quarters consecutively across years
+func quarterIndex(year, quarter int) int {
+	return year*quartersPerYear + quarter - 1
+}
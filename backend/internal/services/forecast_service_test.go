@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ktruedat/healthisis/backend/internal/forecast"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository/memory"
+)
+
+// forecastStore seeds quarterly cases of Gripa from 2015 to 2022 and of Rujeola, whose first
+// quarter had no cases
+func forecastStore() memory.Seed {
+	var diseases []models.Disease
+	for year := 2015; year <= 2022; year++ {
+		for quarter := 1; quarter <= 4; quarter++ {
+			t := (year-2015)*4 + quarter - 1
+			diseases = append(diseases,
+				models.Disease{ID: fmt.Sprintf("flu_%d_%d", year, quarter), Name: "Gripa", Region: models.NationalRegion,
+					Year: uint16(year), Quarter: uint8(quarter), Cases: uint32(1000 + 10*t + 300*(quarter%2))},
+				models.Disease{ID: fmt.Sprintf("rujeola_%d_%d", year, quarter), Name: "Rujeola", Region: models.NationalRegion,
+					Year: uint16(year), Quarter: uint8(quarter), Cases: uint32(t * 3)},
+			)
+		}
+	}
+	return memory.Seed{Diseases: diseases}
+}
+
+func TestPredictReportsBacktestOfItsHorizonAndLevel(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore(forecastStore())
+	service := NewForecastService(store.Diseases, store.Backtests)
+
+	runAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	backtest := func(horizon int, level float64, runAt time.Time) models.BacktestResult {
+		return models.BacktestResult{Disease: "Gripa", Model: forecast.HoltWintersAdditive, Horizon: horizon, Level: level, RunAt: runAt}
+	}
+	if err := store.Backtests.Save(ctx, []models.BacktestResult{
+		backtest(1, 0.95, runAt.Add(2*time.Hour)),
+		backtest(4, 0.8, runAt.Add(time.Hour)),
+	}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// 2023 lies four quarters after the last observation
+	prediction, err := service.Predict(ctx, "Gripa", 2023, forecast.HoltWintersAdditive, 0.95)
+	if err != nil {
+		t.Fatalf("Predict: %v", err)
+	}
+	if prediction.Accuracy != nil {
+		t.Errorf("accuracy = %+v, want none without a backtest four quarters ahead at 95%%", *prediction.Accuracy)
+	}
+
+	if err := store.Backtests.Save(ctx, []models.BacktestResult{backtest(4, 0.95, runAt)}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	prediction, err = service.Predict(ctx, "Gripa", 2023, forecast.HoltWintersAdditive, 0.95)
+	if err != nil {
+		t.Fatalf("Predict: %v", err)
+	}
+	if a := prediction.Accuracy; a == nil || a.Horizon != 4 || a.Level != 0.95 {
+		t.Errorf("accuracy = %+v, want the backtest four quarters ahead at 95%%", a)
+	}
+}
+
+func TestBacktestRejectsNonPositiveSeriesOfRequestedModel(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore(forecastStore())
+	service := NewForecastService(store.Diseases, store.Backtests)
+
+	_, err := service.Backtest(ctx, &models.BacktestRequest{
+		DiseaseIDs: []string{"Rujeola"},
+		Models:     []string{forecast.HoltWintersMultiplicative},
+	})
+	if !errors.Is(err, models.ErrInsufficientData) || !strings.Contains(err.Error(), forecast.ErrNonPositive.Error()) {
+		t.Errorf("error = %v, want ErrInsufficientData naming the non-positive values", err)
+	}
+
+	// Run by default, the model is skipped for that reason while the others are scored
+	report, err := service.Backtest(ctx, &models.BacktestRequest{DiseaseIDs: []string{"Rujeola"}})
+	if err != nil {
+		t.Fatalf("Backtest: %v", err)
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0].Model != forecast.HoltWintersMultiplicative ||
+		!strings.Contains(report.Skipped[0].Reason, forecast.ErrNonPositive.Error()) {
+		t.Errorf("skipped = %+v, want the multiplicative model for its non-positive values", report.Skipped)
+	}
+	if len(report.Results) != len(forecast.Names())-1 {
+		t.Errorf("%d results, want one per other model", len(report.Results))
+	}
+}
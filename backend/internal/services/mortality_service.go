@@ -0,0 +1,450 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/pkg/lifetable"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// MortalityService builds life tables from the deaths and population by age, compares them with
+// the official life expectancy and decomposes its changes by age
+type MortalityService struct {
+	stratified repository.StratifiedRepository
+	population repository.PopulationRepository
+}
+
+// NewMortalityService creates a new MortalityService
+func NewMortalityService(stratified repository.StratifiedRepository, population repository.PopulationRepository) *MortalityService {
+	return &MortalityService{stratified: stratified, population: population}
+}
+
+// lifeTables holds the life tables of each year and stratum, or why one could not be built
+type lifeTables struct {
+	tables  map[stratumYear]lifetable.Table
+	failed  map[stratumYear]error
+	unknown map[stratumYear]float64 // deaths of undeclared age
+}
+
+// years returns the years with deaths in a stratum, oldest first
+func (t *lifeTables) years(sex, medium string) []int {
+	var years []int
+	for k := range t.failed {
+		if k.sex == sex && k.medium == medium {
+			years = append(years, k.year)
+		}
+	}
+	for k := range t.tables {
+		if k.sex == sex && k.medium == medium {
+			years = append(years, k.year)
+		}
+	}
+	sort.Ints(years)
+	return years
+}
+
+// table returns the life table of a year and stratum
+func (t *lifeTables) table(k stratumYear) (lifetable.Table, error) {
+	if err := t.failed[k]; err != nil {
+		return nil, err
+	}
+	table, ok := t.tables[k]
+	if !ok {
+		return nil, fmt.Errorf("%w: no deaths in %d", models.ErrNotFound, k.year)
+	}
+	return table, nil
+}
+
+// LifeTable returns the abridged life table of a year and stratum with the official life
+// expectancy of its ages where published
+func (s *MortalityService) LifeTable(ctx context.Context, q models.LifeTableQuery) (*models.LifeTable, error) {
+	if err := checkMortalityStratum(q.Sex, q.Medium, q.Year); err != nil {
+		return nil, err
+	}
+
+	tables, err := s.lifeTables(ctx, q.Year, q.Year, q.Sex, q.Medium)
+	if err != nil {
+		return nil, err
+	}
+	years := tables.years(q.Sex, q.Medium)
+	if len(years) == 0 {
+		return nil, fmt.Errorf("%w: no deaths for the life table", models.ErrNotFound)
+	}
+	year := years[len(years)-1]
+
+	k := stratumYear{year: year, sex: q.Sex, medium: q.Medium}
+	table, err := tables.table(k)
+	if err != nil {
+		return nil, err
+	}
+
+	var official map[lifeExpectancyKey]float64
+	if q.Sex != "" && q.Medium != "" {
+		// Life expectancy is only published per sex and medium
+		if official, err = s.official(ctx, models.StratifiedFilter{
+			StartYear: &year,
+			EndYear:   &year,
+			Sexes:     []string{q.Sex},
+			Media:     []string{q.Medium},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &models.LifeTable{
+		Year:             year,
+		Sex:              q.Sex,
+		Medium:           q.Medium,
+		LifeExpectancy:   table[0].Ex,
+		UnknownAgeDeaths: tables.unknown[k],
+		Rows:             make([]models.LifeTableRow, 0, len(table)),
+	}
+	for _, r := range table {
+		row := models.LifeTableRow{
+			Age:         lifeTableAge(r.Start, r.Width),
+			Deaths:      r.Deaths,
+			Population:  r.Exposure,
+			Mx:          r.Mx,
+			Ax:          r.Ax,
+			Qx:          r.Qx,
+			Lx:          r.Lx,
+			Dx:          r.Dx,
+			PersonYears: r.PersonYears,
+			Tx:          r.Tx,
+			Ex:          r.Ex,
+		}
+		if e, ok := official[lifeExpectancyKey{stratumYear: k, age: r.Start}]; ok {
+			row.OfficialLifeExpectancy = &e
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, nil
+}
+
+// LifeExpectancy returns the official and computed life expectancy per age, sex and medium over
+// the filter's years. Ages are the first ages of the life table groups and default to birth; the
+// filter's indicator and grouping are ignored.
+func (s *MortalityService) LifeExpectancy(ctx context.Context, filter models.StratifiedFilter) ([]models.LifeExpectancySeries, error) {
+	filter.Indicator = models.IndicatorLifeExpectancy
+	filter.GroupBy, filter.BandWidth = nil, 0
+	if _, err := repository.CompileStratifiedFilter(filter); err != nil {
+		return nil, err
+	}
+
+	if len(filter.Ages) == 0 {
+		filter.Ages = []string{"0"}
+	}
+	ages := make([]int, 0, len(filter.Ages))
+	for _, a := range filter.Ages {
+		age, err := strconv.Atoi(a)
+		i := sort.SearchInts(lifetable.StandardStarts, age)
+		if err != nil || i == len(lifetable.StandardStarts) || lifetable.StandardStarts[i] != age {
+			return nil, fmt.Errorf("%w: ages: %q is not the first age of a life table group", models.ErrInvalidFilter, a)
+		}
+		ages = append(ages, age)
+	}
+	sexes, media := filter.Sexes, filter.Media
+	if len(sexes) == 0 {
+		sexes = []string{models.SexFemale, models.SexMale}
+	}
+	if len(media) == 0 {
+		media = []string{models.MediumRural, models.MediumUrban}
+	}
+
+	official, err := s.official(ctx, models.StratifiedFilter{
+		StartYear: filter.StartYear,
+		EndYear:   filter.EndYear,
+		Sexes:     sexes,
+		Media:     media,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	series := []models.LifeExpectancySeries{}
+	for _, sex := range sexes {
+		for _, medium := range media {
+			tables, err := s.lifeTables(ctx, filter.StartYear, filter.EndYear, sex, medium)
+			if err != nil {
+				return nil, err
+			}
+
+			yearSet := make(map[int]bool)
+			for _, y := range tables.years(sex, medium) {
+				yearSet[y] = true
+			}
+			for k := range official {
+				if k.sex == sex && k.medium == medium {
+					yearSet[k.year] = true
+				}
+			}
+			years := make([]int, 0, len(yearSet))
+			for y := range yearSet {
+				years = append(years, y)
+			}
+			sort.Ints(years)
+
+			for _, age := range ages {
+				line := models.LifeExpectancySeries{Age: strconv.Itoa(age), Sex: sex, Medium: medium, Points: []models.LifeExpectancyPoint{}}
+				for _, year := range years {
+					k := stratumYear{year: year, sex: sex, medium: medium}
+					p := models.LifeExpectancyPoint{Year: year}
+					if e, ok := official[lifeExpectancyKey{stratumYear: k, age: age}]; ok {
+						p.Official = &e
+					}
+					for _, r := range tables.tables[k] {
+						if r.Start == age {
+							e := r.Ex
+							p.Computed = &e
+						}
+					}
+					if p.Official != nil && p.Computed != nil {
+						d := *p.Computed - *p.Official
+						p.Difference = &d
+					}
+					line.Points = append(line.Points, p)
+				}
+				series = append(series, line)
+			}
+		}
+	}
+	return series, nil
+}
+
+// Decomposition decomposes the change in life expectancy at birth between two years in a stratum
+// by age group with Arriaga's method
+func (s *MortalityService) Decomposition(ctx context.Context, q models.DecompositionQuery) (*models.LifeExpectancyDecomposition, error) {
+	if err := checkMortalityStratum(q.Sex, q.Medium, q.FromYear); err != nil {
+		return nil, err
+	}
+	if err := checkMortalityStratum(q.Sex, q.Medium, q.ToYear); err != nil {
+		return nil, err
+	}
+
+	tables, err := s.lifeTables(ctx, nil, nil, q.Sex, q.Medium)
+	if err != nil {
+		return nil, err
+	}
+	years := tables.years(q.Sex, q.Medium)
+	if len(years) == 0 {
+		return nil, fmt.Errorf("%w: no deaths for the decomposition", models.ErrNotFound)
+	}
+	from, to := years[0], years[len(years)-1]
+	if q.FromYear != nil {
+		from = *q.FromYear
+	}
+	if q.ToYear != nil {
+		to = *q.ToYear
+	}
+
+	fromTable, err := tables.table(stratumYear{year: from, sex: q.Sex, medium: q.Medium})
+	if err != nil {
+		return nil, err
+	}
+	toTable, err := tables.table(stratumYear{year: to, sex: q.Sex, medium: q.Medium})
+	if err != nil {
+		return nil, err
+	}
+	contributions, err := lifetable.Arriaga(fromTable, toTable)
+	if err != nil {
+		return nil, fmt.Errorf("error decomposing life expectancy: %w", err)
+	}
+
+	result := &models.LifeExpectancyDecomposition{
+		FromYear:           from,
+		ToYear:             to,
+		Sex:                q.Sex,
+		Medium:             q.Medium,
+		FromLifeExpectancy: fromTable[0].Ex,
+		ToLifeExpectancy:   toTable[0].Ex,
+		Change:             toTable[0].Ex - fromTable[0].Ex,
+		Contributions:      make([]models.LifeExpectancyContribution, 0, len(contributions)),
+	}
+	for _, c := range contributions {
+		result.Contributions = append(result.Contributions, models.LifeExpectancyContribution{
+			Age:      lifeTableAge(c.Start, c.Width),
+			Direct:   c.Direct,
+			Indirect: c.Indirect,
+			Total:    c.Total(),
+		})
+	}
+	return result, nil
+}
+
+// lifeTables builds the life tables of a stratum in the given years from the deaths and the
+// mid-year population summed into the abridged groups; empty sex and medium sum over them
+func (s *MortalityService) lifeTables(ctx context.Context, startYear, endYear *int, sex, medium string) (*lifeTables, error) {
+	var sexes, media []string
+	if sex != "" {
+		sexes = []string{sex}
+	}
+	if medium != "" {
+		media = []string{medium}
+	}
+	key := func(year uint16) stratumYear {
+		return stratumYear{year: int(year), sex: sex, medium: medium}
+	}
+
+	facts, err := s.stratified.Facts(ctx, models.StratifiedFilter{
+		Indicator: models.IndicatorDeaths,
+		StartYear: startYear,
+		EndYear:   endYear,
+		Sexes:     sexes,
+		Media:     media,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing deaths: %w", err)
+	}
+
+	tables := &lifeTables{
+		tables:  make(map[stratumYear]lifetable.Table),
+		failed:  make(map[stratumYear]error),
+		unknown: make(map[stratumYear]float64),
+	}
+	deaths := make(map[stratumYear][]float64)
+	for _, f := range facts {
+		if f.Age == models.AgeTotal {
+			continue
+		}
+		k := key(f.Year)
+		if deaths[k] == nil {
+			deaths[k] = make([]float64, len(lifetable.StandardStarts))
+		}
+		if f.Status.Suppressed() {
+			tables.failed[k] = fmt.Errorf("%w: deaths aged %s in %d are %s", models.ErrInsufficientData, f.Age, f.Year, f.Status)
+			continue
+		}
+		i, ok := lifeTableGroup(f.Age)
+		if !ok {
+			tables.unknown[k] += f.Value
+			continue
+		}
+		deaths[k][i] += f.Value
+	}
+	if len(deaths) == 0 {
+		return tables, nil
+	}
+
+	filter := models.PopulationFilter{StartYear: startYear, Sexes: sexes, Media: media}
+	if endYear != nil {
+		// The year after the range is needed to interpolate the last mid-year population
+		next := *endYear + 1
+		filter.EndYear = &next
+	}
+	entries, err := s.population.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing population: %w", err)
+	}
+	january1 := make(map[stratumYear][]float64)
+	for _, e := range entries {
+		i, ok := lifeTableGroup(e.Age)
+		if !ok {
+			continue
+		}
+		k := key(e.Year)
+		if january1[k] == nil {
+			january1[k] = make([]float64, len(lifetable.StandardStarts))
+		}
+		january1[k][i] += float64(e.Population)
+	}
+
+	for k, d := range deaths {
+		if tables.failed[k] != nil {
+			continue
+		}
+		population := january1[k]
+		if population == nil {
+			tables.failed[k] = fmt.Errorf("%w: no population in %d", models.ErrInsufficientData, k.year)
+			continue
+		}
+		next := january1[stratumYear{year: k.year + 1, sex: k.sex, medium: k.medium}]
+
+		groups := make([]lifetable.Group, len(lifetable.StandardStarts))
+		for i, start := range lifetable.StandardStarts {
+			groups[i] = lifetable.Group{Start: start, Deaths: d[i], Population: population[i]}
+			if i+1 < len(lifetable.StandardStarts) {
+				groups[i].Width = lifetable.StandardStarts[i+1] - start
+			}
+			if next != nil {
+				groups[i].Population = (population[i] + next[i]) / 2
+			}
+		}
+
+		// The sexes of the population table are those of the separation factors
+		table, err := lifetable.Abridged(groups, k.sex)
+		if err != nil {
+			if errors.Is(err, lifetable.ErrNoPopulation) || errors.Is(err, lifetable.ErrNoDeaths) {
+				err = fmt.Errorf("%w: life table of %d: %v", models.ErrInsufficientData, k.year, err)
+			}
+			tables.failed[k] = err
+			continue
+		}
+		tables.tables[k] = table
+	}
+	return tables, nil
+}
+
+// lifeExpectancyKey identifies an official life expectancy by year, stratum and age
+type lifeExpectancyKey struct {
+	stratumYear
+	age int
+}
+
+// official returns the published life expectancy facts matching the filter
+func (s *MortalityService) official(ctx context.Context, filter models.StratifiedFilter) (map[lifeExpectancyKey]float64, error) {
+	filter.Indicator = models.IndicatorLifeExpectancy
+	facts, err := s.stratified.Facts(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing life expectancy: %w", err)
+	}
+
+	official := make(map[lifeExpectancyKey]float64, len(facts))
+	for _, f := range facts {
+		age, _, ok := repository.AgeRange(f.Age)
+		if !ok || f.Status.Suppressed() {
+			continue
+		}
+		official[lifeExpectancyKey{stratumYear: stratumYear{year: int(f.Year), sex: f.Sex, medium: f.Medium}, age: age}] = f.Value
+	}
+	return official, nil
+}
+
+// checkMortalityStratum validates the stratum and year of a life table; empty sex and medium are
+// valid and sum over them
+func checkMortalityStratum(sex, medium string, year *int) error {
+	filter := models.PopulationFilter{StartYear: year}
+	if sex != "" {
+		filter.Sexes = []string{sex}
+	}
+	if medium != "" {
+		filter.Media = []string{medium}
+	}
+	_, err := repository.CompilePopulationFilter(filter)
+	return err
+}
+
+// lifeTableGroup returns the index of the abridged group containing the first year of an age
+func lifeTableGroup(age string) (int, bool) {
+	first, _, ok := repository.AgeRange(age)
+	if !ok {
+		return 0, false
+	}
+	return sort.SearchInts(lifetable.StandardStarts, first+1) - 1, true
+}
+
+// lifeTableAge labels an abridged group like the source ages: "0", "1-4" or "85+"
+func lifeTableAge(start, width int) string {
+	switch width {
+	case 0:
+		return strconv.Itoa(start) + "+"
+	case 1:
+		return strconv.Itoa(start)
+	default:
+		return fmt.Sprintf("%d-%d", start, start+width-1)
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/pkg/lifetable"
+	"github.com/ktruedat/healthisis/backend/internal/repository/memory"
+)
+
+// mortalityStore seeds the deaths of urban men in 2020 at the rates of Austrian males in 1992, and
+// in 2021 the same deaths but for half of those aged 60-64, more than can die in five years. The
+// population is the same on January 1 of 2020 to 2022, so it is also the mid-year population.
+func mortalityStore() (*MortalityService, []lifetable.Group) {
+	deaths := []float64{419, 70, 36, 46, 249, 420, 403, 441, 508, 769, 1154, 1866, 2043, 3496, 4366, 4337, 5279, 6460, 6146}
+	population := []uint32{
+		47925, 189127, 234793, 238790, 254996, 326831, 355086, 324222, 269963, 261971,
+		238011, 261612, 181385, 187962, 153832, 105169, 73694, 57512, 32248,
+	}
+
+	var facts []models.StratifiedFact
+	var entries []models.PopulationEntry
+	groups := make([]lifetable.Group, len(lifetable.StandardStarts))
+	for i, start := range lifetable.StandardStarts {
+		groups[i] = lifetable.Group{Start: start, Deaths: deaths[i], Population: float64(population[i])}
+		if i+1 < len(lifetable.StandardStarts) {
+			groups[i].Width = lifetable.StandardStarts[i+1] - start
+		}
+		age := lifeTableAge(start, groups[i].Width)
+
+		extinct := deaths[i]
+		if start == 60 {
+			extinct = float64(population[i]) / 2
+		}
+		for year, value := range map[uint16]float64{2020: deaths[i], 2021: extinct} {
+			facts = append(facts, models.StratifiedFact{Indicator: models.IndicatorDeaths, Year: year, Age: age,
+				Sex: models.SexMale, Medium: models.MediumUrban, Value: value, Status: models.StatusObserved})
+		}
+		for _, year := range []uint16{2020, 2021, 2022} {
+			entries = append(entries, models.PopulationEntry{Year: year, Age: age,
+				Sex: models.SexMale, Medium: models.MediumUrban, Population: population[i]})
+		}
+	}
+
+	store := memory.NewStore(memory.Seed{Stratified: facts, Population: entries})
+	return NewMortalityService(store.Stratified, store.Population), groups
+}
+
+func TestLifeTableOfStratum(t *testing.T) {
+	service, groups := mortalityStore()
+	want, err := lifetable.Abridged(groups, lifetable.Male)
+	if err != nil {
+		t.Fatalf("Abridged: %v", err)
+	}
+
+	year := 2020
+	table, err := service.LifeTable(context.Background(), models.LifeTableQuery{
+		Year: &year, Sex: models.SexMale, Medium: models.MediumUrban,
+	})
+	if err != nil {
+		t.Fatalf("LifeTable: %v", err)
+	}
+	if !near(table.LifeExpectancy, want[0].Ex, 1e-9) {
+		t.Errorf("e0 = %g, want %g", table.LifeExpectancy, want[0].Ex)
+	}
+	if len(table.Rows) != len(want) {
+		t.Fatalf("%d rows, want %d", len(table.Rows), len(want))
+	}
+	for i, age := range map[int]string{0: "0", 1: "1-4", 2: "5-9", len(want) - 1: "85+"} {
+		if table.Rows[i].Age != age || !near(table.Rows[i].Qx, want[i].Qx, 1e-12) {
+			t.Errorf("row %d: %s with q %g, want %s with q %g", i, table.Rows[i].Age, table.Rows[i].Qx, age, want[i].Qx)
+		}
+	}
+}
+
+func TestDecompositionSumsToChange(t *testing.T) {
+	service, _ := mortalityStore()
+
+	tests := []struct {
+		name     string
+		from, to int
+	}{
+		{"to a year without survivors above 60", 2020, 2021},
+		{"from a year without survivors above 60", 2021, 2020},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := tt.from, tt.to
+			result, err := service.Decomposition(context.Background(), models.DecompositionQuery{
+				FromYear: &from, ToYear: &to, Sex: models.SexMale, Medium: models.MediumUrban,
+			})
+			if err != nil {
+				t.Fatalf("Decomposition: %v", err)
+			}
+			if !near(result.Change, result.ToLifeExpectancy-result.FromLifeExpectancy, 1e-12) || result.Change == 0 {
+				t.Errorf("change = %g from %g to %g", result.Change, result.FromLifeExpectancy, result.ToLifeExpectancy)
+			}
+
+			sum := 0.0
+			for _, c := range result.Contributions {
+				if math.IsNaN(c.Total) || math.IsInf(c.Total, 0) {
+					t.Fatalf("contribution of %s = %g", c.Age, c.Total)
+				}
+				sum += c.Total
+			}
+			if !near(sum, result.Change, 1e-9) {
+				t.Errorf("contributions sum to %g, want the change %g", sum, result.Change)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// PopulationService serves the population table and the denominators rates are computed against
+type PopulationService struct {
+	repo     repository.PopulationRepository
+	diseases repository.DiseaseRepository
+}
+
+// NewPopulationService creates a new PopulationService
+func NewPopulationService(repo repository.PopulationRepository, diseases repository.DiseaseRepository) *PopulationService {
+	return &PopulationService{repo: repo, diseases: diseases}
+}
+
+// Entries returns the population entries matching the filter
+func (s *PopulationService) Entries(ctx context.Context, filter models.PopulationFilter) ([]models.PopulationEntry, error) {
+	entries, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing population: %w", err)
+	}
+	if entries == nil {
+		entries = []models.PopulationEntry{}
+	}
+	return entries, nil
+}
+
+// Denominators returns the mid-year populations of the years in range; nil bounds are open
+func (s *PopulationService) Denominators(ctx context.Context, startYear, endYear *int) ([]models.PopulationDenominator, error) {
+	return s.denominators(ctx, startYear, endYear, models.AgeGroupAll)
+}
+
+// denominators returns the mid-year populations of an age group in the years in range
+func (s *PopulationService) denominators(ctx context.Context, startYear, endYear *int, ageGroup string) ([]models.PopulationDenominator, error) {
+	// The year after the range is needed to interpolate the last mid-year population
+	filter := models.PopulationFilter{StartYear: startYear}
+	if endYear != nil {
+		next := *endYear + 1
+		filter.EndYear = &next
+	}
+
+	entries, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing population: %w", err)
+	}
+
+	totals := repository.PopulationTotals(entries)
+	if ageGroup == models.AgeGroupChildren {
+		totals = repository.ChildPopulationTotals(entries)
+	}
+
+	denominators := []models.PopulationDenominator{}
+	for _, d := range repository.Denominators(totals) {
+		if endYear == nil || d.Year <= *endYear {
+			denominators = append(denominators, d)
+		}
+	}
+	return denominators, nil
+}
+
+// Denominator returns the rounded mid-year population of an age group in a year and whether
+// there is one
+func (s *PopulationService) Denominator(ctx context.Context, year int, ageGroup string) (uint32, bool, error) {
+	denominators, err := s.denominators(ctx, &year, &year, ageGroup)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(denominators) == 0 {
+		return 0, false, nil
+	}
+	return repository.RoundPopulation(denominators[0].MidYear), true, nil
+}
+
+// Revise stores revised population entries and recomputes the rates of the national records of
+// both age groups in every year whose denominator they change. A revised January 1 figure moves
+// the mid-year populations of its year and the year before, which are interpolated towards it;
+// years whose rounded denominators come out the same are left alone. Revised single ages restate
+// the stored Total entry of their year, medium and sex, unless the revision includes that too.
+func (s *PopulationService) Revise(ctx context.Context, entries []models.PopulationEntry) (*models.PopulationRevision, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: no population entries", models.ErrInvalidRecord)
+	}
+	for i, e := range entries {
+		if err := checkPopulationEntry(e); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+	}
+
+	affected := make(map[int]bool)
+	for _, e := range entries {
+		affected[int(e.Year)] = true
+		affected[int(e.Year)-1] = true
+	}
+	years := make([]int, 0, len(affected))
+	for year := range affected {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+
+	ageGroups := []string{models.AgeGroupAll, models.AgeGroupChildren}
+	type denominator struct {
+		population uint32
+		ok         bool
+	}
+	before := make(map[string]map[int]denominator, len(ageGroups))
+	for _, ageGroup := range ageGroups {
+		before[ageGroup] = make(map[int]denominator, len(years))
+		for _, year := range years {
+			population, ok, err := s.Denominator(ctx, year, ageGroup)
+			if err != nil {
+				return nil, err
+			}
+			before[ageGroup][year] = denominator{population, ok}
+		}
+	}
+
+	totals, err := s.restatedTotals(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, append(entries, totals...)); err != nil {
+		return nil, fmt.Errorf("error saving population: %w", err)
+	}
+
+	revision := &models.PopulationRevision{Entries: len(entries), Years: []int{}}
+	for _, year := range years {
+		rebased := false
+		for _, ageGroup := range ageGroups {
+			population, ok, err := s.Denominator(ctx, year, ageGroup)
+			if err != nil {
+				return nil, err
+			}
+			if !ok || before[ageGroup][year] == (denominator{population, ok}) {
+				continue
+			}
+
+			n, err := s.diseases.Rebase(ctx, year, models.NationalRegion, ageGroup, population)
+			if err != nil {
+				return nil, fmt.Errorf("error recomputing rates for %d: %w", year, err)
+			}
+			rebased = true
+			revision.RebasedRecords += n
+		}
+		if rebased {
+			revision.Years = append(revision.Years, year)
+		}
+	}
+
+	return revision, nil
+}
+
+// restatedTotals returns the stored Total entries of the years, media and sexes whose single ages
+// the revision changes without restating their total, recomputed as the sum of their single ages
+func (s *PopulationService) restatedTotals(ctx context.Context, entries []models.PopulationEntry) ([]models.PopulationEntry, error) {
+	type stratum struct {
+		year        uint16
+		medium, sex string
+	}
+
+	revised := make(map[stratum]map[string]uint32)
+	restated := make(map[stratum]bool)
+	for _, e := range entries {
+		k := stratum{e.Year, e.Medium, e.Sex}
+		if e.Age == models.AgeTotal {
+			restated[k] = true
+			continue
+		}
+		if revised[k] == nil {
+			revised[k] = make(map[string]uint32)
+		}
+		revised[k][e.Age] = e.Population
+	}
+
+	var totals []models.PopulationEntry
+	listed := make(map[uint16]bool)
+	for k := range revised {
+		if restated[k] || listed[k.year] {
+			continue
+		}
+		listed[k.year] = true
+
+		year := int(k.year)
+		stored, err := s.repo.List(ctx, models.PopulationFilter{StartYear: &year, EndYear: &year})
+		if err != nil {
+			return nil, fmt.Errorf("error listing population: %w", err)
+		}
+
+		ages := make(map[stratum]map[string]uint32)
+		hasTotal := make(map[stratum]bool)
+		for _, e := range stored {
+			sk := stratum{e.Year, e.Medium, e.Sex}
+			if e.Age == models.AgeTotal {
+				hasTotal[sk] = true
+				continue
+			}
+			if ages[sk] == nil {
+				ages[sk] = make(map[string]uint32)
+			}
+			ages[sk][e.Age] = e.Population
+		}
+
+		for sk := range hasTotal {
+			if revised[sk] == nil || restated[sk] {
+				continue
+			}
+			var sum uint32
+			for age, population := range ages[sk] {
+				if _, ok := revised[sk][age]; !ok {
+					sum += population
+				}
+			}
+			for _, population := range revised[sk] {
+				sum += population
+			}
+			totals = append(totals, models.PopulationEntry{Year: sk.year, Age: models.AgeTotal, Medium: sk.medium, Sex: sk.sex, Population: sum})
+		}
+	}
+	return totals, nil
+}
+
+// checkPopulationEntry rejects entries outside the strata of the population table
+func checkPopulationEntry(e models.PopulationEntry) error {
+	if e.Year == 0 {
+		return fmt.Errorf("%w: year is required", models.ErrInvalidRecord)
+	}
+	if e.Age == "" {
+		return fmt.Errorf("%w: age is required", models.ErrInvalidRecord)
+	}
+	if e.Medium != models.MediumUrban && e.Medium != models.MediumRural {
+		return fmt.Errorf("%w: medium %q is not %q or %q", models.ErrInvalidRecord, e.Medium, models.MediumUrban, models.MediumRural)
+	}
+	if e.Sex != models.SexMale && e.Sex != models.SexFemale {
+		return fmt.Errorf("%w: sex %q is not %q or %q", models.ErrInvalidRecord, e.Sex, models.SexMale, models.SexFemale)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository/memory"
+)
+
+// populationStore seeds two years of single ages 0 and 1 with their Total entries, and a national
+// record of each year computed against a mid-year population of 1,000
+func populationStore() *PopulationService {
+	var entries []models.PopulationEntry
+	for _, year := range []uint16{2020, 2021} {
+		entries = append(entries,
+			models.PopulationEntry{Year: year, Age: "0", Medium: models.MediumUrban, Sex: models.SexMale, Population: 400},
+			models.PopulationEntry{Year: year, Age: "1", Medium: models.MediumUrban, Sex: models.SexMale, Population: 600},
+			models.PopulationEntry{Year: year, Age: models.AgeTotal, Medium: models.MediumUrban, Sex: models.SexMale, Population: 1000},
+		)
+	}
+	diseases := []models.Disease{
+		{ID: "flu_2020_1", Name: "Gripa", Region: models.NationalRegion, Year: 2020, Quarter: 1, Cases: 10, Population: 1000, IncidenceRate: 1000},
+		{ID: "flu_2021_1", Name: "Gripa", Region: models.NationalRegion, Year: 2021, Quarter: 1, Cases: 10, Population: 1000, IncidenceRate: 1000},
+	}
+
+	store := memory.NewStore(memory.Seed{Population: entries, Diseases: diseases})
+	return NewPopulationService(store.Population, store.Diseases)
+}
+
+func TestReviseRestatesTotalOfRevisedSingleAges(t *testing.T) {
+	ctx := context.Background()
+	service := populationStore()
+
+	revision, err := service.Revise(ctx, []models.PopulationEntry{
+		{Year: 2021, Age: "0", Medium: models.MediumUrban, Sex: models.SexMale, Population: 1400},
+	})
+	if err != nil {
+		t.Fatalf("Revise: %v", err)
+	}
+	if !equalInts(revision.Years, []int{2020, 2021}) {
+		t.Errorf("years = %v, want [2020 2021]", revision.Years)
+	}
+	if revision.RebasedRecords != 2 {
+		t.Errorf("rebased %d records, want 2", revision.RebasedRecords)
+	}
+
+	year := 2021
+	entries, err := service.Entries(ctx, models.PopulationFilter{StartYear: &year, EndYear: &year})
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	for _, e := range entries {
+		if e.Age == models.AgeTotal && e.Population != 2000 {
+			t.Errorf("2021 total = %d, want 2000", e.Population)
+		}
+	}
+
+	for _, tt := range []struct {
+		year int
+		want uint32
+	}{{2020, 1500}, {2021, 2000}} {
+		population, ok, err := service.Denominator(ctx, tt.year, models.AgeGroupAll)
+		if err != nil || !ok || population != tt.want {
+			t.Errorf("%d denominator = %d, %v, %v; want %d", tt.year, population, ok, err, tt.want)
+		}
+	}
+}
+
+func TestReviseSkipsUnchangedDenominators(t *testing.T) {
+	ctx := context.Background()
+	service := populationStore()
+
+	// Moving people between single ages leaves the Total, and so the denominators, as they were
+	revision, err := service.Revise(ctx, []models.PopulationEntry{
+		{Year: 2021, Age: "0", Medium: models.MediumUrban, Sex: models.SexMale, Population: 500},
+		{Year: 2021, Age: "1", Medium: models.MediumUrban, Sex: models.SexMale, Population: 500},
+	})
+	if err != nil {
+		t.Fatalf("Revise: %v", err)
+	}
+	if len(revision.Years) != 0 || revision.RebasedRecords != 0 {
+		t.Errorf("years = %v, rebased %d records; want none", revision.Years, revision.RebasedRecords)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
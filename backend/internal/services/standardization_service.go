@@ -0,0 +1,383 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/pkg/stats"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+// The standard populations are given in five-year bands, 0-4 to 80-84, and an open band from 85
+const (
+	standardBandWidth = 5
+	standardOpenAge   = 85
+	standardBands     = standardOpenAge/standardBandWidth + 1
+)
+
+// standardPopulations holds the published standards per 100,000, their oldest bands summed into
+// 85+. The WHO figures are those published, which sum to 100,035; weights are normalised anyway.
+var standardPopulations = map[string][]float64{
+	models.StandardESP2013: {
+		5000, 5500, 5500, 5500, 6000, 6000, 6500, 7000, 7000,
+		7000, 7000, 6500, 6000, 5500, 5000, 4000, 2500, 2500,
+	},
+	models.StandardWHO: {
+		8860, 8690, 8600, 8470, 8220, 7930, 7610, 7150, 6590,
+		6040, 5370, 4550, 3720, 2960, 2210, 1520, 910, 635,
+	},
+}
+
+// defaultStandardizationLevel is the confidence level of standardized rate intervals
+const defaultStandardizationLevel = 0.95
+
+// StandardizationService computes directly age-standardized rates of stratified indicators
+type StandardizationService struct {
+	stratified repository.StratifiedRepository
+	population repository.PopulationRepository
+}
+
+// NewStandardizationService creates a new StandardizationService
+func NewStandardizationService(stratified repository.StratifiedRepository, population repository.PopulationRepository) *StandardizationService {
+	return &StandardizationService{stratified: stratified, population: population}
+}
+
+// stratumYear identifies the year and stratum of a standardized rate
+type stratumYear struct {
+	year        int
+	sex, medium string
+}
+
+// Rates standardizes an indicator by age per year and stratum. Events are summed into the
+// indicator's age groups, single ages banded into five years up to 85+, and rated against the
+// mid-year population of the same groups.
+func (s *StandardizationService) Rates(ctx context.Context, req *models.StandardizedRatesRequest) (*models.StandardizedRates, error) {
+	if err := checkStandardizedRatesRequest(req); err != nil {
+		return nil, err
+	}
+	var bySex, byMedium bool
+	for _, dim := range req.GroupBy {
+		bySex = bySex || dim == models.DimensionSex
+		byMedium = byMedium || dim == models.DimensionMedium
+	}
+	key := func(year uint16, sex, medium string) stratumYear {
+		k := stratumYear{year: int(year)}
+		if bySex {
+			k.sex = sex
+		}
+		if byMedium {
+			k.medium = medium
+		}
+		return k
+	}
+
+	facts, err := s.stratified.Facts(ctx, models.StratifiedFilter{
+		Indicator: req.Indicator,
+		StartYear: req.StartYear,
+		EndYear:   req.EndYear,
+		Sexes:     req.Sexes,
+		Media:     req.Media,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s facts: %w", req.Indicator, err)
+	}
+	if len(facts) == 0 {
+		return nil, fmt.Errorf("%w: no %s facts", models.ErrNotFound, req.Indicator)
+	}
+
+	// Sum the events of each year and stratum into the indicator's age groups
+	events := make(map[stratumYear]map[string]float64)
+	unknown := make(map[stratumYear]float64)
+	suppressed := make(map[stratumYear]*models.SuppressedCells)
+	groupSet := make(map[string]bool)
+	for _, f := range facts {
+		if f.Age == models.AgeTotal {
+			continue
+		}
+		k := key(f.Year, f.Sex, f.Medium)
+		if events[k] == nil {
+			events[k] = make(map[string]float64)
+			suppressed[k] = &models.SuppressedCells{}
+		}
+		if f.Status.Suppressed() {
+			suppressed[k].Add(f.Status)
+			continue
+		}
+		if _, _, ok := repository.AgeRange(f.Age); !ok {
+			unknown[k] += f.Value
+			continue
+		}
+
+		group := repository.AgeBand(f.Age, standardBandWidth, standardOpenAge)
+		groupSet[group] = true
+		events[k][group] += f.Value
+	}
+
+	groups := make([]string, 0, len(groupSet))
+	for g := range groupSet {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return repository.AgeLess(groups[i], groups[j]) })
+
+	population, referenceYear, err := s.populations(ctx, req, groups, key)
+	if err != nil {
+		return nil, err
+	}
+
+	bands := standardPopulations[req.Standard]
+	if req.Standard == models.StandardMoldova {
+		if bands, err = s.referenceBands(ctx, referenceYear); err != nil {
+			return nil, err
+		}
+	}
+	weights := groupWeights(bands, groups)
+
+	keys := make([]stratumYear, 0, len(events))
+	for k := range events {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch {
+		case a.year != b.year:
+			return a.year < b.year
+		case a.sex != b.sex:
+			return a.sex < b.sex
+		default:
+			return a.medium < b.medium
+		}
+	})
+
+	result := &models.StandardizedRates{
+		Indicator: req.Indicator,
+		Standard:  req.Standard,
+		AgeGroups: groups,
+		Weights:   weights,
+		Level:     req.Level,
+		Rates:     []models.StandardizedRate{},
+	}
+	if req.Standard == models.StandardMoldova {
+		result.ReferenceYear = referenceYear
+	}
+
+	for _, k := range keys {
+		pop, ok := population[k]
+		if !ok {
+			// Years without population, such as years before the population table starts
+			continue
+		}
+
+		rate := models.StandardizedRate{
+			Year:             k.year,
+			Sex:              k.sex,
+			Medium:           k.medium,
+			UnknownAgeEvents: unknown[k],
+			Suppressed:       *suppressed[k],
+		}
+		var variance, maxWeight float64
+		for i, g := range groups {
+			d, n := events[k][g], pop[g]
+			if n <= 0 {
+				if d > 0 {
+					return nil, fmt.Errorf("%w: no population aged %s in %d for %g events",
+						models.ErrInsufficientData, g, k.year, d)
+				}
+				continue
+			}
+
+			rate.Events += d
+			rate.Population += n
+			rate.StandardizedRate += weights[i] * d / n * repository.RatePer
+			variance += weights[i] * weights[i] * d / (n * n) * repository.RatePer * repository.RatePer
+			maxWeight = math.Max(maxWeight, weights[i]/n*repository.RatePer)
+		}
+		if rate.Population > 0 {
+			rate.CrudeRate = rate.Events / rate.Population * repository.RatePer
+		}
+		rate.ConfidenceInterval = gammaInterval(rate.StandardizedRate, variance, maxWeight, req.Level)
+
+		result.Rates = append(result.Rates, rate)
+	}
+
+	if len(result.Rates) == 0 {
+		return nil, fmt.Errorf("%w: no population for the years of the %s facts", models.ErrInsufficientData, req.Indicator)
+	}
+	return result, nil
+}
+
+// checkStandardizedRatesRequest validates a request and fills in its defaults
+func checkStandardizedRatesRequest(req *models.StandardizedRatesRequest) error {
+	switch req.Indicator {
+	case "":
+		return fmt.Errorf("%w: indicator is required", models.ErrInvalidFilter)
+	case models.IndicatorPopulation:
+		return fmt.Errorf("%w: the population cannot be rated against itself", models.ErrInvalidFilter)
+	}
+	if !models.Additive(req.Indicator) {
+		return fmt.Errorf("%w: %s is not a count of events", models.ErrInvalidFilter, req.Indicator)
+	}
+
+	if req.Standard == "" {
+		req.Standard = models.StandardESP2013
+	}
+	if _, ok := standardPopulations[req.Standard]; !ok && req.Standard != models.StandardMoldova {
+		return fmt.Errorf("%w: standard %q is not %q, %q or %q", models.ErrInvalidFilter,
+			req.Standard, models.StandardESP2013, models.StandardWHO, models.StandardMoldova)
+	}
+
+	for _, dim := range req.GroupBy {
+		if dim != models.DimensionSex && dim != models.DimensionMedium {
+			return fmt.Errorf("%w: group_by: %q is not %q or %q", models.ErrInvalidFilter, dim, models.DimensionSex, models.DimensionMedium)
+		}
+	}
+
+	if req.Level == 0 {
+		req.Level = defaultStandardizationLevel
+	}
+	if req.Level <= 0 || req.Level >= 1 {
+		return fmt.Errorf("%w: level must be between 0 and 1", models.ErrInvalidFilter)
+	}
+	return nil
+}
+
+// populations returns the mid-year population of each age group per year and stratum, and the
+// latest year with population, the default reference year of the Moldovan standard
+func (s *StandardizationService) populations(
+	ctx context.Context, req *models.StandardizedRatesRequest, groups []string,
+	key func(year uint16, sex, medium string) stratumYear,
+) (map[stratumYear]map[string]float64, int, error) {
+	filter := models.PopulationFilter{StartYear: req.StartYear, Sexes: req.Sexes, Media: req.Media}
+	if req.EndYear != nil {
+		// The year after the range is needed to interpolate the last mid-year population
+		next := *req.EndYear + 1
+		filter.EndYear = &next
+	}
+	entries, err := s.population.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing population: %w", err)
+	}
+
+	january1 := make(map[stratumYear]map[string]float64)
+	latest := 0
+	for _, e := range entries {
+		if int(e.Year) > latest {
+			latest = int(e.Year)
+		}
+		group, ok := ageGroupOf(e.Age, groups)
+		if !ok {
+			continue
+		}
+		k := key(e.Year, e.Sex, e.Medium)
+		if january1[k] == nil {
+			january1[k] = make(map[string]float64)
+		}
+		january1[k][group] += float64(e.Population)
+	}
+
+	midYear := make(map[stratumYear]map[string]float64, len(january1))
+	for k, byGroup := range january1 {
+		next := january1[stratumYear{year: k.year + 1, sex: k.sex, medium: k.medium}]
+		midYear[k] = make(map[string]float64, len(byGroup))
+		for g, n := range byGroup {
+			if next != nil {
+				n = (n + next[g]) / 2
+			}
+			midYear[k][g] = n
+		}
+	}
+
+	if req.ReferenceYear > 0 {
+		latest = req.ReferenceYear
+	}
+	return midYear, latest, nil
+}
+
+// referenceBands sums Moldova's January 1 population of a year into the bands of the standards
+func (s *StandardizationService) referenceBands(ctx context.Context, year int) ([]float64, error) {
+	entries, err := s.population.List(ctx, models.PopulationFilter{StartYear: &year, EndYear: &year})
+	if err != nil {
+		return nil, fmt.Errorf("error listing population: %w", err)
+	}
+
+	bands := make([]float64, standardBands)
+	total := 0.0
+	for _, e := range entries {
+		first, _, ok := repository.AgeRange(e.Age)
+		if !ok {
+			continue
+		}
+		band := first / standardBandWidth
+		if band >= standardBands {
+			band = standardBands - 1
+		}
+		bands[band] += float64(e.Population)
+		total += float64(e.Population)
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("%w: no population by age in %d for the Moldovan standard", models.ErrInsufficientData, year)
+	}
+	return bands, nil
+}
+
+// groupWeights spreads the standard bands evenly over their single years, sums them into the age
+// groups and normalises the sums to 1
+func groupWeights(bands []float64, groups []string) []float64 {
+	weights := make([]float64, len(groups))
+	total := 0.0
+	for i, g := range groups {
+		first, last, _ := repository.AgeRange(g)
+		for age := first; age < standardOpenAge && (last < 0 || age <= last); age++ {
+			weights[i] += bands[age/standardBandWidth] / standardBandWidth
+		}
+		if last < 0 || last >= standardOpenAge {
+			weights[i] += bands[standardBands-1]
+		}
+		total += weights[i]
+	}
+
+	if total > 0 {
+		for i := range weights {
+			weights[i] /= total
+		}
+	}
+	return weights
+}
+
+// ageGroupOf returns the age group wholly containing a population age
+func ageGroupOf(age string, groups []string) (string, bool) {
+	first, last, ok := repository.AgeRange(age)
+	if !ok {
+		return "", false
+	}
+	for _, g := range groups {
+		gFirst, gLast, _ := repository.AgeRange(g)
+		if first < gFirst {
+			continue
+		}
+		if gLast < 0 || last >= 0 && last <= gLast {
+			return g, true
+		}
+	}
+	return "", false
+}
+
+// gammaInterval returns the interval of Fay and Feuer (1997) for a directly standardized rate y
+// with variance v, where maxWeight is the largest standard weight over population of an age group
+func gammaInterval(y, v, maxWeight, level float64) models.ConfInterval {
+	alpha := 1 - level
+	var ci models.ConfInterval
+	if y > 0 && v > 0 {
+		ci.Lower = v / (2 * y) * stats.ChiSquareQuantile(alpha/2, 2*y*y/v)
+	}
+
+	vm := v + maxWeight*maxWeight
+	ym := y + maxWeight
+	if ym > 0 {
+		ci.Upper = vm / (2 * ym) * stats.ChiSquareQuantile(1-alpha/2, 2*ym*ym/vm)
+	}
+	return ci
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository/memory"
+)
+
+// broadAges are the age groups of children, adults of working age and the elderly
+var broadAges = []string{"0-14", "15-64", "65+"}
+
+// standardizationStore seeds deaths in 2020 at 10, 100 and 2,000 per 100,000 of 20,000, 60,000 and
+// 20,000 people of broadAges, whose population is the same on January 1 of 2020 and 2021, and the
+// population of the given ages alone
+func standardizationStore(populationAges ...string) *StandardizationService {
+	counts := map[string]uint32{"0-14": 20000, "15-64": 60000, "65+": 20000}
+	deaths := map[string]float64{"0-14": 2, "15-64": 60, "65+": 400}
+
+	var facts []models.StratifiedFact
+	for _, age := range broadAges {
+		facts = append(facts, models.StratifiedFact{Indicator: models.IndicatorDeaths, Year: 2020, Age: age,
+			Sex: models.SexMale, Medium: models.MediumUrban, Value: deaths[age], Status: models.StatusObserved})
+	}
+	var entries []models.PopulationEntry
+	for _, year := range []uint16{2020, 2021} {
+		for _, age := range populationAges {
+			entries = append(entries, models.PopulationEntry{Year: year, Age: age,
+				Sex: models.SexMale, Medium: models.MediumUrban, Population: counts[age]})
+		}
+	}
+
+	store := memory.NewStore(memory.Seed{Stratified: facts, Population: entries})
+	return NewStandardizationService(store.Stratified, store.Population)
+}
+
+func TestGroupWeights(t *testing.T) {
+	tests := []struct {
+		name     string
+		standard string
+		groups   []string
+		want     []float64
+	}{
+		// 5,000 + 5,500 + 5,500; the ten bands from 15 to 64; 5,500 + 5,000 + 4,000 + 2,500 + 2,500
+		{"ESP2013 in broad groups", models.StandardESP2013, broadAges, []float64{0.16, 0.645, 0.195}},
+		// Three fifths of the 5,000 aged 0-4
+		{"ESP2013 splitting a band", models.StandardESP2013, []string{"0-2", "3+"}, []float64{0.03, 0.97}},
+		// The open group takes the 85+ band whole, 635 of the published 100,035
+		{"WHO open group", models.StandardWHO, []string{"0-84", "85+"}, []float64{99400. / 100035, 635. / 100035}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := groupWeights(standardPopulations[tt.standard], tt.groups)
+			for i := range tt.want {
+				if !near(got[i], tt.want[i], 1e-12) {
+					t.Errorf("weights = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	// In the bands of the standards themselves, every standard sums to 1
+	bands := make([]string, 0, standardBands)
+	for first := 0; first < standardOpenAge; first += standardBandWidth {
+		bands = append(bands, fmt.Sprintf("%d-%d", first, first+standardBandWidth-1))
+	}
+	bands = append(bands, "85+")
+	for standard, population := range standardPopulations {
+		sum := 0.0
+		for _, w := range groupWeights(population, bands) {
+			sum += w
+		}
+		if !near(sum, 1, 1e-12) {
+			t.Errorf("%s weights sum to %g, want 1", standard, sum)
+		}
+	}
+}
+
+func TestAgeGroupOf(t *testing.T) {
+	groups := []string{"0-4", "5-9", "10+"}
+	tests := []struct {
+		age  string
+		want string
+		ok   bool
+	}{
+		{"7", "5-9", true},
+		{"5-9", "5-9", true},
+		{"90", "10+", true},
+		{"85+", "10+", true},
+		{"3-7", "", false}, // straddles two groups
+		{models.AgeTotal, "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := ageGroupOf(tt.age, groups); got != tt.want || ok != tt.ok {
+			t.Errorf("ageGroupOf(%q) = %q, %v; want %q, %v", tt.age, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// An open age belongs to no closed group
+	if got, ok := ageGroupOf("85+", []string{"0-4", "5-84"}); ok {
+		t.Errorf("ageGroupOf(85+) = %q, want none of the closed groups", got)
+	}
+}
+
+func TestGammaInterval(t *testing.T) {
+	// With a single age group the interval of Fay and Feuer is the exact Poisson interval of
+	// Garwood (1936), tabulated for instance by Ulm (1990), divided by the population
+	const population = 1000
+	tests := []struct {
+		events       float64
+		lower, upper float64
+	}{
+		{10, 4.7954, 18.3904},
+		{0, 0, 3.6889},
+	}
+	for _, tt := range tests {
+		y, v, w := tt.events/population, tt.events/(population*population), 1.0/population
+		ci := gammaInterval(y, v, w, 0.95)
+		if !near(ci.Lower*population, tt.lower, 1e-4) || !near(ci.Upper*population, tt.upper, 1e-4) {
+			t.Errorf("%g events: interval [%g, %g] per person, want [%g, %g]",
+				tt.events, ci.Lower*population, ci.Upper*population, tt.lower, tt.upper)
+		}
+	}
+}
+
+func TestReferenceBands(t *testing.T) {
+	year := 2021
+	store := memory.NewStore(memory.Seed{Population: []models.PopulationEntry{
+		{Year: 2021, Age: "3", Sex: models.SexMale, Medium: models.MediumUrban, Population: 100},
+		{Year: 2021, Age: "7", Sex: models.SexMale, Medium: models.MediumUrban, Population: 200},
+		{Year: 2021, Age: "7", Sex: models.SexFemale, Medium: models.MediumRural, Population: 50},
+		{Year: 2021, Age: "90", Sex: models.SexMale, Medium: models.MediumUrban, Population: 30},
+		{Year: 2021, Age: "85+", Sex: models.SexFemale, Medium: models.MediumUrban, Population: 20},
+		{Year: 2021, Age: models.AgeTotal, Sex: models.SexMale, Medium: models.MediumUrban, Population: 330},
+		{Year: 2020, Age: "3", Sex: models.SexMale, Medium: models.MediumUrban, Population: 1000},
+	}})
+	service := NewStandardizationService(store.Stratified, store.Population)
+
+	bands, err := service.referenceBands(context.Background(), year)
+	if err != nil {
+		t.Fatalf("referenceBands: %v", err)
+	}
+	want := make([]float64, standardBands)
+	want[0], want[1], want[standardBands-1] = 100, 250, 50
+	for i := range want {
+		if bands[i] != want[i] {
+			t.Errorf("bands = %v, want %v", bands, want)
+			break
+		}
+	}
+
+	if _, err := service.referenceBands(context.Background(), 2019); !errors.Is(err, models.ErrInsufficientData) {
+		t.Errorf("year without population: error = %v, want ErrInsufficientData", err)
+	}
+}
+
+func TestStandardizedRates(t *testing.T) {
+	tests := []struct {
+		name          string
+		standard      string
+		weights       []float64
+		rate          float64
+		referenceYear int
+	}{
+		// 0.16·10 + 0.645·100 + 0.195·2,000
+		{"ESP2013", models.StandardESP2013, []float64{0.16, 0.645, 0.195}, 456.1, 0},
+		// Standardized to its own population, the rate is the crude rate of 462 deaths in 100,000
+		{"Moldova", models.StandardMoldova, []float64{0.2, 0.6, 0.2}, 462, 2021},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := standardizationStore(broadAges...)
+			result, err := service.Rates(context.Background(), &models.StandardizedRatesRequest{
+				Indicator: models.IndicatorDeaths,
+				Standard:  tt.standard,
+			})
+			if err != nil {
+				t.Fatalf("Rates: %v", err)
+			}
+			if result.ReferenceYear != tt.referenceYear {
+				t.Errorf("reference year = %d, want %d", result.ReferenceYear, tt.referenceYear)
+			}
+			for i := range tt.weights {
+				if !near(result.Weights[i], tt.weights[i], 1e-12) {
+					t.Errorf("weights of %v = %v, want %v", result.AgeGroups, result.Weights, tt.weights)
+					break
+				}
+			}
+			if len(result.Rates) != 1 {
+				t.Fatalf("%d rates, want the one of 2020", len(result.Rates))
+			}
+			rate := result.Rates[0]
+			if !near(rate.StandardizedRate, tt.rate, 1e-9) || !near(rate.CrudeRate, 462, 1e-9) {
+				t.Errorf("standardized %g, crude %g; want %g and 462", rate.StandardizedRate, rate.CrudeRate, tt.rate)
+			}
+			if ci := rate.ConfidenceInterval; ci.Lower >= rate.StandardizedRate || ci.Upper <= rate.StandardizedRate {
+				t.Errorf("interval [%g, %g] excludes the rate %g", ci.Lower, ci.Upper, rate.StandardizedRate)
+			}
+		})
+	}
+}
+
+func TestStandardizedRatesRejectEventsWithoutPopulation(t *testing.T) {
+	// Deaths aged 65+ in a year without anyone of that age
+	service := standardizationStore("0-14", "15-64")
+	_, err := service.Rates(context.Background(), &models.StandardizedRatesRequest{Indicator: models.IndicatorDeaths})
+	if !errors.Is(err, models.ErrInsufficientData) {
+		t.Errorf("error = %v, want ErrInsufficientData", err)
+	}
+}
+
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/pkg/stats"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+const (
+	// defaultVaccinationMaxLag is the default number of years coverage may lead incidence by
+	defaultVaccinationMaxLag = 2
+	// maxVaccinationMaxLag caps the years coverage may lead incidence by
+	maxVaccinationMaxLag = 5
+	// minVaccinationYears is the fewest years a coverage-incidence correlation is computed on
+	minVaccinationYears = 5
+)
+
+// VaccinationService analyses vaccination coverage against its target and the incidence of the
+// diseases the vaccines protect against
+type VaccinationService struct {
+	vaccination repository.VaccinationRepository
+	diseases    repository.DiseaseRepository
+	population  *PopulationService
+}
+
+// NewVaccinationService creates a new VaccinationService
+func NewVaccinationService(
+	vaccination repository.VaccinationRepository, diseases repository.DiseaseRepository, population *PopulationService,
+) *VaccinationService {
+	return &VaccinationService{vaccination: vaccination, diseases: diseases, population: population}
+}
+
+// Analyze reports the coverage of each antigen and age over the requested years with its trend
+// and the years it missed the target, and correlates coverage with the national incidence of the
+// diseases the vaccine protects against in the same and following years
+func (s *VaccinationService) Analyze(ctx context.Context, req *models.VaccinationRequest) (*models.VaccinationAnalysis, error) {
+	if err := checkVaccinationRequest(req); err != nil {
+		return nil, err
+	}
+
+	vaccines, err := s.vaccination.Vaccines(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing vaccines: %w", err)
+	}
+	byAntigen := make(map[string]models.Vaccine, len(vaccines))
+	for _, v := range vaccines {
+		byAntigen[v.Antigen] = v
+	}
+	for _, a := range req.Antigens {
+		if _, ok := byAntigen[a]; !ok {
+			return nil, fmt.Errorf("%w: antigens: %q is not a vaccine", models.ErrInvalidFilter, a)
+		}
+	}
+
+	coverage, err := s.vaccination.Coverage(ctx, models.VaccinationFilter{
+		Antigens:  req.Antigens,
+		StartYear: req.StartYear,
+		EndYear:   req.EndYear,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing vaccination coverage: %w", err)
+	}
+
+	denominators, err := s.population.Denominators(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	midYear := make(map[int]float64, len(denominators))
+	for _, d := range denominators {
+		midYear[d.Year] = d.MidYear
+	}
+
+	result := &models.VaccinationAnalysis{
+		Target:      req.Target,
+		MaxLag:      req.MaxLag,
+		Level:       correlationLevel,
+		Antigens:    []models.AntigenCoverage{},
+		BelowTarget: []string{},
+	}
+
+	// Coverage comes ordered by antigen, age and year; an antigen and age without a single
+	// observed figure is not reported at that age
+	incidence := make(map[string][]models.IncidencePoint)
+	for start := 0; start < len(coverage); {
+		end := start
+		for end < len(coverage) && coverage[end].Antigen == coverage[start].Antigen && coverage[end].Age == coverage[start].Age {
+			end++
+		}
+		figures := coverage[start:end]
+		start = end
+
+		antigen := antigenCoverage(byAntigen[figures[0].Antigen], figures, req.Target)
+		if antigen.Latest == nil {
+			continue
+		}
+
+		for _, disease := range antigen.Diseases {
+			points, ok := incidence[disease]
+			if !ok {
+				if points, err = s.yearlyIncidence(ctx, disease, midYear); err != nil {
+					return nil, err
+				}
+				incidence[disease] = points
+			}
+			antigen.Incidence = append(antigen.Incidence, coverageIncidence(disease, antigen.Points, points, req.MaxLag))
+		}
+
+		if antigen.BelowTarget {
+			result.BelowTarget = append(result.BelowTarget, antigen.Antigen)
+		}
+		result.Antigens = append(result.Antigens, antigen)
+	}
+
+	if len(result.Antigens) == 0 {
+		return nil, fmt.Errorf("%w: no vaccination coverage observed", models.ErrNotFound)
+	}
+	return result, nil
+}
+
+// checkVaccinationRequest validates a request and fills in its defaults
+func checkVaccinationRequest(req *models.VaccinationRequest) error {
+	if req.StartYear != nil && req.EndYear != nil && *req.StartYear > *req.EndYear {
+		return fmt.Errorf("%w: start_year is after end_year", models.ErrInvalidFilter)
+	}
+	if req.Target == 0 {
+		req.Target = models.WHOCoverageTarget
+	}
+	if req.Target < 0 || req.Target > 100 {
+		return fmt.Errorf("%w: target must be between 0 and 100", models.ErrInvalidFilter)
+	}
+	if req.MaxLag == 0 {
+		req.MaxLag = defaultVaccinationMaxLag
+	}
+	if req.MaxLag < 0 || req.MaxLag > maxVaccinationMaxLag {
+		return fmt.Errorf("%w: max_lag must be between 0 and %d years", models.ErrInvalidFilter, maxVaccinationMaxLag)
+	}
+	return nil
+}
+
+// antigenCoverage summarises the yearly coverage of an antigen at one age against the target
+func antigenCoverage(vaccine models.Vaccine, figures []models.VaccinationCoverage, target float64) models.AntigenCoverage {
+	antigen := models.AntigenCoverage{
+		Antigen:   figures[0].Antigen,
+		Name:      vaccine.Name,
+		Age:       figures[0].Age,
+		Diseases:  vaccine.Diseases,
+		Points:    make([]models.CoveragePoint, 0, len(figures)),
+		Incidence: []models.CoverageIncidence{},
+	}
+	if antigen.Diseases == nil {
+		antigen.Diseases = []string{}
+	}
+
+	var years, values []float64
+	for _, f := range figures {
+		p := models.CoveragePoint{Year: int(f.Year), Coverage: f.Coverage, Status: f.Status}
+		if !f.Status.Suppressed() {
+			p.BelowTarget = f.Coverage < target
+			if p.BelowTarget {
+				antigen.YearsBelowTarget++
+			}
+			years = append(years, float64(f.Year))
+			values = append(values, f.Coverage)
+		}
+		antigen.Points = append(antigen.Points, p)
+	}
+
+	for i := len(antigen.Points) - 1; i >= 0; i-- {
+		if !antigen.Points[i].Status.Suppressed() {
+			latest := antigen.Points[i]
+			antigen.Latest = &latest
+			antigen.BelowTarget = latest.BelowTarget
+			break
+		}
+	}
+	if slope := stats.Slope(years, values); !math.IsNaN(slope) {
+		antigen.Trend = &slope
+	}
+	return antigen
+}
+
+// yearlyIncidence returns the yearly national cases of a disease and their rate against the
+// mid-year population, over the years all four quarters were reported
+func (s *VaccinationService) yearlyIncidence(ctx context.Context, disease string, midYear map[int]float64) ([]models.IncidencePoint, error) {
+	points, err := s.diseases.TimeSeries(ctx, models.DiseaseFilter{
+		Names:   []string{disease},
+		Regions: []string{models.NationalRegion},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s cases: %w", disease, err)
+	}
+
+	cases := make(map[int]uint64)
+	quarters := make(map[int]int)
+	for _, p := range reportedPoints(points) {
+		cases[p.Year] += p.Cases
+		quarters[p.Year]++
+	}
+
+	var yearly []models.IncidencePoint
+	for year, n := range quarters {
+		if n < quartersPerYear {
+			continue
+		}
+		p := models.IncidencePoint{Year: year, Cases: cases[year]}
+		if pop := midYear[year]; pop > 0 {
+			rate := float64(cases[year]) / pop * repository.RatePer
+			p.IncidenceRate = &rate
+		}
+		yearly = append(yearly, p)
+	}
+	sort.Slice(yearly, func(i, j int) bool { return yearly[i].Year < yearly[j].Year })
+	return yearly, nil
+}
+
+// coverageIncidence correlates the observed coverage of a year with the incidence rate of the
+// disease 0..maxLag years later
+func coverageIncidence(disease string, coverage []models.CoveragePoint, incidence []models.IncidencePoint, maxLag int) models.CoverageIncidence {
+	relation := models.CoverageIncidence{Disease: disease, Points: incidence, Lags: []models.LagCorrelation{}}
+	if relation.Points == nil {
+		relation.Points = []models.IncidencePoint{}
+	}
+
+	rates := make(map[int]float64, len(incidence))
+	for _, p := range incidence {
+		if p.IncidenceRate != nil {
+			rates[p.Year] = *p.IncidenceRate
+		}
+	}
+
+	for lag := 0; lag <= maxLag; lag++ {
+		var xs, ys []float64
+		for _, p := range coverage {
+			rate, ok := rates[p.Year+lag]
+			if !ok || p.Status.Suppressed() {
+				continue
+			}
+			xs = append(xs, p.Coverage)
+			ys = append(ys, rate)
+		}
+		if len(xs) < minVaccinationYears {
+			continue
+		}
+		if c, ok := pairCorrelation(lag, xs, ys); ok {
+			relation.Lags = append(relation.Lags, c)
+		}
+	}
+	relation.BestLag = bestLag(relation.Lags)
+	return relation
+}
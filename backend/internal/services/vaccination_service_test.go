@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/repository/memory"
+)
+
+// measlesCoverage is the coverage of the Rujeola vaccine from 2010 to 2019, averaging 92%
+var measlesCoverage = []float64{95, 88, 97, 90, 85, 99, 92, 87, 96, 91}
+
+// vaccinationStore seeds measlesCoverage, an unavailable figure for 2020 and a constant population
+// of 100,000. The measles cases of a year from 2011 to 2020 are 2,000 less 20 per point of the
+// coverage a year earlier; 2021 misses a quarter and 2022 has one unavailable.
+func vaccinationStore() *VaccinationService {
+	var coverage []models.VaccinationCoverage
+	for i, c := range measlesCoverage {
+		coverage = append(coverage, models.VaccinationCoverage{Antigen: "Rujeola", Age: "12 luni",
+			Year: uint16(2010 + i), Coverage: c, Status: models.StatusObserved})
+	}
+	coverage = append(coverage, models.VaccinationCoverage{Antigen: "Rujeola", Age: "12 luni",
+		Year: 2020, Status: models.StatusNotAvailable})
+
+	var diseases []models.Disease
+	for year := 2010; year <= 2022; year++ {
+		yearly := uint32(1500)
+		if year > 2010 && year <= 2020 {
+			yearly = uint32(2000 - 20*measlesCoverage[year-2011])
+		}
+		for quarter := 1; quarter <= 4; quarter++ {
+			d := models.Disease{ID: fmt.Sprintf("rujeola_%d_%d", year, quarter), Name: "Rujeola", Region: models.NationalRegion,
+				Year: uint16(year), Quarter: uint8(quarter), Cases: yearly / 4, Status: models.StatusObserved}
+			switch {
+			case year == 2021 && quarter == 4:
+				continue
+			case year == 2022 && quarter == 2:
+				d.Cases, d.Status = 0, models.StatusNotAvailable
+			}
+			diseases = append(diseases, d)
+		}
+	}
+
+	var population []models.PopulationEntry
+	for year := uint16(2010); year <= 2023; year++ {
+		population = append(population, models.PopulationEntry{Year: year, Age: models.AgeTotal,
+			Sex: models.SexMale, Medium: models.MediumUrban, Population: 100000})
+	}
+
+	store := memory.NewStore(memory.Seed{Vaccination: coverage, Diseases: diseases, Population: population})
+	return NewVaccinationService(store.Vaccination, store.Diseases, NewPopulationService(store.Population, store.Diseases))
+}
+
+func TestVaccinationCoverageAgainstTarget(t *testing.T) {
+	analysis, err := vaccinationStore().Analyze(context.Background(), &models.VaccinationRequest{Antigens: []string{"Rujeola"}})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if len(analysis.Antigens) != 1 {
+		t.Fatalf("%d antigens, want Rujeola alone", len(analysis.Antigens))
+	}
+	antigen := analysis.Antigens[0]
+
+	// 2020 is unavailable, so the latest figure is the 91% of 2019, short of the 95% target like
+	// five other years
+	if antigen.Latest == nil || antigen.Latest.Year != 2019 || !antigen.BelowTarget {
+		t.Errorf("latest = %+v, below target %v; want 2019 below target", antigen.Latest, antigen.BelowTarget)
+	}
+	if antigen.YearsBelowTarget != 6 {
+		t.Errorf("%d years below target, want 6", antigen.YearsBelowTarget)
+	}
+	if len(analysis.BelowTarget) != 1 || analysis.BelowTarget[0] != "Rujeola" {
+		t.Errorf("below target = %v, want [Rujeola]", analysis.BelowTarget)
+	}
+	// The products of the deviations of coverage and year from their means sum to -5, the squared
+	// deviations of the years to 82.5
+	if antigen.Trend == nil || !near(*antigen.Trend, -5/82.5, 1e-12) {
+		t.Errorf("trend = %v, want %g", antigen.Trend, -5/82.5)
+	}
+}
+
+func TestCoverageLeadsIncidence(t *testing.T) {
+	analysis, err := vaccinationStore().Analyze(context.Background(), &models.VaccinationRequest{
+		Antigens: []string{"Rujeola"},
+		MaxLag:   2,
+	})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if len(analysis.Antigens) != 1 || len(analysis.Antigens[0].Incidence) != 1 {
+		t.Fatalf("antigens = %+v, want Rujeola against its own incidence", analysis.Antigens)
+	}
+	relation := analysis.Antigens[0].Incidence[0]
+
+	// Years 2021 and 2022 lack a reported quarter; the rates are the cases of 100,000 people
+	if n := len(relation.Points); n != 11 || relation.Points[0].Year != 2010 || relation.Points[n-1].Year != 2020 {
+		t.Fatalf("incidence points = %+v, want 2010 to 2020", relation.Points)
+	}
+	for _, p := range relation.Points {
+		if p.IncidenceRate == nil || !near(*p.IncidenceRate, float64(p.Cases), 1e-9) {
+			t.Errorf("%d: rate %v for %d cases, want the cases", p.Year, p.IncidenceRate, p.Cases)
+		}
+	}
+
+	// Coverage pairs with the incidence of the same year from 2010, the next from 2011 and the
+	// one after from 2012
+	sizes := map[int]int{0: 10, 1: 10, 2: 9}
+	if len(relation.Lags) != len(sizes) {
+		t.Fatalf("lags = %+v, want 0 to 2", relation.Lags)
+	}
+	for _, c := range relation.Lags {
+		if c.SampleSize != sizes[c.Lag] {
+			t.Errorf("lag %d: %d years, want %d", c.Lag, c.SampleSize, sizes[c.Lag])
+		}
+	}
+	if best := relation.BestLag; best == nil || best.Lag != 1 || !near(best.Coefficient, -1, 1e-12) || !best.Significant {
+		t.Errorf("best lag = %+v, want a significant correlation of -1 a year later", best)
+	}
+}
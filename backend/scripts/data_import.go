@@ -2,38 +2,37 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
-	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
 	"log"
-	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/ktruedat/healthisis/backend/internal/models"
+	"github.com/ktruedat/healthisis/backend/internal/pkg/statbank"
+	"github.com/ktruedat/healthisis/backend/internal/repository"
 )
 
 // Disease represents a disease record to insert into ClickHouse
 type Disease struct {
-	ID              string
-	Name            string
-	Category        string
-	Year            uint16
-	Quarter         uint8
-	Region          string
-	Cases           uint32
-	Deaths          uint32
-	Recoveries      uint32
-	Population      uint32
-	IncidenceRate   float64
-	PrevalenceRate  float64 // Added prevalence rate
-	MortalityRate   float64
-	EnvironmentData map[string]interface{}
+	ID             string
+	Name           string
+	Category       string
+	Year           uint16
+	Quarter        uint8
+	Region         string
+	AgeGroup       string
+	Cases          uint32
+	Deaths         uint32
+	Recoveries     uint32
+	Population     uint32
+	IncidenceRate  float64
+	PrevalenceRate float64 // Added prevalence rate
+	MortalityRate  float64
+	Status         models.ValueStatus
 }
 
 var (
@@ -70,9 +69,76 @@ func main() {
 		log.Fatalf("Failed to create table: %v", err)
 	}
 
+	// Create the category and alert tables used by the API
+	err = createSupportTables(conn)
+	if err != nil {
+		log.Fatalf("Failed to create support tables: %v", err)
+	}
+
+	// Import the population the disease rates are computed against
+	population, err := processPopulation()
+	if err != nil {
+		log.Fatalf("Failed to process population data: %v", err)
+	}
+
+	err = importPopulation(conn, population)
+	if err != nil {
+		log.Fatalf("Failed to import population to ClickHouse: %v", err)
+	}
+	log.Printf("Successfully imported %d population entries to ClickHouse", len(population))
+
+	// Import the indicators broken down by age, sex and medium
+	facts, err := processStratifiedFacts()
+	if err != nil {
+		log.Fatalf("Failed to process stratified data: %v", err)
+	}
+
+	err = importStratifiedFacts(conn, facts)
+	if err != nil {
+		log.Fatalf("Failed to import stratified data to ClickHouse: %v", err)
+	}
+	log.Printf("Successfully imported %d stratified facts to ClickHouse", len(facts))
+
+	// Import the vaccination coverage of children
+	coverage, err := processVaccination()
+	if err != nil {
+		log.Fatalf("Failed to process vaccination data: %v", err)
+	}
+
+	err = importVaccination(conn, coverage)
+	if err != nil {
+		log.Fatalf("Failed to import vaccination data to ClickHouse: %v", err)
+	}
+	log.Printf("Successfully imported %d vaccination coverage figures to ClickHouse", len(coverage))
+
+	// Import the hospital beds by profile
+	beds, err := processHospitalBeds()
+	if err != nil {
+		log.Fatalf("Failed to process hospital beds data: %v", err)
+	}
+
+	err = importHospitalBeds(conn, beds)
+	if err != nil {
+		log.Fatalf("Failed to import hospital beds data to ClickHouse: %v", err)
+	}
+	log.Printf("Successfully imported %d hospital bed counts to ClickHouse", len(beds))
+
+	// Cases of all ages are rated against the whole population, those of children against the
+	// population under 18
+	denominators := map[string]map[int]uint32{
+		models.AgeGroupAll:      make(map[int]uint32),
+		models.AgeGroupChildren: make(map[int]uint32),
+	}
+	for _, d := range repository.Denominators(repository.PopulationTotals(population)) {
+		denominators[models.AgeGroupAll][d.Year] = repository.RoundPopulation(d.MidYear)
+	}
+	for _, d := range repository.Denominators(repository.ChildPopulationTotals(population)) {
+		denominators[models.AgeGroupChildren][d.Year] = repository.RoundPopulation(d.MidYear)
+	}
+
 	// Process infectious disease data
 	log.Println("Processing infectious disease data...")
-	diseases, err := processInfectiousDiseases()
+	diseases, err := processInfectiousDiseases(denominators)
 	if err != nil {
 		log.Fatalf("Failed to process infectious diseases data: %v", err)
 	}
@@ -90,12 +156,6 @@ func main() {
 		log.Fatalf("Failed to add category data: %v", err)
 	}
 
-	// Add environmental data
-	err = addEnvironmentalData(diseases)
-	if err != nil {
-		log.Fatalf("Failed to add environmental data: %v", err)
-	}
-
 	// Import data into ClickHouse
 	err = importDataToClickHouse(conn, diseases)
 	if err != nil {
@@ -103,6 +163,31 @@ func main() {
 	}
 
 	log.Printf("Successfully imported %d disease records to ClickHouse", len(diseases))
+
+	// Import the monthly weather station observations
+	observations, err := processEnvironmentObservations()
+	if err != nil {
+		log.Fatalf("Failed to process environment observations: %v", err)
+	}
+
+	err = importEnvironmentObservations(conn, observations)
+	if err != nil {
+		log.Fatalf("Failed to import environment observations to ClickHouse: %v", err)
+	}
+
+	log.Printf("Successfully imported %d environment observations to ClickHouse", len(observations))
+
+	// Import the yearly pollutant emissions of road transport
+	emissions, err := processEmissions()
+	if err != nil {
+		log.Fatalf("Failed to process emissions data: %v", err)
+	}
+
+	err = importEmissions(conn, emissions)
+	if err != nil {
+		log.Fatalf("Failed to import emissions data to ClickHouse: %v", err)
+	}
+	log.Printf("Successfully imported %d pollutant emissions to ClickHouse", len(emissions))
 }
 
 // connectToClickHouse establishes a connection to the ClickHouse server
@@ -147,6 +232,7 @@ func createTable(conn driver.Conn) error {
 		year UInt16,
 		quarter UInt8,
 		region String,
+		age_group LowCardinality(String) DEFAULT 'all',
 		cases UInt32,
 		deaths UInt32,
 		recoveries UInt32,
@@ -154,214 +240,654 @@ func createTable(conn driver.Conn) error {
 		incidence_rate Float64,
 		prevalence_rate Float64,  
 		mortality_rate Float64,
-		environment_data String
+		status LowCardinality(String) DEFAULT 'observed'
 	) ENGINE = MergeTree()
 	ORDER BY (year, quarter, category, name, region)
 	`
-	return conn.Exec(context.Background(), query)
+	if err := conn.Exec(context.Background(), query); err != nil {
+		return err
+	}
+
+	// Tables created before value statuses or age groups were tracked lack the columns
+	if err := conn.Exec(context.Background(), `
+		ALTER TABLE diseases ADD COLUMN IF NOT EXISTS status LowCardinality(String) DEFAULT 'observed'
+	`); err != nil {
+		return err
+	}
+	if err := conn.Exec(context.Background(), `
+		ALTER TABLE diseases ADD COLUMN IF NOT EXISTS age_group LowCardinality(String) DEFAULT 'all' AFTER region
+	`); err != nil {
+		return err
+	}
+
+	// Older tables stored a computed air quality index per record; exposures now live in the
+	// environment tables
+	return conn.Exec(context.Background(), `
+		ALTER TABLE diseases DROP COLUMN IF EXISTS environment_data
+	`)
 }
 
-// processInfectiousDiseases reads and processes infectious diseases data from CSV files
-func processInfectiousDiseases() (map[string]*Disease, error) {
-	diseases := make(map[string]*Disease)
+// createSupportTables creates the tables used by the API besides diseases and seeds the default
+// categories
+func createSupportTables(conn driver.Conn) error {
+	ctx := context.Background()
+
+	if err := conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS categories (
+		id UInt32,
+		name String
+	) ENGINE = MergeTree()
+	ORDER BY id
+	`); err != nil {
+		return err
+	}
+
+	if err := conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS alerts (
+		id UInt32,
+		disease_id UInt32,
+		severity LowCardinality(String),
+		message String,
+		created_at DateTime,
+		expires_at Nullable(DateTime)
+	) ENGINE = MergeTree()
+	ORDER BY (created_at, id)
+	`); err != nil {
+		return err
+	}
 
-	// Process main infectious diseases data
-	infectiousFilePath := filepath.Join(*dataDir, "infectious_diseases_yearly_quarterly.csv")
-	log.Printf("Reading infectious disease data from: %s", infectiousFilePath)
+	if err := conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS forecast_backtests (
+		disease String,
+		model LowCardinality(String),
+		horizon UInt8,
+		level Float64,
+		first_origin_year UInt16,
+		first_origin_quarter UInt8,
+		last_origin_year UInt16,
+		last_origin_quarter UInt8,
+		origins UInt32,
+		skipped_origins UInt32,
+		forecasts UInt32,
+		mae Float64,
+		mape Nullable(Float64),
+		smape Float64,
+		mase Nullable(Float64),
+		coverage Float64,
+		run_at DateTime
+	) ENGINE = ReplacingMergeTree(run_at)
+	ORDER BY (disease, model, horizon, level)
+	`); err != nil {
+		return err
+	}
 
-	file, err := os.Open(infectiousFilePath)
+	if err := conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS population (
+		year UInt16,
+		age String,
+		medium LowCardinality(String),
+		sex LowCardinality(String),
+		population UInt32,
+		revised_at DateTime
+	) ENGINE = ReplacingMergeTree(revised_at)
+	ORDER BY (year, age, medium, sex)
+	`); err != nil {
+		return err
+	}
+
+	if err := conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS stratified_facts (
+		indicator LowCardinality(String),
+		year UInt16,
+		age String,
+		sex LowCardinality(String),
+		medium LowCardinality(String),
+		value Float64,
+		status LowCardinality(String) DEFAULT 'observed'
+	) ENGINE = ReplacingMergeTree()
+	ORDER BY (indicator, year, age, sex, medium)
+	`); err != nil {
+		return err
+	}
+
+	if err := conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS environment_observations (
+		station LowCardinality(String),
+		variable LowCardinality(String),
+		year UInt16,
+		month UInt8,
+		value Float64
+	) ENGINE = ReplacingMergeTree()
+	ORDER BY (station, variable, year, month)
+	`); err != nil {
+		return err
+	}
+
+	if err := conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS emissions (
+		pollutant LowCardinality(String),
+		year UInt16,
+		value Float64,
+		status LowCardinality(String) DEFAULT 'observed'
+	) ENGINE = ReplacingMergeTree()
+	ORDER BY (pollutant, year)
+	`); err != nil {
+		return err
+	}
+
+	if err := conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS vaccines (
+		antigen String,
+		name String,
+		diseases Array(String)
+	) ENGINE = ReplacingMergeTree()
+	ORDER BY antigen
+	`); err != nil {
+		return err
+	}
+
+	if err := conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS vaccination_coverage (
+		antigen LowCardinality(String),
+		age LowCardinality(String),
+		year UInt16,
+		coverage Float64,
+		status LowCardinality(String) DEFAULT 'observed'
+	) ENGINE = ReplacingMergeTree()
+	ORDER BY (antigen, age, year)
+	`); err != nil {
+		return err
+	}
+
+	// Vaccines are replaced on every import, so the links to the diseases follow DefaultVaccines
+	vaccines, err := conn.PrepareBatch(ctx, "INSERT INTO vaccines (antigen, name, diseases)")
 	if err != nil {
-		return nil, fmt.Errorf("failed to open infectious diseases file: %w", err)
+		return err
+	}
+	for _, v := range repository.DefaultVaccines {
+		if err := vaccines.Append(v.Antigen, v.Name, v.Diseases); err != nil {
+			return err
+		}
+	}
+	if err := vaccines.Send(); err != nil {
+		return err
+	}
+
+	if err := conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS bed_profiles (
+		profile String,
+		name String,
+		categories Array(String)
+	) ENGINE = ReplacingMergeTree()
+	ORDER BY profile
+	`); err != nil {
+		return err
 	}
-	defer file.Close()
 
-	reader := csv.NewReader(file)
-	reader.FieldsPerRecord = -1 // Allow variable number of fields
-	reader.Comma = ','          // Use comma as the default separator
-	reader.TrimLeadingSpace = true
+	if err := conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS hospital_beds (
+		profile LowCardinality(String),
+		year UInt16,
+		beds UInt32,
+		status LowCardinality(String) DEFAULT 'observed'
+	) ENGINE = ReplacingMergeTree()
+	ORDER BY (profile, year)
+	`); err != nil {
+		return err
+	}
 
-	// Read header
-	header, err := reader.Read()
+	// Bed profiles are replaced on every import, so the links to the categories follow
+	// DefaultBedProfiles
+	profiles, err := conn.PrepareBatch(ctx, "INSERT INTO bed_profiles (profile, name, categories)")
 	if err != nil {
-		return nil, fmt.Errorf("failed to read header: %w", err)
+		return err
+	}
+	for _, p := range repository.DefaultBedProfiles {
+		if err := profiles.Append(p.Profile, p.Name, p.Categories); err != nil {
+			return err
+		}
+	}
+	if err := profiles.Send(); err != nil {
+		return err
 	}
-	log.Printf("Found %d columns in header", len(header))
 
-	// Parse years and quarters from header
-	yearQuarters := make([]struct {
-		year    int
-		quarter int
-	}, 0, len(header)-1)
+	return addDefaultCategories(ctx, conn)
+}
 
-	for i := 1; i < len(header); i++ {
-		parts := strings.Split(header[i], " ")
-		log.Printf("Parsing header column %d: %s", i, header[i])
+// addDefaultCategories adds the default categories a store lacks by name, so that stores created
+// before a category was added to repository.DefaultCategories gain it. A default whose ID is taken
+// by a category created since is numbered after the highest ID.
+func addDefaultCategories(ctx context.Context, conn driver.Conn) error {
+	rows, err := conn.Query(ctx, "SELECT id, name FROM categories")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	names := make(map[string]bool)
+	ids := make(map[uint32]bool)
+	var maxID uint32
+	for rows.Next() {
+		var id uint32
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return err
+		}
+		names[name] = true
+		ids[id] = true
+		if id > maxID {
+			maxID = id
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
 
-		if len(parts) >= 2 {
-			year, err := strconv.Atoi(parts[0])
-			if err != nil {
-				log.Printf("Warning: Could not parse year from %s: %v", header[i], err)
-				continue
-			}
+	var missing []models.Category
+	for _, c := range repository.DefaultCategories {
+		if !names[c.Name] {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
 
-			quarterStr := parts[1]
-			if len(quarterStr) == 0 {
-				log.Printf("Warning: Quarter string is empty in %s", header[i])
-				continue
-			}
+	batch, err := conn.PrepareBatch(ctx, "INSERT INTO categories (id, name)")
+	if err != nil {
+		return err
+	}
+	for _, c := range missing {
+		id := uint32(c.ID)
+		if ids[id] {
+			maxID++
+			id = maxID
+		}
+		ids[id] = true
+		if id > maxID {
+			maxID = id
+		}
+		if err := batch.Append(id, c.Name); err != nil {
+			return err
+		}
+	}
+	return batch.Send()
+}
 
-			var quarter int
-			// Handle both numeric and Roman numeral formats
-			if strings.HasPrefix(quarterStr, "Q") && len(quarterStr) > 1 {
-				quarter, err = strconv.Atoi(quarterStr[1:2])
-			} else if quarterStr == "I" {
-				quarter = 1
-			} else if quarterStr == "II" {
-				quarter = 2
-			} else if quarterStr == "III" {
-				quarter = 3
-			} else if quarterStr == "IV" {
-				quarter = 4
-			} else if len(quarterStr) >= 1 {
-				quarter, err = strconv.Atoi(quarterStr[0:1]) // Extract first digit as quarter
-			}
+// Dimensions the importer names in the Statbank mappings
+const (
+	dimDisease   = "disease"
+	dimCategory  = "category"
+	dimAntigen   = "antigen"
+	dimProfile   = "profile"
+	dimPollutant = "pollutant"
+)
+
+// Statbank mappings of the exports the importer reads
+var (
+	infectiousDiseasesTable = statbank.Mapping{Columns: map[string]string{"Boli infectioase": dimDisease}}
+	categoriesTable         = statbank.Mapping{Columns: map[string]string{"Clase de boli": dimCategory}}
+	stationMonthTable       = statbank.Mapping{Label: statbank.DimStation}
+	populationTable         = statbank.Mapping{}
+	stratifiedTable         = statbank.Mapping{}
+	vaccinationTable        = statbank.Mapping{Columns: map[string]string{"Boli": dimAntigen}}
+	hospitalBedsTable       = statbank.Mapping{Columns: map[string]string{"Profil": dimProfile}}
+	emissionsTable          = statbank.Mapping{Columns: map[string]string{"Substante": dimPollutant}}
+)
+
+// stratifiedFiles maps the Statbank exports broken down by age, sex and medium to their indicators
+var stratifiedFiles = []struct {
+	file      string
+	indicator string
+}{
+	{"Decedati-Medii-Virste-Ani-Sexe.csv", models.IndicatorDeaths},
+	{"Speranta-viata-pe-Virste-Ani-Medii-Sexe.csv", models.IndicatorLifeExpectancy},
+	{"Persoane-in-virsta-18-ani-si-peste-recunoscute-cu-dizabilitate-primara-pe-Grupe-de-virsta-Medii-Sexe-Ani.csv", models.IndicatorDisability},
+}
+
+// processStratifiedFacts reads the yearly indicators broken down by age, sex and medium
+func processStratifiedFacts() ([]models.StratifiedFact, error) {
+	var facts []models.StratifiedFact
+
+	for _, f := range stratifiedFiles {
+		path := filepath.Join(*dataDir, f.file)
+		log.Printf("Reading %s data from: %s", f.indicator, path)
+
+		table, err := statbank.ReadFile(path, stratifiedTable)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.file, err)
+		}
 
-			if err != nil || quarter < 1 || quarter > 4 {
-				log.Printf("Warning: Could not parse quarter from %s: %v", quarterStr, err)
+		for _, o := range table.Observations {
+			year, ok := o.Int(statbank.DimYear)
+			age, sex, medium := o.Get(statbank.DimAge), o.Get(statbank.DimSex), o.Get(statbank.DimMedium)
+			if !ok || age == "" || sex == "" || medium == "" {
+				log.Printf("Warning: Skipping observation without year, age, sex or medium: %v", o.Dimensions)
 				continue
 			}
 
-			yearQuarters = append(yearQuarters, struct {
-				year    int
-				quarter int
-			}{year: year, quarter: quarter})
+			facts = append(facts, models.StratifiedFact{
+				Indicator: f.indicator,
+				Year:      uint16(year),
+				Age:       age,
+				Sex:       sex,
+				Medium:    medium,
+				Value:     o.Value,
+				Status:    models.ValueStatus(o.Status),
+			})
+		}
+	}
 
-			log.Printf("Added year: %d, quarter: %d", year, quarter)
-		} else {
-			log.Printf("Warning: Column header format not recognized: %s", header[i])
+	return facts, nil
+}
+
+// importStratifiedFacts inserts the stratified facts into ClickHouse
+func importStratifiedFacts(conn driver.Conn, facts []models.StratifiedFact) error {
+	batch, err := conn.PrepareBatch(context.Background(), `
+		INSERT INTO stratified_facts (indicator, year, age, sex, medium, value, status)
+	`)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range facts {
+		if err := batch.Append(f.Indicator, f.Year, f.Age, f.Sex, f.Medium, f.Value, string(f.Status)); err != nil {
+			return err
 		}
 	}
 
-	log.Printf("Successfully parsed %d year-quarter combinations", len(yearQuarters))
+	log.Printf("Inserting %d stratified facts into ClickHouse...", len(facts))
+	return batch.Send()
+}
+
+// vaccinationFile is the Statbank export of the vaccination coverage of children by antigen and age
+const vaccinationFile = "Copii-cuprinsi-cu-vaccinari-preventive-pe-Virsta-Ani.csv"
 
-	// Get population data (we'll use a simplified approach for demo)
-	populationByYear := map[int]int{
-		2014: 3556400,
-		2015: 3553056,
-		2016: 3550852,
-		2017: 3547539,
-		2018: 3542708,
-		2019: 3535112,
-		2020: 3515894,
-		2021: 3232724,
-		2022: 3095176,
-		2023: 3059639,
-		2024: 3028803,
+// vaccinationAges maps the ages of the vaccination export that are not in completed years
+var vaccinationAges = map[string]string{"nou-nascuti (30 zile)": models.VaccinationAgeNewborn}
+
+// processVaccination reads the yearly vaccination coverage by antigen and age. Antigens are only
+// reported at the age they are given, so ages without a single observed figure are left out.
+func processVaccination() ([]models.VaccinationCoverage, error) {
+	path := filepath.Join(*dataDir, vaccinationFile)
+	log.Printf("Reading vaccination data from: %s", path)
+
+	table, err := statbank.ReadFile(path, vaccinationTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vaccination file: %w", err)
 	}
 
-	// Read disease data rows
-	lineCount := 0
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
+	var coverage []models.VaccinationCoverage
+	observed := make(map[[2]string]bool)
+	for _, o := range table.Observations {
+		year, ok := o.Int(statbank.DimYear)
+		antigen, age := o.Get(dimAntigen), o.Get(statbank.DimAge)
+		if !ok || antigen == "" || age == "" {
+			log.Printf("Warning: Skipping observation without antigen, age or year: %v", o.Dimensions)
+			continue
 		}
-		if err != nil {
-			return nil, fmt.Errorf("error reading CSV record: %w", err)
+		if a, ok := vaccinationAges[age]; ok {
+			age = a
 		}
 
-		lineCount++
-		if lineCount == 1 {
-			continue // Skip header if it was already read
+		if !o.Missing() {
+			observed[[2]string{antigen, age}] = true
 		}
+		coverage = append(coverage, models.VaccinationCoverage{
+			Antigen:  antigen,
+			Age:      age,
+			Year:     uint16(year),
+			Coverage: o.Value,
+			Status:   models.ValueStatus(o.Status),
+		})
+	}
+
+	reported := coverage[:0]
+	for _, c := range coverage {
+		if observed[[2]string{c.Antigen, c.Age}] {
+			reported = append(reported, c)
+		}
+	}
+	return reported, nil
+}
+
+// importVaccination inserts the vaccination coverage into ClickHouse
+func importVaccination(conn driver.Conn, coverage []models.VaccinationCoverage) error {
+	batch, err := conn.PrepareBatch(context.Background(), `
+		INSERT INTO vaccination_coverage (antigen, age, year, coverage, status)
+	`)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range coverage {
+		if err := batch.Append(c.Antigen, c.Age, c.Year, c.Coverage, string(c.Status)); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Inserting %d vaccination coverage figures into ClickHouse...", len(coverage))
+	return batch.Send()
+}
+
+// hospitalBedsFile is the Statbank export of the hospital beds by profile
+const hospitalBedsFile = "Paturi-spitale-Profil-Indicatori-Ani.csv"
+
+// processHospitalBeds reads the yearly hospital beds by profile
+func processHospitalBeds() ([]models.HospitalBeds, error) {
+	path := filepath.Join(*dataDir, hospitalBedsFile)
+	log.Printf("Reading hospital beds data from: %s", path)
+
+	table, err := statbank.ReadFile(path, hospitalBedsTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hospital beds file: %w", err)
+	}
 
-		diseaseName := strings.TrimSpace(record[0])
-		if diseaseName == "" {
-			log.Printf("Skipping record with empty disease name at line %d", lineCount)
+	var beds []models.HospitalBeds
+	for _, o := range table.Observations {
+		year, ok := o.Int(statbank.DimYear)
+		profile := o.Get(dimProfile)
+		if !ok || profile == "" {
+			log.Printf("Warning: Skipping observation without profile or year: %v", o.Dimensions)
 			continue
 		}
 
-		log.Printf("Processing disease: %s (line %d)", diseaseName, lineCount)
+		beds = append(beds, models.HospitalBeds{
+			Profile: profile,
+			Year:    uint16(year),
+			Beds:    uint32(o.Value),
+			Status:  models.ValueStatus(o.Status),
+		})
+	}
+	return beds, nil
+}
 
-		// Create disease entry for each year/quarter
-		recordsAdded := 0
-		for i, yq := range yearQuarters {
-			if i+1 >= len(record) {
-				log.Printf("Warning: Not enough columns for %s at index %d", diseaseName, i)
-				continue
-			}
+// importHospitalBeds inserts the hospital beds into ClickHouse
+func importHospitalBeds(conn driver.Conn, beds []models.HospitalBeds) error {
+	batch, err := conn.PrepareBatch(context.Background(), `
+		INSERT INTO hospital_beds (profile, year, beds, status)
+	`)
+	if err != nil {
+		return err
+	}
 
-			caseValue := strings.TrimSpace(record[i+1])
-			// Skip empty or invalid data
-			if caseValue == "" || caseValue == ".." || caseValue == "C" || caseValue == "-" {
-				log.Printf("Skipping empty/invalid value for %s in %d Q%d: %s",
-					diseaseName, yq.year, yq.quarter, caseValue)
-				continue
-			}
+	for _, b := range beds {
+		if err := batch.Append(b.Profile, b.Year, b.Beds, string(b.Status)); err != nil {
+			return err
+		}
+	}
 
-			// Parse case count
-			cases, err := strconv.Atoi(caseValue)
-			if err != nil {
-				log.Printf("Warning: Invalid case count '%s' for disease '%s' in %d Q%d, skipping",
-					caseValue, diseaseName, yq.year, yq.quarter)
-				continue
-			}
+	log.Printf("Inserting %d hospital bed counts into ClickHouse...", len(beds))
+	return batch.Send()
+}
 
-			// Create unique ID for the disease record
-			id := fmt.Sprintf("%s_%d_%d", strings.Replace(diseaseName, " ", "_", -1), yq.year, yq.quarter)
+// populationFile is the Statbank export of the January 1 population by age, medium and sex
+const populationFile = "Populatia-resedinta-obisnuita-inceputul-anului-pe-Ani-Virste-Medii-Sexe.csv"
 
-			// Calculate population for this year
-			population := populationByYear[yq.year]
-			if population == 0 {
-				population = 3500000 // Default if not found
-			}
+// processPopulation reads the January 1 population by year, age, medium and sex
+func processPopulation() ([]models.PopulationEntry, error) {
+	path := filepath.Join(*dataDir, populationFile)
+	log.Printf("Reading population data from: %s", path)
 
-			// Calculate incidence rate per 100,000 people
-			incidenceRate := float64(cases) * 100000 / float64(population)
+	table, err := statbank.ReadFile(path, populationTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read population file: %w", err)
+	}
 
-			// Estimate mortality rate based on disease type and severity
-			mortalityRate := calculateMortalityRate(diseaseName, yq.year)
+	var entries []models.PopulationEntry
+	for _, o := range table.Observations {
+		year, ok := o.Int(statbank.DimYear)
+		age, medium, sex := o.Get(statbank.DimAge), o.Get(statbank.DimMedium), o.Get(statbank.DimSex)
+		if !ok || age == "" || medium == "" || sex == "" {
+			log.Printf("Warning: Skipping observation without year, age, medium or sex: %v", o.Dimensions)
+			continue
+		}
+		if o.Missing() {
+			continue
+		}
 
-			// Estimate deaths based on mortality rate
-			deaths := int(float64(cases) * mortalityRate / 100)
+		entries = append(entries, models.PopulationEntry{
+			Year:       uint16(year),
+			Age:        age,
+			Medium:     medium,
+			Sex:        sex,
+			Population: uint32(o.Value),
+		})
+	}
 
-			// Estimate recoveries (simple approach: cases - deaths)
-			recoveries := cases - deaths
-			if recoveries < 0 {
-				recoveries = 0
-			}
+	return entries, nil
+}
 
-			// Determine disease category
-			category := categorizeDisease(diseaseName)
-
-			disease := &Disease{
-				ID:              id,
-				Name:            diseaseName,
-				Category:        category,
-				Year:            uint16(yq.year),
-				Quarter:         uint8(yq.quarter),
-				Region:          "Republic of Moldova",
-				Cases:           uint32(cases),
-				Deaths:          uint32(deaths),
-				Recoveries:      uint32(recoveries),
-				Population:      uint32(population),
-				IncidenceRate:   incidenceRate,
-				MortalityRate:   mortalityRate,
-				EnvironmentData: make(map[string]interface{}),
-			}
+// importPopulation inserts the population entries into ClickHouse
+func importPopulation(conn driver.Conn, entries []models.PopulationEntry) error {
+	batch, err := conn.PrepareBatch(context.Background(), `
+		INSERT INTO population (year, age, medium, sex, population, revised_at)
+	`)
+	if err != nil {
+		return err
+	}
 
-			diseases[id] = disease
-			recordsAdded++
+	revisedAt := time.Now().UTC()
+	for _, e := range entries {
+		if err := batch.Append(e.Year, e.Age, e.Medium, e.Sex, e.Population, revisedAt); err != nil {
+			return err
 		}
+	}
+
+	log.Printf("Inserting %d population entries into ClickHouse...", len(entries))
+	return batch.Send()
+}
 
-		log.Printf("Added %d records for disease: %s", recordsAdded, diseaseName)
+// infectiousFiles maps the quarterly Statbank exports of infectious disease cases to the age
+// group they count; the children's file repeats the diseases of the all-ages file
+var infectiousFiles = []struct {
+	file     string
+	ageGroup string
+}{
+	{"infectious_diseases_yearly_quarterly.csv", models.AgeGroupAll},
+	{"Morbiditatea-copiilor-pe-Boli-infectioase-Ani-Trimestre.csv", models.AgeGroupChildren},
+}
+
+// processInfectiousDiseases reads and processes infectious diseases data from CSV files, computing
+// rates against the mid-year population of each year and age group
+func processInfectiousDiseases(denominators map[string]map[int]uint32) (map[string]*Disease, error) {
+	diseases := make(map[string]*Disease)
+
+	for _, f := range infectiousFiles {
+		if err := processInfectiousFile(f.file, f.ageGroup, denominators[f.ageGroup], diseases); err != nil {
+			return nil, err
+		}
 	}
 
 	log.Printf("Found %d disease records in total", len(diseases))
 	return diseases, nil
 }
 
-// calculateMortalityRate estimates a mortality rate for the disease
+// processInfectiousFile reads the quarterly cases of one age group into diseases
+func processInfectiousFile(file, ageGroup string, denominators map[int]uint32, diseases map[string]*Disease) error {
+	path := filepath.Join(*dataDir, file)
+	log.Printf("Reading %s infectious disease data from: %s", ageGroup, path)
+
+	table, err := statbank.ReadFile(path, infectiousDiseasesTable)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	log.Printf("Found %d observations of %v", len(table.Observations), table.Dimensions)
+
+	// Create a disease entry for each year/quarter
+	for _, o := range table.Observations {
+		diseaseName := o.Get(dimDisease)
+		year, okYear := o.Int(statbank.DimYear)
+		quarter, okQuarter := o.Int(statbank.DimQuarter)
+		if diseaseName == "" || !okYear || !okQuarter {
+			log.Printf("Warning: Skipping observation without disease, year or quarter: %v", o.Dimensions)
+			continue
+		}
+
+		// Create unique ID for the disease record; the records of children extend the ID of the
+		// all-ages record, so a disease ID selects both
+		id := fmt.Sprintf("%s_%d_%d", strings.Replace(diseaseName, " ", "_", -1), year, quarter)
+		if ageGroup != models.AgeGroupAll {
+			id += "_" + ageGroup
+		}
+		category := categorizeDisease(diseaseName)
+
+		// Cells that withhold their value are kept with their status and no counts, so they are
+		// not mistaken for quarters without cases
+		if o.Missing() {
+			log.Printf("Recording %s value for %s in %d Q%d", o.Status, diseaseName, year, quarter)
+			diseases[id] = &Disease{
+				ID:       id,
+				Name:     diseaseName,
+				Category: category,
+				Year:     uint16(year),
+				Quarter:  uint8(quarter),
+				Region:   models.NationalRegion,
+				AgeGroup: ageGroup,
+				Status:   models.ValueStatus(o.Status),
+			}
+			continue
+		}
+		cases := int(o.Value)
+
+		// Estimate deaths from the case fatality of the disease
+		deaths := int(float64(cases) * calculateMortalityRate(diseaseName, year) / 100)
+
+		// Estimate recoveries (simple approach: cases - deaths)
+		recoveries := cases - deaths
+		if recoveries < 0 {
+			recoveries = 0
+		}
+
+		disease := &Disease{
+			ID:         id,
+			Name:       diseaseName,
+			Category:   category,
+			Year:       uint16(year),
+			Quarter:    uint8(quarter),
+			Region:     models.NationalRegion,
+			AgeGroup:   ageGroup,
+			Cases:      uint32(cases),
+			Deaths:     uint32(deaths),
+			Recoveries: uint32(recoveries),
+			Status:     models.ValueStatus(o.Status),
+		}
+
+		// Calculate incidence and mortality rates per 100,000 people
+		if population, ok := denominators[year]; ok {
+			disease.Population = population
+			disease.IncidenceRate = float64(cases) * repository.RatePer / float64(population)
+			disease.MortalityRate = float64(deaths) * repository.RatePer / float64(population)
+		} else {
+			log.Printf("Warning: No %s population for %d; leaving the rates of %s unset", ageGroup, year, diseaseName)
+		}
+		diseases[id] = disease
+	}
+
+	return nil
+}
+
+// calculateMortalityRate estimates the case fatality of the disease as a percentage of cases
 // This is a simplified approach - in reality you would use actual mortality data
 func calculateMortalityRate(diseaseName string, year int) float64 {
 	// These are rough estimates for demonstration purposes
@@ -421,23 +947,11 @@ func categorizeDisease(diseaseName string) string {
 func addCategoryData(diseases map[string]*Disease) error {
 	categoriesFilePath := filepath.Join(*dataDir, "categories_prevalence_incidence.csv")
 	log.Printf("Reading category data from: %s", categoriesFilePath)
-	
-	file, err := os.Open(categoriesFilePath)
-	if err != nil {
-		return fmt.Errorf("failed to open categories file: %w", err)
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	reader.FieldsPerRecord = -1
 
-	// Skip header
-	header, err := reader.Read()
+	table, err := statbank.ReadFile(categoriesFilePath, categoriesTable)
 	if err != nil {
-		return fmt.Errorf("failed to read header: %w", err)
+		return fmt.Errorf("failed to read categories file: %w", err)
 	}
-	
-	log.Printf("Found %d columns in categories file header", len(header))
 
 	// Create category to our category mapping
 	categoryMapping := map[string]string{
@@ -448,191 +962,240 @@ func addCategoryData(diseases map[string]*Disease) error {
 		"Boli ale aparatului circulator":            "Circulatory System Diseases",
 		"Boli ale aparatului digestiv":              "Digestive System Diseases",
 	}
-	
+
 	// Store category data for prevalence and incidence by year
-	categoryData := make(map[string]map[int]struct{
+	categoryData := make(map[string]map[int]struct {
 		prevalence float64
 		incidence  float64
 	})
 
-	// Read category data
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("error reading CSV record: %w", err)
+	// The indicator of each column says whether it counts prevalence (total cases) or
+	// incidence (new cases), in thousands
+	for _, o := range table.Observations {
+		categoryName := o.Get(dimCategory)
+		if categoryName == statbank.ValueTotal {
+			continue
 		}
 
-		categoryName := strings.TrimSpace(record[0])
-		if categoryName == "Total" {
+		year, ok := o.Int(statbank.DimYear)
+		if !ok || o.Missing() {
 			continue
 		}
-		
-		log.Printf("Processing category: %s", categoryName)
 
 		// Map to our category system
 		mappedCategory, exists := categoryMapping[categoryName]
 		if !exists {
 			mappedCategory = "Other"
 		}
-		
+
 		// Initialize category data map
 		if _, exists := categoryData[mappedCategory]; !exists {
-			categoryData[mappedCategory] = make(map[int]struct{
+			categoryData[mappedCategory] = make(map[int]struct {
 				prevalence float64
 				incidence  float64
 			})
 		}
 
-		// Extract both prevalence and incidence data for each year
-		for year := 2014; year <= 2023; year++ {
-			yearIndex := year - 2014
-			
-			// Columns 1-10 have prevalence data, columns 11-20 have incidence data
-			prevalenceValue := 0.0
-			incidenceValue := 0.0
-			
-			// Extract prevalence (total cases)
-			if yearIndex < len(record)-11 {  // Make sure we don't go out of bounds
-				prevalenceStr := record[yearIndex+1]  // +1 to skip category name column
-				if prevalenceStr != "" {
-					if val, err := strconv.ParseFloat(prevalenceStr, 64); err == nil {
-						prevalenceValue = val
-					}
-				}
-			}
-			
-			// Extract incidence (new cases)
-			if yearIndex+11 < len(record) {
-				incidenceStr := record[yearIndex+11]  // +11 to reach incidence data columns
-				if incidenceStr != "" {
-					if val, err := strconv.ParseFloat(incidenceStr, 64); err == nil {
-						incidenceValue = val
-					}
-				}
-			}
-			
-			// Store the data
-			categoryData[mappedCategory][year] = struct{
-				prevalence float64
-				incidence  float64
-			}{
-				prevalence: prevalenceValue,
-				incidence:  incidenceValue,
-			}
-			
-			log.Printf("Category %s, Year %d: Prevalence=%.2f, Incidence=%.2f", 
-				mappedCategory, year, prevalenceValue, incidenceValue)
+		data := categoryData[mappedCategory][year]
+		indicator := o.Get(statbank.DimIndicator)
+		switch {
+		case strings.Contains(indicator, "Prevalenta"):
+			data.prevalence = o.Value
+		case strings.Contains(indicator, "Incidenta"):
+			data.incidence = o.Value
+		default:
+			continue
 		}
+		categoryData[mappedCategory][year] = data
+
+		log.Printf("Category %s, Year %d: Prevalence=%.2f, Incidence=%.2f",
+			mappedCategory, year, data.prevalence, data.incidence)
 	}
 
 	// Apply the data to disease records
 	count := 0
 	for _, disease := range diseases {
+		// Suppressed records carry no counts, records of years without population have no
+		// population to rate against, and the category figures count all ages
+		if disease.Status.Suppressed() || disease.Population == 0 || disease.AgeGroup != models.AgeGroupAll {
+			continue
+		}
 		year := int(disease.Year)
 		category := disease.Category
-		
+
 		// Skip if year or category not found
 		if yearData, exists := categoryData[category]; exists {
 			if data, yearExists := yearData[year]; yearExists {
 				// Calculate prevalence rate - convert from absolute values (thousands) to rate per 100,000
 				if data.prevalence > 0 {
-					absolutePrevalence := data.prevalence * 1000  // Convert from thousands to actual count
+					absolutePrevalence := data.prevalence * 1000 // Convert from thousands to actual count
 					disease.PrevalenceRate = absolutePrevalence * 100000 / float64(disease.Population)
 					count++
 				}
-				
+
 				// Only update incidence rate if we don't have a specific value already
 				if disease.IncidenceRate == 0 && data.incidence > 0 {
-					absoluteIncidence := data.incidence * 1000  // Convert from thousands
+					absoluteIncidence := data.incidence * 1000 // Convert from thousands
 					disease.IncidenceRate = absoluteIncidence * 100000 / float64(disease.Population)
 				}
 			}
 		}
 	}
-	
+
 	log.Printf("Updated prevalence rates for %d disease records", count)
 	return nil
 }
 
-// addEnvironmentalData adds weather and other environmental factors
-func addEnvironmentalData(diseases map[string]*Disease) error {
-	// In this simplified version, we'll just add some dummy environmental data
-	// since the actual precipitation file might not be available
-
-	log.Println("Adding environmental data to disease records...")
-
-	// Simple environmental factors by year and quarter
-	temperatureByYearQuarter := map[int]map[int]float64{
-		2014: {1: 0.5, 2: 15.2, 3: 21.4, 4: 5.3},
-		2015: {1: -0.2, 2: 16.1, 3: 22.5, 4: 6.1},
-		2016: {1: 0.1, 2: 15.8, 3: 23.0, 4: 4.9},
-		2017: {1: -1.2, 2: 14.9, 3: 21.8, 4: 7.2},
-		2018: {1: 0.8, 2: 17.3, 3: 22.6, 4: 8.4},
-		2019: {1: 1.2, 2: 16.5, 3: 23.2, 4: 6.8},
-		2020: {1: 2.1, 2: 15.3, 3: 22.8, 4: 5.9},
-		2021: {1: -0.5, 2: 14.8, 3: 21.2, 4: 6.7},
-		2022: {1: 0.3, 2: 16.8, 3: 24.5, 4: 7.8},
-		2023: {1: 1.1, 2: 17.2, 3: 23.9, 4: 8.2},
-	}
-
-	humidityByYearQuarter := map[int]map[int]float64{
-		2014: {1: 78, 2: 65, 3: 58, 4: 75},
-		2015: {1: 80, 2: 63, 3: 55, 4: 77},
-		2016: {1: 79, 2: 66, 3: 57, 4: 76},
-		2017: {1: 81, 2: 64, 3: 56, 4: 78},
-		2018: {1: 77, 2: 60, 3: 53, 4: 73},
-		2019: {1: 76, 2: 62, 3: 54, 4: 74},
-		2020: {1: 75, 2: 61, 3: 52, 4: 72},
-		2021: {1: 82, 2: 67, 3: 59, 4: 79},
-		2022: {1: 79, 2: 63, 3: 56, 4: 76},
-		2023: {1: 78, 2: 64, 3: 57, 4: 75},
-	}
-
-	// Add environmental data to each disease record
-	for _, disease := range diseases {
-		year := disease.Year
-		quarter := disease.Quarter
+// environmentFiles maps the monthly station exports to the variables they observe
+var environmentFiles = []struct {
+	file     string
+	variable string
+}{
+	{"Temperatura-medie-a-aerului-pe-Luni-Statia-meteorologica-Ani.csv", models.VariableAirTemperature},
+	{"Cantitatea-precipitatii-Luni-Statia-meteorologica-Ani.csv", models.VariablePrecipitation},
+	{"Viteza-medie-a-vintului-pe-Luni-Statia-meteorologica-Ani.csv", models.VariableWindSpeed},
+}
+
+// processEnvironmentObservations reads the monthly temperature, precipitation and wind files
+func processEnvironmentObservations() ([]models.EnvironmentObservation, error) {
+	var observations []models.EnvironmentObservation
+
+	for _, f := range environmentFiles {
+		path := filepath.Join(*dataDir, f.file)
+		log.Printf("Reading %s observations from: %s", f.variable, path)
 
-		// Initialize environment data map if needed
-		if disease.EnvironmentData == nil {
-			disease.EnvironmentData = make(map[string]interface{})
+		parsed, err := parseStationMonthFile(path, f.variable)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f.file, err)
 		}
 
-		// Add temperature data
-		if yearData, exists := temperatureByYearQuarter[int(year)]; exists {
-			if temp, exists := yearData[int(quarter)]; exists {
-				disease.EnvironmentData["avg_temperature_c"] = temp
-			}
+		log.Printf("Parsed %d %s observations", len(parsed), f.variable)
+		observations = append(observations, parsed...)
+	}
+
+	return observations, nil
+}
+
+// parseStationMonthFile parses a Statbank export with one row per month and one
+// "<station> <year>" column per station and year
+func parseStationMonthFile(path, variable string) ([]models.EnvironmentObservation, error) {
+	table, err := statbank.ReadFile(path, stationMonthTable)
+	if err != nil {
+		return nil, err
+	}
+
+	var observations []models.EnvironmentObservation
+	for _, o := range table.Observations {
+		year, okYear := o.Int(statbank.DimYear)
+		month, okMonth := o.Int(statbank.DimMonth)
+		station := o.Get(statbank.DimStation)
+		if !okYear || !okMonth || station == "" {
+			log.Printf("Warning: Skipping observation without station, year or month: %v", o.Dimensions)
+			continue
 		}
 
-		// Add humidity data
-		if yearData, exists := humidityByYearQuarter[int(year)]; exists {
-			if humidity, exists := yearData[int(quarter)]; exists {
-				disease.EnvironmentData["avg_humidity_percent"] = humidity
-			}
+		if o.Missing() {
+			// Not available or confidential; a "-" is a true zero, e.g. a month without precipitation
+			continue
 		}
 
-		// Add random precipitation data
-		disease.EnvironmentData["precipitation_mm"] = float64(30 + (int(year)%10)*5 + int(quarter)*10)
+		observations = append(observations, models.EnvironmentObservation{
+			Station:  station,
+			Variable: variable,
+			Year:     uint16(year),
+			Month:    uint8(month),
+			Value:    o.Value,
+		})
+	}
+
+	return observations, nil
+}
 
-		// Add some air quality index data (simulated)
-		disease.EnvironmentData["air_quality_index"] = float64(50 + (int(year)%5)*10 - int(quarter)*3)
+// importEnvironmentObservations inserts the observations into ClickHouse
+func importEnvironmentObservations(conn driver.Conn, observations []models.EnvironmentObservation) error {
+	batch, err := conn.PrepareBatch(context.Background(), `
+		INSERT INTO environment_observations (station, variable, year, month, value)
+	`)
+	if err != nil {
+		return err
 	}
 
-	log.Printf("Added environmental data to %d disease records", len(diseases))
-	return nil
+	for _, o := range observations {
+		if err := batch.Append(o.Station, o.Variable, o.Year, o.Month, o.Value); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Inserting %d environment observations into ClickHouse...", len(observations))
+	return batch.Send()
+}
+
+// emissionsFile is the Statbank export of the yearly pollutant emissions of road transport
+const emissionsFile = "Emisiile-substantelor-poluante-in-aerul-atmosferic-de-la-transportul-auto-pe-Substante-Ani.csv"
+
+// emissionPollutants maps the substances of the emissions export to pollutants
+var emissionPollutants = map[string]string{
+	"oxid de carbon": models.PollutantCarbonMonoxide,
+	"dioxid de azot": models.PollutantNitrogenDioxide,
+	"dioxid de sulf": models.PollutantSulphurDioxide,
+	"hidrocarburi":   models.PollutantHydrocarbons,
+}
+
+// processEmissions reads the yearly emissions of each pollutant
+func processEmissions() ([]models.Emission, error) {
+	path := filepath.Join(*dataDir, emissionsFile)
+	log.Printf("Reading emissions data from: %s", path)
+
+	table, err := statbank.ReadFile(path, emissionsTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read emissions file: %w", err)
+	}
+
+	var emissions []models.Emission
+	for _, o := range table.Observations {
+		year, ok := o.Int(statbank.DimYear)
+		pollutant, known := emissionPollutants[strings.ToLower(o.Get(dimPollutant))]
+		if !ok || !known {
+			log.Printf("Warning: Skipping observation without a known pollutant or year: %v", o.Dimensions)
+			continue
+		}
+
+		emissions = append(emissions, models.Emission{
+			Pollutant: pollutant,
+			Year:      uint16(year),
+			Value:     o.Value,
+			Status:    models.ValueStatus(o.Status),
+		})
+	}
+	return emissions, nil
+}
+
+// importEmissions inserts the emissions into ClickHouse
+func importEmissions(conn driver.Conn, emissions []models.Emission) error {
+	batch, err := conn.PrepareBatch(context.Background(), `
+		INSERT INTO emissions (pollutant, year, value, status)
+	`)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range emissions {
+		if err := batch.Append(e.Pollutant, e.Year, e.Value, string(e.Status)); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Inserting %d pollutant emissions into ClickHouse...", len(emissions))
+	return batch.Send()
 }
 
 // importDataToClickHouse imports the disease data into ClickHouse
 func importDataToClickHouse(conn driver.Conn, diseases map[string]*Disease) error {
 	batch, err := conn.PrepareBatch(context.Background(), `
 		INSERT INTO diseases (
-			id, name, category, year, quarter, region, 
+			id, name, category, year, quarter, region, age_group,
 			cases, deaths, recoveries, population, 
-			incidence_rate, prevalence_rate, mortality_rate, environment_data
+			incidence_rate, prevalence_rate, mortality_rate, status
 		)
 	`)
 	if err != nil {
@@ -640,25 +1203,19 @@ func importDataToClickHouse(conn driver.Conn, diseases map[string]*Disease) erro
 	}
 
 	count := 0
-	for id, disease := range diseases {
-		// Convert environment data to JSON string
-		environmentJSON, err := json.Marshal(disease.EnvironmentData)
-		if err != nil {
-			log.Printf("Warning: Could not marshal environment data for %s: %v", id, err)
-			environmentJSON = []byte("{}")
-		}
-
+	for _, disease := range diseases {
 		// Debug log to help diagnose issues
 		log.Printf("Adding disease: %s, Year: %d, Quarter: %d", disease.Name, disease.Year, disease.Quarter)
 
 		// Append the data to the batch
-		err = batch.Append(
+		err := batch.Append(
 			disease.ID,
 			disease.Name,
 			disease.Category,
 			disease.Year,
 			disease.Quarter,
 			disease.Region,
+			disease.AgeGroup,
 			disease.Cases,
 			disease.Deaths,
 			disease.Recoveries,
@@ -666,7 +1223,7 @@ func importDataToClickHouse(conn driver.Conn, diseases map[string]*Disease) erro
 			disease.IncidenceRate,
 			disease.PrevalenceRate, // Added prevalence rate
 			disease.MortalityRate,
-			string(environmentJSON),
+			string(disease.Status),
 		)
 		if err != nil {
 			log.Printf("Error appending row for disease %s (%s): %v", disease.Name, disease.ID, err)
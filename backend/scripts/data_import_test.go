@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ktruedat/healthisis/backend/internal/repository"
+)
+
+func TestCategorizeDiseaseUsesDefaultCategories(t *testing.T) {
+	categories := make(map[string]bool)
+	for _, c := range repository.DefaultCategories {
+		categories[c.Name] = true
+	}
+
+	for _, name := range []string{
+		"Hepatita virală B", "Tuberculoza", "Gripa", "Infecții respiratorii acute", "Pneumonii",
+		"HIV/SIDA", "Sifilis", "Infecția gonococică", "Boli diareice acute intestinale", "Salmoneloze",
+		"Dizenterie", "Escherichioze", "Coronavirus (COVID-19)", "Rujeola",
+	} {
+		if category := categorizeDisease(name); !categories[category] {
+			t.Errorf("%q is categorized as %q, which is not a default category", name, category)
+		}
+	}
+}
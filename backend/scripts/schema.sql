@@ -12,18 +12,158 @@ CREATE TABLE IF NOT EXISTS diseases (
     year UInt16,
     quarter UInt8,
     region String,
+    -- all, or children for the cases aged 0-17 among them; never summed together
+    age_group LowCardinality(String) DEFAULT 'all',
     cases UInt32,
     deaths UInt32,
     recoveries UInt32,
     population UInt32,
     incidence_rate Float64,
+    prevalence_rate Float64,
     mortality_rate Float64,
-    environment_data String
+    -- observed, zero ("-"), not_available ("..") or confidential ("C"); suppressed rows have no counts
+    status LowCardinality(String) DEFAULT 'observed'
 ) ENGINE = MergeTree()
 ORDER BY (year, quarter, category, name, region);
 
+-- Create the disease categories table
+CREATE TABLE IF NOT EXISTS categories (
+    id UInt32,
+    name String
+) ENGINE = MergeTree()
+ORDER BY id;
+
+INSERT INTO categories (id, name) VALUES
+    (1, 'Respiratory Infections'),
+    (2, 'Viral Hepatitis'),
+    (3, 'STIs'),
+    (4, 'COVID-19'),
+    (5, 'Intestinal Infections'),
+    (6, 'Other Infectious Diseases');
+
+-- Create the disease alerts table
+CREATE TABLE IF NOT EXISTS alerts (
+    id UInt32,
+    disease_id UInt32,
+    severity LowCardinality(String),
+    message String,
+    created_at DateTime,
+    expires_at Nullable(DateTime)
+) ENGINE = MergeTree()
+ORDER BY (created_at, id);
+
+-- Create the forecast backtest results table; newer runs replace older ones
+CREATE TABLE IF NOT EXISTS forecast_backtests (
+    disease String,
+    model LowCardinality(String),
+    horizon UInt8,
+    level Float64,
+    first_origin_year UInt16,
+    first_origin_quarter UInt8,
+    last_origin_year UInt16,
+    last_origin_quarter UInt8,
+    origins UInt32,
+    skipped_origins UInt32,
+    forecasts UInt32,
+    mae Float64,
+    mape Nullable(Float64),
+    smape Float64,
+    mase Nullable(Float64),
+    coverage Float64,
+    run_at DateTime
+) ENGINE = ReplacingMergeTree(run_at)
+ORDER BY (disease, model, horizon, level);
+
+-- Create the January 1 population table; a revision supersedes the entries it restates
+CREATE TABLE IF NOT EXISTS population (
+    year UInt16,
+    age String,
+    medium LowCardinality(String),
+    sex LowCardinality(String),
+    population UInt32,
+    revised_at DateTime
+) ENGINE = ReplacingMergeTree(revised_at)
+ORDER BY (year, age, medium, sex);
+
+-- Create the table of yearly indicators broken down by age, sex and medium. Ages are those of the
+-- source ("7", "18-29", "85+", "unknown" or "total"); statuses are those of the diseases table.
+CREATE TABLE IF NOT EXISTS stratified_facts (
+    indicator LowCardinality(String),
+    year UInt16,
+    age String,
+    sex LowCardinality(String),
+    medium LowCardinality(String),
+    value Float64,
+    status LowCardinality(String) DEFAULT 'observed'
+) ENGINE = ReplacingMergeTree()
+ORDER BY (indicator, year, age, sex, medium);
+
+-- Create the vaccines table, linking each antigen of the coverage figures to the diseases it
+-- protects against; the importer fills it from repository.DefaultVaccines
+CREATE TABLE IF NOT EXISTS vaccines (
+    antigen String,
+    name String,
+    diseases Array(String)
+) ENGINE = ReplacingMergeTree()
+ORDER BY antigen;
+
+-- Create the yearly vaccination coverage table, in percent of the children of the age ("newborn"
+-- for vaccines given within 30 days of birth, otherwise completed years)
+CREATE TABLE IF NOT EXISTS vaccination_coverage (
+    antigen LowCardinality(String),
+    age LowCardinality(String),
+    year UInt16,
+    coverage Float64,
+    status LowCardinality(String) DEFAULT 'observed'
+) ENGINE = ReplacingMergeTree()
+ORDER BY (antigen, age, year);
+
+-- Create the bed profiles table, linking each hospital bed profile to the disease categories it
+-- treats; the importer fills it from repository.DefaultBedProfiles
+CREATE TABLE IF NOT EXISTS bed_profiles (
+    profile String,
+    name String,
+    categories Array(String)
+) ENGINE = ReplacingMergeTree()
+ORDER BY profile;
+
+-- Create the yearly hospital beds table by bed profile
+CREATE TABLE IF NOT EXISTS hospital_beds (
+    profile LowCardinality(String),
+    year UInt16,
+    beds UInt32,
+    status LowCardinality(String) DEFAULT 'observed'
+) ENGINE = ReplacingMergeTree()
+ORDER BY (profile, year);
+
+-- Create the yearly road-transport pollutant emissions table, in thousand tonnes
+CREATE TABLE IF NOT EXISTS emissions (
+    pollutant LowCardinality(String),
+    year UInt16,
+    value Float64,
+    status LowCardinality(String) DEFAULT 'observed'
+) ENGINE = ReplacingMergeTree()
+ORDER BY (pollutant, year);
+
+-- Create the monthly weather station observations table
+CREATE TABLE IF NOT EXISTS environment_observations (
+    station LowCardinality(String),
+    variable LowCardinality(String),
+    year UInt16,
+    month UInt8,
+    value Float64
+) ENGINE = ReplacingMergeTree()
+ORDER BY (station, variable, year, month);
+
+-- Quarterly rollups of these observations are computed by the API, which sums or averages each
+-- variable as models.EnvironmentVariables prescribes, rather than by a view repeating that rule
+DROP VIEW IF EXISTS environment_quarterly;
+
+-- The views sum the records of all ages only: the children's records repeat cases those records
+-- already count. The views are replaced, so stores created before the filter pick it up.
+
 -- Create a view for easy yearly statistics
-CREATE VIEW IF NOT EXISTS yearly_disease_stats AS
+CREATE OR REPLACE VIEW yearly_disease_stats AS
 SELECT
     year,
     category,
@@ -34,11 +174,12 @@ SELECT
     AVG(incidence_rate) AS avg_incidence_rate,
     AVG(mortality_rate) AS avg_mortality_rate
 FROM diseases
+WHERE age_group = 'all'
 GROUP BY year, category, name
 ORDER BY year DESC, total_cases DESC;
 
 -- Create a view for quarterly trends
-CREATE VIEW IF NOT EXISTS quarterly_trends AS
+CREATE OR REPLACE VIEW quarterly_trends AS
 SELECT
     year,
     quarter,
@@ -46,5 +187,6 @@ SELECT
     SUM(cases) AS total_cases,
     SUM(deaths) AS total_deaths
 FROM diseases
+WHERE age_group = 'all'
 GROUP BY year, quarter, category
 ORDER BY year, quarter, total_cases DESC;
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestSchemaViewsSumAllAgesOnly guards the views over the diseases table against double counting
+// the children's records, which repeat cases the records of all ages already count
+func TestSchemaViewsSumAllAgesOnly(t *testing.T) {
+	schema, err := os.ReadFile("schema.sql")
+	if err != nil {
+		t.Fatalf("reading schema: %v", err)
+	}
+
+	view := regexp.MustCompile(`(?i)CREATE\s+(?:OR\s+REPLACE\s+)?VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)`)
+	views := 0
+	for _, statement := range strings.Split(string(schema), ";") {
+		m := view.FindStringSubmatch(statement)
+		if m == nil || !regexp.MustCompile(`(?i)\bFROM\s+diseases\b`).MatchString(statement) {
+			continue
+		}
+		views++
+		if !strings.Contains(statement, "age_group = 'all'") {
+			t.Errorf("view %s sums the diseases of every age group", m[1])
+		}
+	}
+	if views == 0 {
+		t.Error("no views over the diseases table")
+	}
+}
@@ -17,6 +17,20 @@ export interface Disease {
   category_id?: number;
   description?: string;
   region?: string; // Make region optional
+  ageGroup?: AgeGroup;
+  status?: ValueStatus;
+  environmentData?: Record<string, number>; // with include=environment
+}
+
+// Age group of disease records; children count the cases aged 0-17 among all ages
+export type AgeGroup = 'all' | 'children';
+
+// Status of a source cell; suppressed (not_available, confidential) records have no counts
+export type ValueStatus = 'observed' | 'zero' | 'not_available' | 'confidential';
+
+export interface SuppressedCells {
+  notAvailable: number;
+  confidential: number;
 }
 
 export interface DiseaseInput {
@@ -49,7 +63,7 @@ export interface DashboardSummary {
   totalDiseases?: number;
   totalRegions?: number;
   averageRate: number;
-  trendDirection: 'increasing' | 'decreasing' | 'stable';
+  trendDirection: 'increasing' | 'decreasing' | 'stable' | 'insufficient_data';
   changePercent: number;
   topDiseases?: {
     diseaseId: number;
@@ -62,6 +76,8 @@ export interface DashboardSummary {
     trendPercentage: number;
     direction: 'up' | 'down' | 'stable';
   }[];
+  suppressed?: SuppressedCells;
+  capacity?: CapacitySummary;
 }
 
 // Disease trends
@@ -74,7 +90,10 @@ export interface DiseaseTrends {
     incidenceRate: number;
     mortalityRate: number;
     recoveryRate: number;
+    status: ValueStatus;
+    suppressed: SuppressedCells;
   }[];
+  suppressed: SuppressedCells;
 }
 
 // Map data
@@ -89,15 +108,26 @@ export interface MapData {
 }
 
 // Forecast types
+export type ForecastModel =
+  | 'seasonal_naive'
+  | 'holt_winters_additive'
+  | 'holt_winters_multiplicative'
+  | 'sarima';
+
 export interface ForecastRequest {
-  disease_id: number;
+  disease_id: string;
   year: number;
   quarters?: number[];
+  model?: ForecastModel;
+  level?: number;
 }
 
 export interface ForecastResponse {
-  disease_id: number;
+  disease_id: string;
   year: number;
+  model: string;
+  level: number;
+  quarters: number[];
   forecast: number[];
   confidence_intervals?: {
     lower: number;
@@ -113,16 +143,167 @@ export interface CorrelationRequest {
     start_date: string;
     end_date: string;
   };
+  max_lag?: number;
+  lag_unit?: 'quarter' | 'month';
+}
+
+export interface LagCorrelation {
+  lag: number;
+  coefficient: number;
+  sample_size: number;
+  band: number;
+  significant: boolean;
+}
+
+export interface CrossCorrelation {
+  lags: LagCorrelation[];
+  best_lag: LagCorrelation | null;
+}
+
+export interface LaggedCorrelationResult {
+  factor1: string;
+  factor2: string;
+  lag_unit: 'quarter' | 'month';
+  max_lag: number;
+  level: number;
+  raw: CrossCorrelation;
+  prewhitened: CrossCorrelation;
+  prewhiten_order: number;
+}
+
+export interface CorrelationStat {
+  coefficient: number;
+  p_value: number;
+  confidence_interval: {
+    lower: number;
+    upper: number;
+  };
 }
 
 export interface CorrelationResult {
+  factor1?: string;
+  factor2?: string;
   correlation_coefficient: number;
   p_value: number;
   confidence_interval: {
     lower: number;
     upper: number;
   };
-  visualization_data: any;
+  pearson?: CorrelationStat;
+  spearman?: CorrelationStat;
+  sample_size: number;
+  level?: number;
+  granularity?: 'quarter' | 'year'; // year when either factor is an emission
+  visualization_data: {
+    year: number;
+    quarter?: number; // absent from yearly correlations
+    x: number;
+    y: number;
+  }[];
+}
+
+export interface CorrelationMatrixRequest {
+  diseases?: string[];
+  categories?: string[];
+  factors?: string[];
+  timeframe?: {
+    start_date: string;
+    end_date: string;
+  };
+  method?: 'pearson' | 'spearman';
+  cluster?: boolean;
+}
+
+export interface CorrelationMatrix {
+  series: string[];
+  method: 'pearson' | 'spearman';
+  granularity: 'quarter' | 'year';
+  coefficients: (number | null)[][];
+  p_values: (number | null)[][];
+  adjusted_p_values: (number | null)[][];
+  sample_sizes: number[][];
+  clustered: boolean;
+}
+
+export type StandardPopulation = 'esp2013' | 'who' | 'moldova';
+
+export interface StandardizedRatesRequest {
+  indicator: string;
+  standard?: StandardPopulation;
+  reference_year?: number;
+  start_year?: number;
+  end_year?: number;
+  sexes?: Array<'male' | 'female'>;
+  media?: Array<'urban' | 'rural'>;
+  group_by?: Array<'sex' | 'medium'>;
+  level?: number;
+}
+
+export interface StandardizedRate {
+  year: number;
+  sex?: 'male' | 'female';
+  medium?: 'urban' | 'rural';
+  events: number;
+  population: number;
+  crude_rate: number;
+  standardized_rate: number;
+  confidence_interval: {
+    lower: number;
+    upper: number;
+  };
+  unknown_age_events: number;
+  suppressed: SuppressedCells;
+}
+
+export interface StandardizedRates {
+  indicator: string;
+  standard: StandardPopulation;
+  reference_year?: number;
+  age_groups: string[];
+  weights: number[];
+  level: number;
+  rates: StandardizedRate[];
+}
+
+export interface VaccinationRequest {
+  antigens?: string[];
+  start_year?: number;
+  end_year?: number;
+  target?: number;
+  max_lag?: number;
+}
+
+export interface CoveragePoint {
+  year: number;
+  coverage: number;
+  status: ValueStatus;
+  below_target: boolean;
+}
+
+export interface AntigenCoverage {
+  antigen: string;
+  name: string;
+  age: string;
+  diseases: string[];
+  points: CoveragePoint[];
+  latest: CoveragePoint | null;
+  below_target: boolean;
+  years_below_target: number;
+  trend: number | null;
+  incidence: Array<{
+    disease: string;
+    points: Array<{ year: number; cases: number; incidence_rate: number | null }>;
+    lags: LagCorrelation[];
+    best_lag: LagCorrelation | null;
+  }>;
+}
+
+export interface VaccinationAnalysis {
+  target: number;
+  max_lag: number;
+  level: number;
+  antigens: AntigenCoverage[];
+  below_target: string[];
 }
 
 // Query types
@@ -147,6 +328,7 @@ export interface DiseaseFilters {
   quarters?: number[];
   regions?: string[];
   categories?: string[];
+  ageGroup?: AgeGroup; // defaults to all
   diseaseIds?: string[];
   minCases?: number;
   maxCases?: number;
@@ -154,4 +336,311 @@ export interface DiseaseFilters {
   sortOrder?: 'asc' | 'desc';
   limit?: number;
   offset?: number;
+  include?: 'environment'[];
+}
+
+// Paediatric breakdown: children against adults (all ages less children)
+export interface AgeSegmentPoint {
+  year: number;
+  quarter: number;
+  allAges: number;
+  children: number;
+  adults: number;
+  paediatricShare: number | null;
+  status: ValueStatus;
+  suppressed: SuppressedCells;
+}
+
+export interface AgeSegmentSeries {
+  name?: string; // absent on the total
+  points: AgeSegmentPoint[];
+}
+
+export interface PaediatricBreakdown {
+  diseases: AgeSegmentSeries[];
+  total: AgeSegmentSeries;
+  suppressed: SuppressedCells;
+}
+
+// Environment types
+export interface EnvironmentPoint {
+  year: number;
+  quarter: number;
+  month?: number;
+  factor: string;
+  value: number;
+}
+
+export interface EnvironmentSeries {
+  station: string;
+  variable: string;
+  unit?: string;
+  aggregation: 'mean' | 'sum';
+  granularity: 'quarter' | 'month';
+  points: EnvironmentPoint[];
+}
+
+export interface EnvironmentFilters {
+  variables?: string[];
+  stations?: string[];
+  startYear?: number;
+  endYear?: number;
+  quarters?: number[];
+  granularity?: 'quarter' | 'month';
+}
+
+export interface Station {
+  name: string;
+  variables: string[];
+  firstYear: number;
+  lastYear: number;
+}
+
+// Yearly road-transport emissions, correlated as "emission:<pollutant>" factors
+export type Pollutant = 'carbon_monoxide' | 'nitrogen_dioxide' | 'sulphur_dioxide' | 'hydrocarbons';
+
+export interface EmissionPoint {
+  year: number;
+  value: number; // thousand tonnes
+  status: ValueStatus;
+}
+
+export interface EmissionSeries {
+  pollutant: Pollutant;
+  unit: string;
+  points: EmissionPoint[];
+}
+
+export interface EmissionFilters {
+  pollutants?: Pollutant[];
+  startYear?: number;
+  endYear?: number;
+}
+
+export interface PopulationEntry {
+  year: number;
+  age: string; // "0" to "84", "85+" or "total"
+  medium: 'urban' | 'rural';
+  sex: 'male' | 'female';
+  population: number;
+}
+
+export interface PopulationFilters {
+  startYear?: number;
+  endYear?: number;
+  ages?: string[];
+  media?: Array<'urban' | 'rural'>;
+  sexes?: Array<'male' | 'female'>;
+}
+
+export interface PopulationDenominator {
+  year: number;
+  january1: number;
+  midYear: number;
+  interpolated: boolean;
+}
+
+export interface PopulationRevision {
+  entries: number;
+  years: number[];
+  rebasedRecords: number;
+}
+
+export type StratifiedIndicator = 'population' | 'deaths' | 'life_expectancy' | 'disability';
+
+export type StratifiedDimension = 'age' | 'sex' | 'medium';
+
+export interface StratifiedFilters {
+  indicator?: StratifiedIndicator;
+  startYear?: number;
+  endYear?: number;
+  ages?: string[];
+  sexes?: Array<'male' | 'female'>;
+  media?: Array<'urban' | 'rural'>;
+  groupBy?: StratifiedDimension[];
+  bandWidth?: number;
+  openAge?: number;
+}
+
+export interface StratifiedSeries {
+  indicator: StratifiedIndicator;
+  age?: string;
+  sex?: 'male' | 'female';
+  medium?: 'urban' | 'rural';
+  points: Array<{
+    year: number;
+    value: number;
+    status: ValueStatus;
+    suppressed: SuppressedCells;
+  }>;
+}
+
+export interface PyramidBar {
+  age: string;
+  male: number;
+  female: number;
+  malePercent: number;
+  femalePercent: number;
+}
+
+export interface Pyramid {
+  indicator: StratifiedIndicator;
+  year: number;
+  media: string[];
+  total: number;
+  bars: PyramidBar[];
+  suppressed: SuppressedCells;
+}
+
+export interface LifeExpectancySeries {
+  age: string;
+  sex: 'male' | 'female';
+  medium: 'urban' | 'rural';
+  points: Array<{
+    year: number;
+    official: number | null;
+    computed: number | null;
+    difference: number | null;
+  }>;
+}
+
+export interface LifeTableRow {
+  age: string;
+  deaths: number;
+  population: number;
+  mx: number;
+  ax: number;
+  qx: number;
+  lx: number;
+  dx: number;
+  personYears: number;
+  tx: number;
+  ex: number;
+  officialLifeExpectancy?: number;
+}
+
+export interface LifeTable {
+  year: number;
+  sex?: 'male' | 'female';
+  medium?: 'urban' | 'rural';
+  lifeExpectancy: number;
+  unknownAgeDeaths: number;
+  rows: LifeTableRow[];
+}
+
+export interface LifeExpectancyDecomposition {
+  fromYear: number;
+  toYear: number;
+  sex?: 'male' | 'female';
+  medium?: 'urban' | 'rural';
+  fromLifeExpectancy: number;
+  toLifeExpectancy: number;
+  change: number;
+  contributions: Array<{
+    age: string;
+    direct: number;
+    indirect: number;
+    total: number;
+  }>;
+}
+
+// Hospital capacity types
+export interface BedProfile {
+  profile: string;
+  name: string;
+  categories: string[];
+}
+
+export interface CapacityPoint {
+  year: number;
+  beds: number;
+  bedsPer10k: number | null;
+  status: ValueStatus;
+  suppressed: SuppressedCells;
+}
+
+export interface CapacitySeries {
+  profile: string; // 'total' for the sum of the selected profiles
+  name: string;
+  points: CapacityPoint[];
+}
+
+export interface BurdenPoint {
+  year: number;
+  beds: number;
+  bedsPer10k: number | null;
+  cases: number;
+  casesPerBed: number | null;
+  suppressed: SuppressedCells;
+}
+
+export interface CapacityBurden {
+  profile: string;
+  name: string;
+  categories: string[];
+  points: BurdenPoint[];
+  correlation: {
+    coefficient: number;
+    pValue: number;
+    lower: number;
+    upper: number;
+    sampleSize: number;
+  } | null;
+}
+
+export interface CapacitySummary {
+  year: number;
+  beds: number;
+  bedsPer10k: number | null;
+  burden: Array<BurdenPoint & { profile: string; name: string }>;
+}
+
+// Disability: adults recognised with a primary disability per 10k adults of the stratum
+export type DisabilityAge = '18-29' | '30-39' | '40-49' | '50+';
+
+export interface DisabilityFilters {
+  startYear?: number;
+  endYear?: number;
+  ages?: DisabilityAge[];
+  sexes?: Array<'male' | 'female'>;
+  media?: Array<'urban' | 'rural'>;
+  groupBy?: StratifiedDimension[];
+  sort?: string; // e.g. "-ratePer10k,year"
+  sortBy?: string;
+  sortOrder?: 'asc' | 'desc';
+  limit?: number;
+  offset?: number;
+}
+
+export interface DisabilityPoint {
+  year: number;
+  persons: number;
+  adults: number;
+  ratePer10k: number | null;
+  change: number | null; // rate difference from the year before
+  changePercent: number | null;
+  status: ValueStatus;
+  suppressed: SuppressedCells;
+}
+
+export interface DisabilityRate extends DisabilityPoint {
+  age?: DisabilityAge;
+  sex?: 'male' | 'female';
+  medium?: 'urban' | 'rural';
+}
+
+export interface DisabilityPage {
+  rates: DisabilityRate[];
+  totalCount: number;
+  page: number;
+  totalPages: number;
+  limit: number;
+  hasMore: boolean;
+}
+
+export interface DisabilitySeries {
+  age?: DisabilityAge;
+  sex?: 'male' | 'female';
+  medium?: 'urban' | 'rural';
+  points: DisabilityPoint[];
 }